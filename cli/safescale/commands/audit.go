@@ -0,0 +1,65 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package commands
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var auditCmdName = "audit"
+
+// AuditCmd command
+var AuditCmd = cli.Command{
+	Name:  "audit",
+	Usage: "audit COMMAND",
+	Subcommands: []cli.Command{
+		auditList,
+	},
+}
+
+var auditList = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List the mutating operations done on the current tenant, the oldest first",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "since",
+			Usage: "Only list operations done since this date (RFC3339 format, ie 2020-01-31T12:00:00Z) or this duration (ie 24h)",
+		},
+		cli.StringFlag{
+			Name:  "resource",
+			Usage: "Only list operations done on this kind of resource (host, network, volume, share, bucket, ssh, tenant, job)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", auditCmdName, c.Command.Name, c.Args())
+		since, err := parseSince(c.String("since"))
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument(err.Error()))
+		}
+		events, err := client.New().Audit.List(since, c.String("resource"), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of audit events", false).Error())))
+		}
+		return clitools.SuccessResponse(events.GetEvents())
+	},
+}
@@ -350,8 +350,12 @@ var clusterCreateCommand = cli.Command{
 		},
 		cli.StringFlag{
 			Name:  "cidr, N",
-			Value: "192.168.0.0/16",
-			Usage: "Defines the CIDR of the network to use with cluster",
+			Usage: "Defines the CIDR of the network to use with cluster; if not set, a free CIDR is allocated from the address pool of the tenant",
+		},
+		cli.IntFlag{
+			Name:  "subnet-size",
+			Value: 22,
+			Usage: "Defines the prefix length of the CIDR allocated when --cidr is not set",
 		},
 		cli.StringFlag{
 			Name: "domain",
@@ -373,7 +377,7 @@ var clusterCreateCommand = cli.Command{
 		cli.StringFlag{
 			Name: "sizing",
 			Usage: `Describe sizing for any type of host in format "<component><operator><value>[,...]" where:
-	<component> can be cpu, cpufreq, gpu, ram, disk
+	<component> can be cpu, cpufreq, gpu, ram, disk, disk-speed, net-speed
 	<operator> can be =,~,<,<=,>,>= (except for disk where valid operators are only = or >=):
 		- = means exactly <value>
 		- ~ means between <value> and 2*<value>
@@ -383,14 +387,17 @@ var clusterCreateCommand = cli.Command{
 		- >= means greater or equal to <value>
 	<value> can be an integer (for cpu and disk) or a float (for ram) or an including interval "[<lower value>-<upper value>]:"
 		- <cpu> is expecting an int as number of cpu cores, or an interval with minimum and maximum number of cpu cores
-		- <cpufreq> is expecting an int as minimum cpu frequency in MHz
+		- <cpufreq> is expecting a float as minimum cpu frequency in GHz, or in MHz when followed by MHz (scanner would have been run first)
 		- <gpu> is expecting an int as number of GPU (scanner would have been run first to be able to determine which template proposes GPU)
 		- <ram> is expecting a float as memory size in GB, or an interval with minimum and maximum memory size
 		- <disk> is expecting an int as system disk size in GB
+		- <disk-speed> is expecting a float as minimum speed of the system disk in MB/s, or in GB/s when followed by GBps (scanner would have been run first)
+		- <net-speed> is expecting a float as minimum network speed in KB/s, or in MB/s when followed by MBps (scanner would have been run first)
 	examples:
 		--sizing "cpu <= 4, ram <= 10, disk = 100"
 		--sizing "cpu ~ 4, ram = [14-32]" (is identical to --sizing "cpu=[4-8], ram=[14-32]")
 		--sizing "cpu <= 8, ram ~ 16"
+		--sizing "cpu >= 4, disk-speed >= 200MBps, cpufreq >= 2.5"
 	Can be used with --gw-sizing and friends to set a global host sizing and refine for a particular type of host.
 `,
 		},
@@ -406,6 +413,18 @@ var clusterCreateCommand = cli.Command{
 			Name:  "node-sizing",
 			Usage: `Describe node sizing in format "<component><operator><value>[,...]" (cf. --sizing for details)`,
 		},
+		cli.BoolFlag{
+			Name:  "node-spot",
+			Usage: "Create nodes as spot (or preemptible) hosts; nodes reclaimed by the provider are replaced by safescaled",
+		},
+		cli.BoolFlag{
+			Name:  "cheapest",
+			Usage: "Select for gateways, masters and nodes the cheapest templates fulfilling the sizings, instead of the smallest ones",
+		},
+		cli.StringFlag{
+			Name:  "user-data",
+			Usage: "File containing a script run on masters and nodes once configured by SafeScale, including nodes added later by expand",
+		},
 		cli.UintFlag{
 			Name:  "cpu",
 			Usage: "DEPRECATED! use --sizing and friends instead! Defines the number of cpu of masters and nodes in the cluster",
@@ -458,6 +477,11 @@ var clusterCreateCommand = cli.Command{
 			los = ""
 		}
 
+		userData, err := readUserData(c)
+		if err != nil {
+			return err
+		}
+
 		var (
 			gatewaysDef *pb.HostDefinition
 			mastersDef  *pb.HostDefinition
@@ -513,12 +537,27 @@ var clusterCreateCommand = cli.Command{
 				mastersDef = gatewaysDef         // ... nor for masters
 			}
 		}
+		if c.Bool("node-spot") {
+			// nodesDef may be shared with gateways and masters, which must not be spot hosts
+			if nodesDef == nil {
+				nodesDef = &pb.HostDefinition{Sizing: &pb.HostSizing{}}
+			} else {
+				nodesDef = nodesDef.Clone()
+			}
+			nodesDef.Sizing.Replaceable = true
+		}
+		if c.Bool("cheapest") {
+			gatewaysDef = cheapestHostDefinition(gatewaysDef)
+			mastersDef = cheapestHostDefinition(mastersDef)
+			nodesDef = cheapestHostDefinition(nodesDef)
+		}
 		clusterInstance, err := cluster.Create(
 			concurrency.RootTask(),
 			control.Request{
 				Name:                    clusterName,
 				Complexity:              clusterComplexity,
 				CIDR:                    cidr,
+				SubnetSize:              c.Int("subnet-size"),
 				Domain: domain,
 				Flavor:                  clusterFlavor,
 				KeepOnFailure:           keep,
@@ -526,6 +565,7 @@ var clusterCreateCommand = cli.Command{
 				MastersDef:              mastersDef,
 				NodesDef:                nodesDef,
 				DisabledDefaultFeatures: disableFeatures,
+				UserData:                userData,
 				},
 		)
 		if err != nil {
@@ -556,6 +596,19 @@ var clusterCreateCommand = cli.Command{
 	},
 }
 
+// cheapestHostDefinition sets the cheapest selection policy in def, creating it if needed; the missing parts of the
+// definition are filled with the defaults of the cluster
+func cheapestHostDefinition(def *pb.HostDefinition) *pb.HostDefinition {
+	if def == nil {
+		return &pb.HostDefinition{Sizing: &pb.HostSizing{Cheapest: true}}
+	}
+	if def.Sizing == nil {
+		def.Sizing = &pb.HostSizing{}
+	}
+	def.Sizing.Cheapest = true
+	return def
+}
+
 // clusterDeleteCmd handles 'deploy cluster <clustername> delete'
 var clusterDeleteCommand = cli.Command{
 	Name:      "delete",
@@ -19,6 +19,7 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"strconv"
 	"strings"
 
@@ -51,6 +52,7 @@ var HostCmd = cli.Command{
 		hostStatus,
 		hostSSH,
 		hostReboot,
+		hostConsoleLog,
 		hostStart,
 		hostStop,
 		hostCheckFeatureCommand,
@@ -118,6 +120,26 @@ var hostReboot = cli.Command{
 	},
 }
 
+var hostConsoleLog = cli.Command{
+	Name:      "console-log",
+	Usage:     "Displays the console output of a Host, available even when SSH is not",
+	ArgsUsage: "<Host_name|Host_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", hostCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Host_name>."))
+		}
+
+		hostRef := c.Args().First()
+		consoleLog, err := client.New().Host.ConsoleLog(hostRef, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "console log of host", false).Error())))
+		}
+		return clitools.SuccessResponse(consoleLog)
+	},
+}
+
 var hostList = cli.Command{
 	Name:    "list",
 	Aliases: []string{"ls"},
@@ -194,7 +216,7 @@ var hostCreate = cli.Command{
 		cli.StringFlag{
 			Name:  "net,network",
 			Value: "",
-			Usage: "network name or network id",
+			Usage: "network name or network id; several networks may be given separated by commas, the first one being the default network",
 		},
 		cli.StringFlag{
 			Name:  "domain",
@@ -213,10 +235,39 @@ var hostCreate = cli.Command{
 			Name:  "f, force",
 			Usage: "Force creation even if the host doesn't meet the GPU and CPU freq requirements",
 		},
+		cli.BoolFlag{
+			Name:  "async",
+			Usage: "Submit the creation and return immediately the UUID of the job (use 'safescale job inspect' to follow it)",
+		},
+		cli.StringFlag{
+			Name:  "user-data",
+			Usage: "File containing a script run on the host once configured by SafeScale (CA certificates, proxies, agents, ...)",
+		},
+		cli.BoolFlag{
+			Name:  "spot",
+			Usage: "Create a spot (or preemptible) host, cheaper but that may be reclaimed by the provider without notice",
+		},
+		cli.BoolFlag{
+			Name:  "cheapest",
+			Usage: "Select the cheapest template fulfilling the sizing, instead of the smallest one (needs prices, from the provider or a price file)",
+		},
+		cli.DurationFlag{
+			Name:  "expires",
+			Usage: "Delay after which the host expires (ex: 72h); users are warned 24h before, then the host is stopped or deleted (see --on-expiry)",
+		},
+		cli.StringFlag{
+			Name:  "on-expiry",
+			Value: "stop",
+			Usage: "What is done with the host once expired: 'stop' or 'delete'",
+		},
+		cli.DurationFlag{
+			Name:  "idle-stop",
+			Usage: "Delay of inactivity (low load) after which the host is stopped (ex: 2h)",
+		},
 		cli.StringFlag{
 			Name: "S, sizing",
 			Usage: `Describe sizing of host in format "<component><operator><value>[,...]" where:
-			<component> can be cpu, cpufreq, gpu, ram, disk
+			<component> can be cpu, cpufreq, gpu, ram, disk, disk-speed, net-speed
 			<operator> can be =,~,<=,>= (except for disk where valid operators are only = or >=):
 				- = means exactly <value>
 				- ~ means between <value> and 2*<value>
@@ -226,14 +277,17 @@ var hostCreate = cli.Command{
 				- >= means greater or equal to <value>
 			<value> can be an integer (for cpu and disk) or a float (for ram) or an including interval "[<lower value>-<upper value>]:"
 				- <cpu> is expecting an int as number of cpu cores, or an interval with minimum and maximum number of cpu cores
-				- <cpufreq> is expecting an int as minimum cpu frequency in MHz
+				- <cpufreq> is expecting a float as minimum cpu frequency in GHz, or in MHz when followed by MHz (scanner would have been run first)
 				- <gpu> is expecting an int as number of GPU (scanner would have been run first to be able to determine which template proposes GPU)
 				- <ram> is expecting a float as memory size in GB, or an interval with minimum and maximum mmory size
 				- <disk> is expecting an int as system disk size in GB
+				- <disk-speed> is expecting a float as minimum speed of the system disk in MB/s, or in GB/s when followed by GBps (scanner would have been run first)
+				- <net-speed> is expecting a float as minimum network speed in KB/s, or in MB/s when followed by MBps (scanner would have been run first)
 			examples:
 				--sizing "cpu <= 4, ram <= 10, disk >= 100"
 				--sizing "cpu ~ 4, ram = [14-32]" (is identical to --sizing "cpu=[4-8], ram=[14-32]")
 				--sizing "cpu <= 8, ram ~ 16"
+				--sizing "cpu >= 4, disk-speed >= 200MBps, cpufreq >= 2.5"
 `,
 		},
 		cli.UintFlag{
@@ -280,6 +334,36 @@ var hostCreate = cli.Command{
 		if err != nil {
 			return err
 		}
+		def.UserData, err = readUserData(c)
+		if err != nil {
+			return err
+		}
+		if c.Bool("spot") {
+			if def.Sizing == nil {
+				def.Sizing = &pb.HostSizing{}
+			}
+			def.Sizing.Replaceable = true
+		}
+		if c.Bool("cheapest") {
+			if def.Sizing == nil {
+				def.Sizing = &pb.HostSizing{}
+			}
+			def.Sizing.Cheapest = true
+		}
+		if c.IsSet("expires") || c.IsSet("idle-stop") {
+			def.Lifecycle = &pb.HostLifecycle{
+				ExpiresIn: int64(c.Duration("expires").Seconds()),
+				OnExpiry:  c.String("on-expiry"),
+				IdleStop:  int64(c.Duration("idle-stop").Seconds()),
+			}
+		}
+		if c.Bool("async") {
+			uuid, err := client.New().Host.CreateAsync(def, temporal.GetExecutionTimeout())
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "submission of host creation", false).Error())))
+			}
+			return clitools.SuccessResponse(asyncResponse(uuid))
+		}
 		resp, err := client.New().Host.Create(def, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "creation of host", true).Error())))
@@ -632,6 +716,19 @@ var hostDeleteFeatureCommand = cli.Command{
 	},
 }
 
+// readUserData returns the content of the file given by --user-data, if any
+func readUserData(c *cli.Context) (string, error) {
+	path := c.String("user-data")
+	if path == "" {
+		return "", nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("failed to read user data file '%s': %s", path, err.Error())))
+	}
+	return string(content), nil
+}
+
 // constructPBHostDefinitionFromCLI ...
 func constructPBHostDefinitionFromCLI(c *cli.Context, key string) (*pb.HostDefinition, error) {
 	var sizing string
@@ -640,6 +737,13 @@ func constructPBHostDefinitionFromCLI(c *cli.Context, key string) (*pb.HostDefin
 			return nil, clitools.FailureResponse(clitools.ExitOnInvalidArgument("cannot use simultaneously --sizing and --cpu|--cpufreq|--gpu|--ram|--disk"))
 		}
 		sizing = c.String(key)
+		if strings.HasPrefix(sizing, "@") {
+			var err error
+			sizing, err = presetSizing(sizing)
+			if err != nil {
+				return nil, err
+			}
+		}
 	} else {
 		if c.IsSet("cpu") {
 			sizing = fmt.Sprintf("cpu ~ %d,", c.Int("cpu"))
@@ -662,14 +766,26 @@ func constructPBHostDefinitionFromCLI(c *cli.Context, key string) (*pb.HostDefin
 		return nil, clitools.FailureResponse(clitools.ExitOnInvalidArgument(err.Error()))
 	}
 
+	var networks []string
+	for _, n := range strings.Split(c.String("net"), ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			networks = append(networks, n)
+		}
+	}
+	network := ""
+	if len(networks) > 0 {
+		network = networks[0]
+	}
+
 	def := pb.HostDefinition{
-		Name:    c.Args().First(),
-		ImageId: c.String("os"),
-		Network: c.String("net"),
-		Public:  c.Bool("public"),
-		Force:   c.Bool("force"),
-		Domain: c.String("domain"),
-		Sizing:  &pb.HostSizing{},
+		Name:     c.Args().First(),
+		ImageId:  c.String("os"),
+		Network:  network,
+		Networks: networks,
+		Public:   c.Bool("public"),
+		Force:    c.Bool("force"),
+		Domain:   c.String("domain"),
+		Sizing:   &pb.HostSizing{},
 	}
 	if t, ok := tokens["cpu"]; ok {
 		min, max, err := t.Validate()
@@ -691,8 +807,34 @@ func constructPBHostDefinitionFromCLI(c *cli.Context, key string) (*pb.HostDefin
 			return nil, clitools.FailureResponse(clitools.ExitOnInvalidArgument(err.Error()))
 		}
 		if min != "" {
-			val, _ := strconv.ParseFloat(min, 64)
-			def.Sizing.MinCpuFreq = float32(val)
+			def.Sizing.MinCpuFreq, err = sizingValue(t, min, map[string]float32{"": 1, "ghz": 1, "mhz": 0.001})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if t, ok := tokens["disk-speed"]; ok {
+		min, _, err := t.Validate()
+		if err != nil {
+			return nil, clitools.FailureResponse(clitools.ExitOnInvalidArgument(err.Error()))
+		}
+		if min != "" {
+			def.Sizing.MinDiskSpeed, err = sizingValue(t, min, map[string]float32{"": 1, "mbps": 1, "gbps": 1000})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if t, ok := tokens["net-speed"]; ok {
+		min, _, err := t.Validate()
+		if err != nil {
+			return nil, clitools.FailureResponse(clitools.ExitOnInvalidArgument(err.Error()))
+		}
+		if min != "" {
+			def.Sizing.MinNetSpeed, err = sizingValue(t, min, map[string]float32{"": 1, "kbps": 1, "mbps": 1000})
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 	if t, ok := tokens["gpu"]; ok {
@@ -733,3 +875,18 @@ func constructPBHostDefinitionFromCLI(c *cli.Context, key string) (*pb.HostDefin
 	}
 	return &def, nil
 }
+
+// sizingValue converts the value of a sizing token to the unit expected by safescaled, using the factors of the
+// units accepted by the token (the factor of "" is used when no unit is given)
+func sizingValue(t *clitools.Token, value string, units map[string]float32) (float32, error) {
+	factor, ok := units[t.GetUnit()]
+	if !ok {
+		keyword, _ := t.GetKeyword()
+		return 0, clitools.FailureResponse(clitools.ExitOnInvalidArgument(fmt.Sprintf("invalid unit '%s' for '%s'", t.GetUnit(), keyword)))
+	}
+	val, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, clitools.FailureResponse(clitools.ExitOnInvalidArgument(err.Error()))
+	}
+	return float32(val) * factor, nil
+}
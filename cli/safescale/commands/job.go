@@ -0,0 +1,146 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var jobCmdName = "job"
+
+// JobCmd command
+var JobCmd = cli.Command{
+	Name:  "job",
+	Usage: "job COMMAND",
+	Subcommands: []cli.Command{
+		jobList,
+		jobInspect,
+		jobHistory,
+		jobStop,
+	},
+}
+
+var jobList = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List running jobs",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", jobCmdName, c.Command.Name, c.Args())
+		jobs, err := client.New().JobManager.List(temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of jobs", false).Error())))
+		}
+		return clitools.SuccessResponse(jobs.GetList())
+	},
+}
+
+var jobInspect = cli.Command{
+	Name:      "inspect",
+	Aliases:   []string{"show", "status"},
+	Usage:     "Get status, error and result of a job, running or ended",
+	ArgsUsage: "<Job_UUID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", jobCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Job_UUID>."))
+		}
+		job, err := client.New().JobManager.Inspect(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "inspection of job", false).Error())))
+		}
+		return clitools.SuccessResponse(job)
+	},
+}
+
+var jobHistory = cli.Command{
+	Name:  "history",
+	Usage: "List jobs recorded by safescaled, the most recent first",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "since",
+			Usage: "Only list jobs started since this date (RFC3339 format, ie 2020-01-31T12:00:00Z) or this duration (ie 24h)",
+		},
+		cli.IntFlag{
+			Name:  "limit",
+			Value: 0,
+			Usage: "Maximum number of jobs to list (0 means no limit)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", jobCmdName, c.Command.Name, c.Args())
+		since, err := parseSince(c.String("since"))
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument(err.Error()))
+		}
+		history, err := client.New().JobManager.History(since, c.Int("limit"), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "history of jobs", false).Error())))
+		}
+		return clitools.SuccessResponse(history.GetJobs())
+	},
+}
+
+var jobStop = cli.Command{
+	Name:      "stop",
+	Aliases:   []string{"cancel", "abort"},
+	Usage:     "Stop a running job",
+	ArgsUsage: "<Job_UUID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", jobCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Job_UUID>."))
+		}
+		err := client.New().JobManager.Stop(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "stop of job", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+// parseSince converts a date in RFC3339 format or a duration to a date in RFC3339 format
+func parseSince(since string) (string, error) {
+	if since == "" {
+		return "", nil
+	}
+	if d, err := time.ParseDuration(since); err == nil {
+		return time.Now().Add(-d).Format(time.RFC3339), nil
+	}
+	if _, err := time.Parse(time.RFC3339, since); err != nil {
+		return "", fmt.Errorf("invalid value '%s' for --since: must be a date in RFC3339 format or a duration", since)
+	}
+	return since, nil
+}
+
+// asyncResponse is the result displayed when a command is submitted asynchronously
+func asyncResponse(uuid string) map[string]interface{} {
+	return map[string]interface{}{
+		"job":    uuid,
+		"status": "submitted",
+	}
+}
@@ -18,6 +18,7 @@ package commands
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -41,6 +42,11 @@ var NetworkCmd = cli.Command{
 		networkDelete,
 		networkInspect,
 		networkList,
+		networkPeer,
+		networkUnpeer,
+		networkRepair,
+		networkVPNCmd,
+		networkDNSCmd,
 	},
 }
 
@@ -157,6 +163,241 @@ var networkInspect = cli.Command{
 	},
 }
 
+var networkPeer = cli.Command{
+	Name:      "peer",
+	Usage:     "peer NETWORK PEER_NETWORK",
+	ArgsUsage: "<network_name> <peer_network_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", networkCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <network_name> or <peer_network_name>."))
+		}
+
+		err := client.New().Network.Peer(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "peering of networks", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var networkUnpeer = cli.Command{
+	Name:      "unpeer",
+	Usage:     "unpeer NETWORK PEER_NETWORK",
+	ArgsUsage: "<network_name> <peer_network_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", networkCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <network_name> or <peer_network_name>."))
+		}
+
+		err := client.New().Network.Unpeer(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "unpeering of networks", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var networkRepair = cli.Command{
+	Name:      "repair",
+	Usage:     "Recreates the dead gateway(s) of a network created with failover, without touching its hosts",
+	ArgsUsage: "<network_name>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "os",
+			Usage: "Image name for the recreated gateway(s) (default: the image the gateways were created with)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", networkCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <network_name>."))
+		}
+
+		network, err := client.New().Network.Repair(c.Args().First(), c.String("os"), temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "repair of network", true).Error())))
+		}
+		return clitools.SuccessResponse(network)
+	},
+}
+
+var networkVPNCmd = cli.Command{
+	Name:  "vpn",
+	Usage: "vpn COMMAND",
+	Subcommands: []cli.Command{
+		networkVPNAdd,
+		networkVPNRemove,
+		networkVPNInspect,
+	},
+}
+
+var networkVPNAdd = cli.Command{
+	Name:      "add",
+	Usage:     "Connects a remote site to the network through a WireGuard VPN on the gateway(s)",
+	ArgsUsage: "<network_name>",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "peer",
+			Usage: "Name of the remote site",
+		},
+		cli.StringFlag{
+			Name:  "public-key",
+			Usage: "WireGuard public key of the remote site",
+		},
+		cli.StringFlag{
+			Name:  "allowed-ips",
+			Usage: "CIDR(s) of the remote site routed through the VPN, separated by commas",
+		},
+		cli.StringFlag{
+			Name:  "endpoint",
+			Usage: "Public address and port of the remote site (optional if the remote site initiates the connection)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", networkCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <network_name>."))
+		}
+		if c.String("peer") == "" || c.String("public-key") == "" || c.String("allowed-ips") == "" {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("Missing mandatory option --peer, --public-key or --allowed-ips."))
+		}
+
+		var allowedIPs []string
+		for _, v := range strings.Split(c.String("allowed-ips"), ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				allowedIPs = append(allowedIPs, v)
+			}
+		}
+		peer := &pb.NetworkVPNPeer{
+			Name:       c.String("peer"),
+			PublicKey:  c.String("public-key"),
+			Endpoint:   c.String("endpoint"),
+			AllowedIps: allowedIPs,
+		}
+		vpn, err := client.New().Network.AddVPNPeer(c.Args().First(), peer, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "addition of VPN peer", false).Error())))
+		}
+		return clitools.SuccessResponse(vpn)
+	},
+}
+
+var networkVPNRemove = cli.Command{
+	Name:      "remove",
+	Aliases:   []string{"rm", "delete"},
+	Usage:     "Disconnects a remote site from the VPN of the network",
+	ArgsUsage: "<network_name> <peer_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", networkCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <network_name> or <peer_name>."))
+		}
+
+		vpn, err := client.New().Network.RemoveVPNPeer(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "removal of VPN peer", false).Error())))
+		}
+		return clitools.SuccessResponse(vpn)
+	},
+}
+
+var networkVPNInspect = cli.Command{
+	Name:      "inspect",
+	Aliases:   []string{"show"},
+	Usage:     "Shows the VPN configuration of the network, with what the remote sites need to configure their side",
+	ArgsUsage: "<network_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", networkCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <network_name>."))
+		}
+
+		vpn, err := client.New().Network.InspectVPN(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "inspection of VPN", false).Error())))
+		}
+		return clitools.SuccessResponse(vpn)
+	},
+}
+
+var networkDNSCmd = cli.Command{
+	Name:  "dns",
+	Usage: "dns COMMAND",
+	Subcommands: []cli.Command{
+		networkDNSAdd,
+		networkDNSRemove,
+		networkDNSList,
+	},
+}
+
+var networkDNSAdd = cli.Command{
+	Name:      "add",
+	Usage:     "Adds to the internal DNS of the network an alias (CNAME record) of a host or of an external name",
+	ArgsUsage: "<network_name> <alias> <target>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", networkCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 3 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <network_name>, <alias> or <target>."))
+		}
+
+		dns, err := client.New().Network.AddDNSAlias(c.Args().Get(0), c.Args().Get(1), c.Args().Get(2), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "addition of DNS alias", false).Error())))
+		}
+		return clitools.SuccessResponse(dns)
+	},
+}
+
+var networkDNSRemove = cli.Command{
+	Name:      "remove",
+	Aliases:   []string{"rm", "delete"},
+	Usage:     "Removes an alias from the internal DNS of the network",
+	ArgsUsage: "<network_name> <alias>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", networkCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <network_name> or <alias>."))
+		}
+
+		dns, err := client.New().Network.RemoveDNSAlias(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "removal of DNS alias", false).Error())))
+		}
+		return clitools.SuccessResponse(dns)
+	},
+}
+
+var networkDNSList = cli.Command{
+	Name:      "list",
+	Aliases:   []string{"ls", "inspect", "show"},
+	Usage:     "Lists the records and aliases served by the internal DNS of the network",
+	ArgsUsage: "<network_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", networkCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <network_name>."))
+		}
+
+		dns, err := client.New().Network.InspectDNS(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "inspection of DNS", false).Error())))
+		}
+		return clitools.SuccessResponse(dns)
+	},
+}
+
 var networkCreate = cli.Command{
 	Name:      "create",
 	Aliases:   []string{"new"},
@@ -165,8 +406,24 @@ var networkCreate = cli.Command{
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  "cidr",
-			Value: "192.168.0.0/24",
-			Usage: "cidr of the network",
+			Usage: "cidr of the network; if not set, a free cidr is allocated from the address pool of the tenant",
+		},
+		cli.IntFlag{
+			Name:  "subnet-size",
+			Value: 24,
+			Usage: "prefix length of the cidr allocated when --cidr is not set",
+		},
+		cli.BoolFlag{
+			Name:  "ipv6",
+			Usage: "creates an IPv6-only network; --cidr is then an IPv6 prefix, randomly chosen in fd00::/8 if not set",
+		},
+		cli.BoolFlag{
+			Name:  "dual-stack",
+			Usage: "creates a network with both IPv4 and IPv6 addresses",
+		},
+		cli.StringFlag{
+			Name:  "ipv6-cidr",
+			Usage: "IPv6 prefix of a dual-stack network; if not set, it is chosen by the provider",
 		},
 		cli.StringFlag{
 			Name:  "os",
@@ -174,7 +431,7 @@ var networkCreate = cli.Command{
 			Usage: "Image name for the gateway",
 		},
 		cli.StringFlag{
-			Name: "domain",
+			Name:  "domain",
 			Value: "",
 			Usage: "Defines the domain used to define host FQDN (default: empty)",
 		},
@@ -187,10 +444,14 @@ var networkCreate = cli.Command{
 			Name:  "failover",
 			Usage: "creates 2 gateways for the network with a VIP used as internal default route",
 		},
+		cli.BoolFlag{
+			Name:  "async",
+			Usage: "Submit the creation and return immediately the UUID of the job (use 'safescale job inspect' to follow it)",
+		},
 		cli.StringFlag{
 			Name: "S, sizing",
 			Usage: `Describe sizing of network gateway in format "<component><operator><value>[,...]" where:
-			<component> can be cpu, cpufreq, gpu, ram, disk
+			<component> can be cpu, cpufreq, gpu, ram, disk, disk-speed, net-speed
 			<operator> can be =,~,<=,>= (except for disk where valid operators are only = or >=):
 				- = means exactly <value>
 				- ~ means between <value> and 2*<value>
@@ -200,14 +461,17 @@ var networkCreate = cli.Command{
 				- >= means greater or equal to <value>
 			<value> can be an integer (for cpu and disk) or a float (for ram) or an including interval "[<lower value>-<upper value>]:"
 				- <cpu> is expecting an int as number of cpu cores, or an interval with minimum and maximum number of cpu cores
-				- <cpufreq> is expecting an int as minimum cpu frequency in MHz
+				- <cpufreq> is expecting a float as minimum cpu frequency in GHz, or in MHz when followed by MHz (scanner would have been run first)
 				- <gpu> is expecting an int as number of GPU (scanner would have been run first to be able to determine which template proposes GPU)
 				- <ram> is expecting a float as memory size in GB, or an interval with minimum and maximum mmory size
 				- <disk> is expecting an int as system disk size in GB
+				- <disk-speed> is expecting a float as minimum speed of the system disk in MB/s, or in GB/s when followed by GBps (scanner would have been run first)
+				- <net-speed> is expecting a float as minimum network speed in KB/s, or in MB/s when followed by MBps (scanner would have been run first)
 			examples:
 				--sizing "cpu <= 4, ram <= 10, disk >= 100"
 				--sizing "cpu ~ 4, ram = [14-32]" (is identical to --sizing "cpu=[4-8], ram=[14-32]")
 				--sizing "cpu <= 8, ram ~ 16"
+				--sizing "cpu >= 4, disk-speed >= 200MBps, cpufreq >= 2.5"
 `,
 		},
 		cli.UintFlag{
@@ -230,21 +494,44 @@ var networkCreate = cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <network_name>."))
 		}
 
+		ipVersion := pb.IPVersion_IPV4
+		switch {
+		case c.Bool("ipv6") && c.Bool("dual-stack"):
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("--ipv6 and --dual-stack cannot be used together"))
+		case c.Bool("ipv6"):
+			ipVersion = pb.IPVersion_IPV6
+		case c.Bool("dual-stack"):
+			ipVersion = pb.IPVersion_DUAL_STACK
+		}
+		if c.IsSet("ipv6-cidr") && ipVersion != pb.IPVersion_DUAL_STACK {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("--ipv6-cidr can be used only with --dual-stack"))
+		}
+
 		def, err := constructPBHostDefinitionFromCLI(c, "sizing")
 		if err != nil {
 			return err
 		}
 		netdef := pb.NetworkDefinition{
-			Cidr:     c.String("cidr"),
-			Name:     c.Args().Get(0),
-			FailOver: c.Bool("failover"),
-			Domain: c.String("domain"),
+			Cidr:       c.String("cidr"),
+			SubnetSize: int32(c.Int("subnet-size")),
+			IpVersion:  ipVersion,
+			Ipv6Cidr:   c.String("ipv6-cidr"),
+			Name:       c.Args().Get(0),
+			FailOver:   c.Bool("failover"),
+			Domain:     c.String("domain"),
 			Gateway: &pb.GatewayDefinition{
 				ImageId: c.String("os"),
 				Name:    c.String("gwname"),
 				Sizing:  def.Sizing,
 			},
 		}
+		if c.Bool("async") {
+			uuid, err := client.New().Network.CreateAsync(&netdef, temporal.GetExecutionTimeout())
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "submission of network creation", false).Error())))
+			}
+			return clitools.SuccessResponse(asyncResponse(uuid))
+		}
 		network, err := client.New().Network.Create(&netdef, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "creation of network", true).Error())))
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var publicIPCmdName = "public-ip"
+
+// PublicIPCmd public-ip command
+var PublicIPCmd = cli.Command{
+	Name:  "public-ip",
+	Usage: "public-ip COMMAND",
+	Subcommands: []cli.Command{
+		publicIPList,
+		publicIPInspect,
+		publicIPAllocate,
+		publicIPAttach,
+		publicIPDetach,
+		publicIPRelease,
+	},
+}
+
+var publicIPList = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List public IPs",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "all",
+			Usage: "List all public IPs on tenant (not only those allocated by SafeScale)",
+		}},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", publicIPCmdName, c.Command.Name, c.Args())
+		ips, err := client.New().PublicIP.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of public IPs", false).Error())))
+		}
+		return clitools.SuccessResponse(ips.GetPublicIps())
+	},
+}
+
+var publicIPInspect = cli.Command{
+	Name:      "inspect",
+	Aliases:   []string{"show"},
+	Usage:     "Inspect public IP",
+	ArgsUsage: "<PublicIP_name|PublicIP_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name|PublicIP_ID>."))
+		}
+
+		ip, err := client.New().PublicIP.Inspect(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "inspection of public IP", false).Error())))
+		}
+		return clitools.SuccessResponse(ip)
+	},
+}
+
+var publicIPAllocate = cli.Command{
+	Name:      "allocate",
+	Aliases:   []string{"create", "new"},
+	Usage:     "Allocate a public IP",
+	ArgsUsage: "<PublicIP_name>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name>."))
+		}
+
+		ip, err := client.New().PublicIP.Allocate(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "allocation of public IP", true).Error())))
+		}
+		return clitools.SuccessResponse(ip)
+	},
+}
+
+var publicIPAttach = cli.Command{
+	Name:      "attach",
+	Usage:     "Attach a public IP to an host, moving it from the host it is attached to if any",
+	ArgsUsage: "<PublicIP_name|PublicIP_ID> <Host_name|Host_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name> and/or <Host_name>."))
+		}
+
+		ip, err := client.New().PublicIP.Attach(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "attach of public IP", true).Error())))
+		}
+		return clitools.SuccessResponse(ip)
+	},
+}
+
+var publicIPDetach = cli.Command{
+	Name:      "detach",
+	Usage:     "Detach a public IP from its host",
+	ArgsUsage: "<PublicIP_name|PublicIP_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name>."))
+		}
+
+		ip, err := client.New().PublicIP.Detach(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "detach of public IP", true).Error())))
+		}
+		return clitools.SuccessResponse(ip)
+	},
+}
+
+var publicIPRelease = cli.Command{
+	Name:      "release",
+	Aliases:   []string{"delete", "rm"},
+	Usage:     "Release a public IP",
+	ArgsUsage: "<PublicIP_name|PublicIP_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name>."))
+		}
+
+		err := client.New().PublicIP.Release(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "release of public IP", true).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package commands
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var serverCmdName = "server"
+
+// ServerCmd command
+var ServerCmd = cli.Command{
+	Name:  "server",
+	Usage: "server COMMAND",
+	Subcommands: []cli.Command{
+		serverHealth,
+	},
+}
+
+var serverHealth = cli.Command{
+	Name:  "health",
+	Usage: "Display the liveness and readiness of safescaled; fails if safescaled is not ready",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", serverCmdName, c.Command.Name, c.Args())
+		health, err := client.New().Health.Check(temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "health check of safescaled", false).Error())))
+		}
+		if !health.GetReady() {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, "safescaled is not ready (draining jobs before shutdown)"))
+		}
+		return clitools.SuccessResponse(health)
+	},
+}
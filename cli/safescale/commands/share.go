@@ -18,6 +18,7 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -47,13 +48,39 @@ var ShareCmd = cli.Command{
 		shareUnmount,
 		shareList,
 		shareInspect,
+		shareACLCmd,
 	},
 }
 
+// parseShareACLs parses ACLs in the format '<host>[:rw|:ro]', separated by commas
+func parseShareACLs(in []string) ([]*pb.ShareACL, error) {
+	var acls []*pb.ShareACL
+	for _, arg := range in {
+		for _, v := range strings.Split(arg, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			host, access := v, "rw"
+			if i := strings.LastIndex(v, ":"); i != -1 {
+				host, access = v[:i], v[i+1:]
+			}
+			if host == "" || (access != "rw" && access != "ro") {
+				return nil, fmt.Errorf("invalid ACL '%s', must be '<host>[:rw|:ro]'", v)
+			}
+			acls = append(acls, &pb.ShareACL{
+				Host:     &pb.Reference{Name: host},
+				ReadOnly: access == "ro",
+			})
+		}
+	}
+	return acls, nil
+}
+
 var shareCreate = cli.Command{
 	Name:      "create",
 	Aliases:   []string{"new"},
-	Usage:     "Create a nfs server on an host and exports a directory",
+	Usage:     "Create a share server on an host and exports a directory",
 	ArgsUsage: "<Share_name> <Host_name|Host_ID>",
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -61,6 +88,11 @@ var shareCreate = cli.Command{
 			Value: resources.DefaultShareExportedPath,
 			Usage: "Path to be exported",
 		},
+		cli.StringFlag{
+			Name:  "type",
+			Value: "nfs",
+			Usage: "Protocol used to export the share; can be 'nfs', 'nfs4-krb5' (NFSv4 with Kerberos) or 'smb' (SMB/CIFS)",
+		},
 		cli.BoolFlag{
 			Name:  "readonly",
 			Usage: "Disallow write requests on this NFS volume",
@@ -89,9 +121,13 @@ var shareCreate = cli.Command{
 			Name:  "subtreecheck",
 			Usage: "Enable subtree checking",
 		},
+		cli.StringFlag{
+			Name:  "allow",
+			Usage: "Hosts allowed to mount the share, as '<host>[:rw|:ro]' separated by commas (default: every host, with rw access)",
+		},
 		cli.StringSliceFlag{
 			Name:  "securityModes",
-			Usage: "{sys(the default--no cryptographic security), krb5(authentication only), krb5i(integrity protection), and krb5p(privacy protection)}",
+			Usage: "{sys(the default--no cryptographic security), krb5(authentication only), krb5i(integrity protection), and krb5p(privacy protection)}; type 'nfs4-krb5' defaults to krb5 and refuses sys, type 'smb' accepts only one mode, sys meaning a Samba user and none guest access",
 		},
 	},
 	Action: func(c *cli.Context) error {
@@ -101,11 +137,17 @@ var shareCreate = cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Nas_name> and/or <Host_name>."))
 		}
 
+		acls, err := parseShareACLs([]string{c.String("allow")})
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(err.Error()))
+		}
+
 		shareName := c.Args().Get(0)
 		def := pb.ShareDefinition{
 			Name: shareName,
 			Host: &pb.Reference{Name: c.Args().Get(1)},
 			Path: c.String("path"),
+			Type: c.String("type"),
 			Options: &pb.ExportOptions{
 				ReadOnly:     c.Bool("readonly"),
 				RootSquash:   c.Bool("rootsquash"),
@@ -116,8 +158,9 @@ var shareCreate = cli.Command{
 				SubtreeCheck: c.Bool("subtreecheck"),
 			},
 			SecurityModes: c.StringSlice("securityModes"),
+			Acls:          acls,
 		}
-		err := client.New().Share.Create(&def, temporal.GetExecutionTimeout())
+		err = client.New().Share.Create(&def, temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(client.DecorateError(err, "creation of share", true).Error()))
 		}
@@ -271,3 +314,65 @@ var shareInspect = cli.Command{
 		return clitools.SuccessResponse(list)
 	},
 }
+
+var shareACLCmd = cli.Command{
+	Name:  "acl",
+	Usage: "acl COMMAND",
+	Subcommands: []cli.Command{
+		shareACLAdd,
+		shareACLRemove,
+	},
+}
+
+var shareACLAdd = cli.Command{
+	Name:      "add",
+	Usage:     "Allows hosts to mount a share, or changes their access; the share is re-exported without disturbing its clients",
+	ArgsUsage: "<Share_name> <Host_name|Host_ID>[:rw|:ro]...",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", shareCmdName, c.Command.Name, c.Args())
+		if c.NArg() < 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Share_name> and/or <Host_name>."))
+		}
+
+		acls, err := parseShareACLs(c.Args().Tail())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument(err.Error()))
+		}
+		def := pb.ShareACLDefinition{
+			Share: &pb.Reference{Name: c.Args().First()},
+			Acls:  acls,
+		}
+		err = client.New().Share.AddACL(&def, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(client.DecorateError(err, "addition of share ACL", true).Error()))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var shareACLRemove = cli.Command{
+	Name:      "remove",
+	Aliases:   []string{"rm", "delete"},
+	Usage:     "Forbids hosts to mount a share; the hosts must not mount the share",
+	ArgsUsage: "<Share_name> <Host_name|Host_ID>...",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", shareCmdName, c.Command.Name, c.Args())
+		if c.NArg() < 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Share_name> and/or <Host_name>."))
+		}
+
+		def := pb.ShareACLDefinition{
+			Share: &pb.Reference{Name: c.Args().First()},
+		}
+		for _, v := range c.Args().Tail() {
+			def.Acls = append(def.Acls, &pb.ShareACL{Host: &pb.Reference{Name: v}})
+		}
+		err := client.New().Share.RemoveACL(&def, temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(client.DecorateError(err, "removal of share ACL", true).Error()))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
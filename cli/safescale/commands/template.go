@@ -17,6 +17,9 @@
 package commands
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 
@@ -45,13 +48,47 @@ var templateList = cli.Command{
 		cli.BoolFlag{
 			Name:  "all",
 			Usage: "List all available templates in tenant (without any filter)",
+		},
+		cli.BoolFlag{
+			Name:  "scanned",
+			Usage: "List only the templates measured by the scanner, with their measurements (CPU frequency, disk and network speeds, ...)",
+		},
+		cli.BoolFlag{
+			Name:  "presets",
+			Usage: "List the presets of the tenant, usable as '@<name>' with the flags --sizing and --os",
 		}},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", templateCmdName, c.Command.Name, c.Args())
-		templates, err := client.New().Template.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		if c.Bool("presets") {
+			presets, err := client.New().Template.Presets(temporal.GetExecutionTimeout())
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of presets", false).Error())))
+			}
+			return clitools.SuccessResponse(presets.GetPresets())
+		}
+		templates, err := client.New().Template.List(c.Bool("all"), c.Bool("scanned"), temporal.GetExecutionTimeout())
 		if err != nil {
 			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of templates", false).Error())))
 		}
 		return clitools.SuccessResponse(templates.GetTemplates())
 	},
 }
+
+// presetSizing returns the sizing expression of the preset referenced by value ('@<name>')
+func presetSizing(value string) (string, error) {
+	name := strings.TrimPrefix(value, "@")
+	presets, err := client.New().Template.Presets(temporal.GetExecutionTimeout())
+	if err != nil {
+		return "", clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "list of presets", false).Error())))
+	}
+	for _, p := range presets.GetPresets() {
+		if p.GetName() != name {
+			continue
+		}
+		if p.GetSizing() == "" {
+			return "", clitools.FailureResponse(clitools.ExitOnInvalidArgument(fmt.Sprintf("preset '%s' defines no sizing", name)))
+		}
+		return p.GetSizing(), nil
+	}
+	return "", clitools.FailureResponse(clitools.ExitOnInvalidArgument(fmt.Sprintf("preset '%s' not found", name)))
+}
@@ -17,12 +17,17 @@
 package commands
 
 import (
+	"sort"
+
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 
+	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/server/cluster"
 	"github.com/CS-SI/SafeScale/lib/utils"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
@@ -36,6 +41,7 @@ var TenantCmd = cli.Command{
 		tenantList,
 		tenantGet,
 		tenantSet,
+		tenantCost,
 		// tenantStorageList,
 		// tenantStorageGet,
 		// tenantStorageSet,
@@ -87,6 +93,102 @@ var tenantSet = cli.Command{
 	},
 }
 
+var tenantCost = cli.Command{
+	Name:  "cost",
+	Usage: "Estimate the hourly cost of the hosts and volumes of the current tenant, grouped by cluster or network",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", tenantCmdName, c.Command.Name, c.Args())
+		cost, err := client.New().Tenant.Cost(temporal.GetExecutionTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "estimation of tenant cost", false).Error())))
+		}
+		return clitools.SuccessResponse(formatTenantCost(cost, clustersByNetwork()))
+	},
+}
+
+// clustersByNetwork returns the names of the clusters indexed by the ID of their network
+func clustersByNetwork() map[string]string {
+	clusters := map[string]string{}
+	list, err := cluster.List()
+	if err != nil {
+		logrus.Warnf("failed to list clusters, costs are grouped by network only: %v", err)
+		return clusters
+	}
+	task := concurrency.RootTask()
+	for _, c := range list {
+		netCfg, err := c.GetNetworkConfig(task)
+		if err != nil {
+			logrus.Warnf("failed to get network of cluster '%s': %v", c.GetIdentity(task).Name, err)
+			continue
+		}
+		clusters[netCfg.NetworkID] = c.GetIdentity(task).Name
+	}
+	return clusters
+}
+
+// formatTenantCost groups the costs of the resources by cluster, or by network if the network is not the one of a
+// cluster; the resources without price are listed apart
+func formatTenantCost(cost *pb.TenantCost, clusters map[string]string) map[string]interface{} {
+	type group struct {
+		Kind         string                   `json:"kind"`
+		Name         string                   `json:"name"`
+		PricePerHour float64                  `json:"price_per_hour"`
+		Resources    []map[string]interface{} `json:"resources"`
+	}
+
+	var (
+		total    float64
+		unpriced []map[string]interface{}
+	)
+	groups := map[string]*group{}
+	for _, r := range cost.GetResources() {
+		res := map[string]interface{}{
+			"kind": r.GetKind(),
+			"name": r.GetName(),
+		}
+		if !r.GetPriced() {
+			unpriced = append(unpriced, res)
+			continue
+		}
+		res["price_per_hour"] = r.GetPricePerHour()
+
+		g := &group{Kind: "network", Name: r.GetNetworkName()}
+		if name, ok := clusters[r.GetNetworkId()]; ok {
+			g = &group{Kind: "cluster", Name: name}
+		} else if r.GetNetworkId() == "" {
+			g = &group{Kind: "none", Name: "unattached"}
+		} else if g.Name == "" {
+			g.Name = r.GetNetworkId()
+		}
+		key := g.Kind + ":" + g.Name
+		if found, ok := groups[key]; ok {
+			g = found
+		} else {
+			groups[key] = g
+		}
+		g.Resources = append(g.Resources, res)
+		g.PricePerHour += r.GetPricePerHour()
+		total += r.GetPricePerHour()
+	}
+
+	var keys []string
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sorted []*group
+	for _, k := range keys {
+		sorted = append(sorted, groups[k])
+	}
+
+	return map[string]interface{}{
+		"currency":       cost.GetCurrency(),
+		"total_per_hour": total,
+		"groups":         sorted,
+		"unpriced":       unpriced,
+	}
+}
+
 // var tenantStorageList = cli.Command{
 // 	Name:    "storage-list",
 // 	Aliases: []string{"storage-ls"},
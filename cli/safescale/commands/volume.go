@@ -44,6 +44,7 @@ var VolumeCmd = cli.Command{
 		volumeCreate,
 		volumeAttach,
 		volumeDetach,
+		volumeMigrate,
 	},
 }
 
@@ -126,6 +127,15 @@ var volumeCreate = cli.Command{
 			Value: "HDD",
 			Usage: fmt.Sprintf("Allowed values: %s", getAllowedSpeeds()),
 		},
+		cli.StringFlag{
+			Name:  "raid",
+			Usage: "Creates a software RAID volume of this level (0, 1 or 10), made of --count volumes of --size Go each",
+		},
+		cli.IntFlag{
+			Name:  "count",
+			Value: 2,
+			Usage: "Number of volumes making up a RAID volume",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeCmdName, c.Command.Name, c.Args())
@@ -148,6 +158,10 @@ var volumeCreate = cli.Command{
 			Size:  volSize,
 			Speed: pb.VolumeSpeed(volSpeed),
 		}
+		if raid := c.String("raid"); raid != "" {
+			def.Raid = raid
+			def.Count = int32(c.Int("count"))
+		}
 
 		volume, err := client.New().Volume.Create(&def, temporal.GetExecutionTimeout())
 		if err != nil {
@@ -172,6 +186,10 @@ var volumeAttach = cli.Command{
 			Value: "ext4",
 			Usage: "Filesystem format",
 		},
+		cli.BoolFlag{
+			Name:  "encrypt",
+			Usage: "Encrypt the volume with LUKS, its key being kept ciphered in metadata (a volume encrypted once is encrypted on any later attachment)",
+		},
 		cli.BoolFlag{
 			Name:  "do-not-format",
 			Usage: "Prevent the volume to be formated (the previous format of the disk will be kept, beware that a new volume has no format before his first attachment and so cannot be attach with this option)",
@@ -186,6 +204,7 @@ var volumeAttach = cli.Command{
 		def := pb.VolumeAttachment{
 			Format:      c.String("format"),
 			DoNotFormat: c.Bool("do-not-format"),
+			Encrypt:     c.Bool("encrypt"),
 			MountPath:   c.String("path"),
 			Host:        &pb.Reference{Name: c.Args().Get(1)},
 			Volume:      &pb.Reference{Name: c.Args().Get(0)},
@@ -217,15 +236,38 @@ var volumeDetach = cli.Command{
 	},
 }
 
+var volumeMigrate = cli.Command{
+	Name:      "migrate",
+	Usage:     "Move a volume to another host, copying its data in a new volume if the provider can't attach it to this host",
+	ArgsUsage: "<Volume_name|Volume_ID> <Host_name|Host_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: {%s}, {%s} with args {%s}", volumeCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name> and/or <Host_name>."))
+		}
+
+		err := client.New().Volume.Migrate(c.Args().Get(0), c.Args().Get(1), temporal.GetLongOperationTimeout())
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnRPC(utils.Capitalize(client.DecorateError(err, "migration of volume", true).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
 type volumeInfoDisplayable struct {
-	ID        string
-	Name      string
-	Speed     string
-	Size      int32
-	Host      string
-	MountPath string
-	Format    string
-	Device    string
+	ID          string
+	Name        string
+	Speed       string
+	Size        int32
+	Host        string
+	MountPath   string
+	Format      string
+	Device      string
+	Encrypted   bool
+	RAID        string `json:",omitempty"`
+	RAIDMembers int32  `json:",omitempty"`
+	RAIDHealth  string `json:",omitempty"`
 }
 
 type volumeDisplayable struct {
@@ -245,6 +287,10 @@ func toDisplaybleVolumeInfo(volumeInfo *pb.VolumeInfo) *volumeInfoDisplayable {
 		volumeInfo.GetMountPath(),
 		volumeInfo.GetFormat(),
 		volumeInfo.GetDevice(),
+		volumeInfo.GetEncrypted(),
+		volumeInfo.GetRaid(),
+		volumeInfo.GetRaidMembers(),
+		volumeInfo.GetRaidHealth(),
 	}
 }
 
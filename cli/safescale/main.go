@@ -157,6 +157,9 @@ func main() {
 		return nil
 	}
 
+	app.Commands = append(app.Commands, commands.AuditCmd)
+	sort.Sort(cli.CommandsByName(commands.AuditCmd.Subcommands))
+
 	app.Commands = append(app.Commands, commands.NetworkCmd)
 	sort.Sort(cli.CommandsByName(commands.NetworkCmd.Subcommands))
 
@@ -169,6 +172,9 @@ func main() {
 	app.Commands = append(app.Commands, commands.VolumeCmd)
 	sort.Sort(cli.CommandsByName(commands.VolumeCmd.Subcommands))
 
+	app.Commands = append(app.Commands, commands.PublicIPCmd)
+	sort.Sort(cli.CommandsByName(commands.PublicIPCmd.Subcommands))
+
 	app.Commands = append(app.Commands, commands.SSHCmd)
 	sort.Sort(cli.CommandsByName(commands.SSHCmd.Subcommands))
 
@@ -184,6 +190,12 @@ func main() {
 	app.Commands = append(app.Commands, commands.TemplateCmd)
 	sort.Sort(cli.CommandsByName(commands.TemplateCmd.Subcommands))
 
+	app.Commands = append(app.Commands, commands.JobCmd)
+	sort.Sort(cli.CommandsByName(commands.JobCmd.Subcommands))
+
+	app.Commands = append(app.Commands, commands.ServerCmd)
+	sort.Sort(cli.CommandsByName(commands.ServerCmd.Subcommands))
+
 	app.Commands = append(app.Commands, commands.ClusterCommand)
 	sort.Sort(cli.CommandsByName(commands.ClusterCommand.Subcommands))
 
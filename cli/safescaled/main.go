@@ -17,6 +17,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -26,36 +27,178 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/cluster"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/server/listeners"
 	"github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
 
 	_ "github.com/CS-SI/SafeScale/lib/server"
 )
 
-var profileCloseFunc = func() {}
+var (
+	profileCloseFunc = func() {}
+	tracingCloseFunc = func() {}
+)
 
 func cleanup(onAbort bool) {
 	fmt.Println("cleanup")
 	profileCloseFunc()
+	tracingCloseFunc()
 	os.Exit(0)
 }
 
+// cancelGracePeriod is the time left to cancelled jobs to run their cleanup before stopping anyway
+const cancelGracePeriod = 2 * time.Minute
+
+// shutdown stops safescaled gracefully: new requests are refused, running jobs have up to 'drainTimeout' to end,
+// then the remaining ones are cancelled (running their cleanup) before the gRPC server is stopped
+func shutdown(s *grpc.Server, drainTimeout time.Duration) {
+	logrus.Warnf("Shutting down, waiting up to %s for %d running job(s) to end...", drainTimeout, utils.JobCount())
+	listeners.StartDraining()
+
+	if !utils.JobWaitAll(drainTimeout) {
+		count := utils.JobCancelAll()
+		logrus.Warnf("%d job(s) still running after %s, cancelled", count, drainTimeout)
+		if !utils.JobWaitAll(cancelGracePeriod) {
+			logrus.Errorf("%d job(s) still running after cancellation, stopping anyway", utils.JobCount())
+		}
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		s.Stop()
+	}
+}
+
+// defaultSpotCheckInterval is the default delay between two searches of reclaimed spot nodes in clusters
+const defaultSpotCheckInterval = 5 * time.Minute
+
+// watchReclaimedNodes replaces periodically the spot nodes of the clusters of the current tenant reclaimed by the
+// provider, until safescaled starts draining
+func watchReclaimedNodes(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-listeners.DrainingStarted():
+			return
+		case <-ticker.C:
+		}
+		if listeners.GetCurrentTenant() == nil {
+			// No tenant set yet, nothing to watch
+			continue
+		}
+		err := listeners.RunBackgroundJob("Replace reclaimed spot nodes", func(ctx context.Context) error {
+			task, err := concurrency.NewTaskWithContext(ctx)
+			if err != nil {
+				return err
+			}
+			return cluster.ReplaceReclaimedNodes(task)
+		})
+		if err != nil {
+			logrus.Errorf("failed to replace reclaimed spot nodes: %v", err)
+		}
+	}
+}
+
+// defaultLifecycleCheckInterval is the default delay between two enforcements of the lifecycle policies of hosts
+const defaultLifecycleCheckInterval = 5 * time.Minute
+
+// watchHostLifecycles enforces periodically the lifecycle policies (expiration, stop when idle) of the hosts of the
+// current tenant, until safescaled starts draining
+func watchHostLifecycles(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-listeners.DrainingStarted():
+			return
+		case <-ticker.C:
+		}
+		if listeners.GetCurrentTenant() == nil {
+			// No tenant set yet, nothing to watch
+			continue
+		}
+		err := listeners.RunBackgroundJob("Enforce lifecycle policies of hosts", listeners.EnforceHostLifecycles)
+		if err != nil {
+			logrus.Errorf("failed to enforce lifecycle policies of hosts: %v", err)
+		}
+	}
+}
+
+// getIntervalFromEnv returns the duration set in the environment variable 'key', or 'defaultValue' if the variable is
+// not set or does not contain a positive duration
+func getIntervalFromEnv(key string, defaultValue time.Duration) time.Duration {
+	interval := temporal.GetTimeoutFromEnv(key, defaultValue)
+	if interval <= 0 {
+		logrus.Warnf("invalid value '%s' of %s, must be positive; using %s", os.Getenv(key), key, defaultValue)
+		return defaultValue
+	}
+	return interval
+}
+
+// defaultJobRetention is the default delay during which the records of ended jobs are kept in the job store
+const defaultJobRetention = 30 * 24 * time.Hour
+
+// jobPruneInterval is the delay between two removals of expired records from the job store
+const jobPruneInterval = 24 * time.Hour
+
+// pruneJobStore removes, at startup then periodically, the records of the jobs ended for more than 'retention'
+func pruneJobStore(retention time.Duration) {
+	for {
+		count, err := utils.JobPrune(time.Now().Add(-retention))
+		if err != nil {
+			logrus.Warnf("failed to prune job store: %v", err)
+		} else if count > 0 {
+			logrus.Infof("Removed %d job(s) ended for more than %s from job store", count, retention)
+		}
+		time.Sleep(jobPruneInterval)
+	}
+}
+
 // *** MAIN ***
-func work(version string) {
-	c := make(chan os.Signal)
+func work(version string, drainTimeout time.Duration) {
+	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	serving := make(chan *grpc.Server, 1)
 	go func() {
+		var s *grpc.Server
+		select {
+		case <-c:
+			// Nothing is running yet, stops immediately
+			cleanup(true)
+		case s = <-serving:
+		}
+
 		<-c
-		cleanup(true)
+		go func() {
+			// A second signal forces the stop without waiting for the jobs
+			<-c
+			logrus.Warnln("Forced to stop")
+			cleanup(true)
+		}()
+		shutdown(s, drainTimeout)
 	}()
 
 	logrus.Infoln("Checking configuration")
@@ -93,20 +236,38 @@ func work(version string) {
 	if err != nil {
 		logrus.Fatalf("failed to listen: %v", err)
 	}
-	s := grpc.NewServer()
+	err = utils.JobRecoverInterrupted()
+	if err != nil {
+		logrus.Warnf("failed to recover interrupted jobs from job store: %v", err)
+	}
+	go pruneJobStore(getIntervalFromEnv("SAFESCALE_JOB_RETENTION", defaultJobRetention))
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			otelgrpc.UnaryServerInterceptor(),
+			listeners.MetricsUnaryServerInterceptor,
+			listeners.DrainingUnaryServerInterceptor,
+			listeners.JobUnaryServerInterceptor,
+			listeners.AuditUnaryServerInterceptor,
+		),
+	)
 
 	logrus.Infoln("Registering services")
+	pb.RegisterAuditServiceServer(s, &listeners.AuditListener{})
 	pb.RegisterBucketServiceServer(s, &listeners.BucketListener{})
 	// pb.RegisterDataServiceServer(s, &listeners.DataListener{})
+	pb.RegisterHealthServiceServer(s, &listeners.HealthListener{})
 	pb.RegisterHostServiceServer(s, &listeners.HostListener{})
 	pb.RegisterImageServiceServer(s, &listeners.ImageListener{})
 	pb.RegisterJobServiceServer(s, &listeners.JobManagerListener{})
 	pb.RegisterNetworkServiceServer(s, &listeners.NetworkListener{})
+	pb.RegisterPublicIPServiceServer(s, &listeners.PublicIPListener{})
 	pb.RegisterShareServiceServer(s, &listeners.ShareListener{})
 	pb.RegisterSshServiceServer(s, &listeners.SSHListener{})
 	pb.RegisterTemplateServiceServer(s, &listeners.TemplateListener{})
 	pb.RegisterTenantServiceServer(s, &listeners.TenantListener{})
 	pb.RegisterVolumeServiceServer(s, &listeners.VolumeListener{})
+	healthpb.RegisterHealthServer(s, listeners.StandardHealthServer)
 
 	// logrus.Println("Initializing service factory")
 	// commands.InitServiceFactory()
@@ -114,6 +275,10 @@ func work(version string) {
 	// Register reflection service on gRPC server.
 	reflection.Register(s)
 
+	go watchReclaimedNodes(getIntervalFromEnv("SAFESCALE_SPOT_CHECK_INTERVAL", defaultSpotCheckInterval))
+	go watchHostLifecycles(getIntervalFromEnv("SAFESCALE_HOST_LIFECYCLE_CHECK_INTERVAL", defaultLifecycleCheckInterval))
+
+	serving <- s
 	fmt.Printf("Safescaled version: %s\nReady to serve :-)\n", version)
 	if err := s.Serve(lis); err != nil {
 		logrus.Fatalf("Failed to serve: %v", err)
@@ -153,6 +318,19 @@ func main() {
 			Usage: "Profiles binary; can contain 'cpu', 'ram', 'web' and a combination of them (ie 'cpu,ram')",
 			// TODO: extends profile to accept <what>:params, for example cpu:$HOME/safescale.cpu.pprof, or web:192.168.2.1:1666
 		},
+		cli.StringFlag{
+			Name:  "metrics",
+			Usage: "Exposes Prometheus metrics on `ADDRESS` (ie ':9100'), under path /metrics",
+		},
+		cli.StringFlag{
+			Name:  "tracing",
+			Usage: "Exports OpenTelemetry traces to `EXPORTER`; can be 'otlp[:<endpoint>]' or 'file:<path>'",
+		},
+		cli.DurationFlag{
+			Name:  "drain-timeout",
+			Usage: "On SIGTERM or SIGINT, waits up to `DURATION` for running jobs to end before cancelling them",
+			Value: 5 * time.Minute,
+		},
 		// cli.IntFlag{
 		// 	Name:  "port, p",
 		// 	Usage: "Bind to specified port `PORT`",
@@ -186,11 +364,25 @@ func main() {
 			}
 			utils.Debug = true
 		}
+
+		// Sets tracing
+		if c.IsSet("tracing") {
+			closer, err := tracing.Init(c.String("tracing"), "safescaled")
+			if err != nil {
+				return fmt.Errorf("failed to initialize tracing: %v", err)
+			}
+			tracingCloseFunc = closer
+		}
+
+		// Exposes metrics
+		if c.IsSet("metrics") {
+			metrics.Serve(c.String("metrics"))
+		}
 		return nil
 	}
 
 	app.Action = func(c *cli.Context) error {
-		work(app.Version)
+		work(app.Version, c.Duration("drain-timeout"))
 		return nil
 	}
 
@@ -4,23 +4,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"os"
 	"os/exec"
 	"strings"
 
 	scribble "github.com/nanobox-io/golang-scribble"
+	"github.com/sirupsen/logrus"
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
 	"github.com/CS-SI/SafeScale/lib/utils"
 )
 
-// StoredCPUInfo ...
-type StoredCPUInfo struct {
-	ID string `bow:"key"`
-	CPUInfo
-}
-
+// collect stores in the metadata bucket of the tenant the results of the scan, shared by all the users of the tenant
 func collect(tenantName string) error {
 	cmd := exec.Command("safescale", "tenant", "set", tenantName)
 	if err := cmd.Run(); err != nil {
@@ -31,53 +27,37 @@ func collect(tenantName string) error {
 	if err != nil {
 		return err
 	}
-	authOpts, err := serviceProvider.GetAuthenticationOptions()
-	if err != nil {
-		return err
-	}
-	region, ok := authOpts.Get("Region")
-	if !ok {
-		return fmt.Errorf("region value unset")
-	}
 
-	folder := fmt.Sprintf("images/%s/%s", serviceProvider.GetName(), region)
-
-	err = os.MkdirAll(utils.AbsPathify("$HOME/.safescale/scanner"), 0777)
+	err = importLocalDB(serviceProvider)
 	if err != nil {
-		log.Fatal(err)
-	}
-
-	db, err := scribble.New(utils.AbsPathify("$HOME/.safescale/scanner/db"), nil)
-	if err != nil {
-		log.Fatal(err)
+		logrus.Warnf("failed to import scan results of the local database: %v", err)
 	}
 
 	files, err := ioutil.ReadDir(utils.AbsPathify("$HOME/.safescale/scanner"))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	for _, file := range files {
-		acpu := StoredCPUInfo{}
 		theFile := utils.AbsPathify(fmt.Sprintf("$HOME/.safescale/scanner/%s", file.Name()))
 		if strings.Contains(file.Name(), tenantName+"#") {
-			log.Printf("Storing: %s", file.Name())
+			logrus.Printf("Storing: %s", file.Name())
 
 			byteValue, err := ioutil.ReadFile(theFile)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 
-			err = json.Unmarshal(byteValue, &acpu)
+			scan := resources.StoredCPUInfo{}
+			err = json.Unmarshal(byteValue, &scan)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
+			scan.ID = scan.ImageID
 
-			acpu.ID = acpu.ImageID
-
-			err = db.Write(folder, acpu.TemplateName, acpu)
+			err = serviceProvider.StoreTemplateScan(&scan)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 		}
 		if !file.IsDir() {
@@ -89,3 +69,47 @@ func collect(tenantName string) error {
 	}
 	return nil
 }
+
+// importLocalDB stores in the metadata bucket the scan results of the tenant kept in the local database by previous
+// versions of the scanner, then removes them from the local database
+func importLocalDB(serviceProvider iaas.Service) error {
+	authOpts, err := serviceProvider.GetAuthenticationOptions()
+	if err != nil {
+		return err
+	}
+	region, ok := authOpts.Get("Region")
+	if !ok {
+		return fmt.Errorf("region value unset")
+	}
+	folder := fmt.Sprintf("images/%s/%s", serviceProvider.GetName(), region)
+
+	dbPath := utils.AbsPathify("$HOME/.safescale/scanner/db")
+	if _, err := os.Stat(fmt.Sprintf("%s/%s", dbPath, folder)); os.IsNotExist(err) {
+		return nil
+	}
+	db, err := scribble.New(dbPath, nil)
+	if err != nil {
+		return err
+	}
+	records, err := db.ReadAll(folder)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		scan := resources.StoredCPUInfo{}
+		err = json.Unmarshal([]byte(record), &scan)
+		if err != nil {
+			return err
+		}
+		logrus.Printf("Importing scan result of template '%s' from the local database", scan.TemplateName)
+		err = serviceProvider.StoreTemplateScan(&scan)
+		if err != nil {
+			return err
+		}
+		err = db.Delete(folder, scan.TemplateName)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
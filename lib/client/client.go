@@ -35,12 +35,15 @@ import (
 
 // Session units the different resources proposed by safescaled as safescale client
 type Session struct {
+	Audit      *audit
 	Bucket     *bucket
 	// Data       *data
+	Health     *health
 	Host       *host
 	Image      *image
 	JobManager *jobManager
 	Network    *network
+	PublicIP   *publicIP
 	Share      *share
 	SSH        *ssh
 	Template   *template
@@ -79,12 +82,15 @@ func New() Client {
 		safescaledPort: safescaledPort,
 	}
 
+	s.Audit = &audit{session: s}
 	s.Bucket = &bucket{session: s}
 	// s.Data = &data{session: s}
+	s.Health = &health{session: s}
 	s.Host = &host{session: s}
 	s.Image = &image{session: s}
 	s.Network = &network{session: s}
 	s.JobManager = &jobManager{session: s}
+	s.PublicIP = &publicIP{session: s}
 	s.Share = &share{session: s}
 	s.SSH = &ssh{session: s}
 	s.Template = &template{session: s}
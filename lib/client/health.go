@@ -0,0 +1,44 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package client
+
+import (
+	"time"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// health is the safescale client part checking the state of safescaled
+type health struct {
+	// session is not used currently
+	session *Session
+}
+
+// Check returns the liveness and readiness of safescaled
+func (h *health) Check(timeout time.Duration) (*pb.HealthStatus, error) {
+	h.session.Connect()
+	defer h.session.Disconnect()
+	service := pb.NewHealthServiceClient(h.session.connection)
+	ctx, err := utils.GetContext(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Check(ctx, &googleprotobuf.Empty{})
+}
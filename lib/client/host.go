@@ -17,6 +17,7 @@
 package client
 
 import (
+	"context"
 	"strings"
 	"sync"
 	"time"
@@ -90,6 +91,19 @@ func (h *host) Reboot(name string, timeout time.Duration) error {
 	return err
 }
 
+// ConsoleLog returns the console output of the host
+func (h *host) ConsoleLog(name string, timeout time.Duration) (*pb.HostConsoleLog, error) {
+	h.session.Connect()
+	defer h.session.Disconnect()
+	service := pb.NewHostServiceClient(h.session.connection)
+	ctx, err := srvutils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.ConsoleLog(ctx, &pb.Reference{Name: name})
+}
+
 // Start host
 func (h *host) Start(name string, timeout time.Duration) error {
 	h.session.Connect()
@@ -134,6 +148,34 @@ func (h *host) Create(def *pb.HostDefinition, timeout time.Duration) (*pb.Host,
 	return service.Create(ctx, def)
 }
 
+// CreateAsync asks safescaled to create the host in background and returns the UUID of the job
+func (h *host) CreateAsync(def *pb.HostDefinition, timeout time.Duration) (string, error) {
+	if def == nil {
+		return "", scerr.InvalidParameterError("def", "cannot be nil")
+	}
+
+	h.session.Connect()
+	defer h.session.Disconnect()
+	service := pb.NewHostServiceClient(h.session.connection)
+	ctx, uuid, err := srvutils.GetAsyncContext(timeout)
+	if err != nil {
+		return "", err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	_, err = service.Create(ctxTo, def)
+	return uuid, err
+}
+
 // Delete deletes several hosts at the same time in goroutines
 func (h *host) Delete(names []string, timeout time.Duration) error {
 	h.session.Connect()
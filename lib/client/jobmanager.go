@@ -57,3 +57,29 @@ func (c *jobManager) Stop(uuid string, timeout time.Duration) error {
 	_, err = service.Stop(ctx, &pb.JobDefinition{Uuid: uuid})
 	return err
 }
+
+// Inspect returns the information about a job, running or ended
+func (c *jobManager) Inspect(uuid string, timeout time.Duration) (*pb.Job, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewJobServiceClient(c.session.connection)
+	ctx, err := utils.GetContext(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Inspect(ctx, &pb.JobDefinition{Uuid: uuid})
+}
+
+// History returns the jobs started since the date 'since' (in RFC3339 format, empty meaning all)
+func (c *jobManager) History(since string, limit int, timeout time.Duration) (*pb.JobHistory, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+	service := pb.NewJobServiceClient(c.session.connection)
+	ctx, err := utils.GetContext(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.History(ctx, &pb.JobHistoryRequest{Since: since, Limit: int32(limit)})
+}
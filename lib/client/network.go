@@ -17,6 +17,7 @@
 package client
 
 import (
+	"context"
 	"strings"
 	"sync"
 	"time"
@@ -157,3 +158,154 @@ func (n *network) Create(def *pb.NetworkDefinition, timeout time.Duration) (*pb.
 	return service.Create(ctx, def)
 
 }
+
+// CreateAsync asks safescaled to create the network in background and returns the UUID of the job
+func (n *network) CreateAsync(def *pb.NetworkDefinition, timeout time.Duration) (string, error) {
+	if def == nil {
+		return "", scerr.InvalidParameterError("def", "cannot be nil")
+	}
+
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, uuid, err := utils.GetAsyncContext(timeout)
+	if err != nil {
+		return "", err
+	}
+
+	var ctxTo context.Context
+	var cancel context.CancelFunc
+
+	if timeout > 0 {
+		ctxTo, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	} else {
+		ctxTo = ctx
+	}
+
+	_, err = service.Create(ctxTo, def)
+	return uuid, err
+}
+
+// Peer connects the network with peer network
+func (n *network) Peer(name, peer string, timeout time.Duration) error {
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Peer(ctx, &pb.NetworkPeeringRequest{Network: &pb.Reference{Name: name}, Peer: &pb.Reference{Name: peer}})
+	return err
+}
+
+// Unpeer disconnects the network from peer network
+func (n *network) Unpeer(name, peer string, timeout time.Duration) error {
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Unpeer(ctx, &pb.NetworkPeeringRequest{Network: &pb.Reference{Name: name}, Peer: &pb.Reference{Name: peer}})
+	return err
+}
+
+// InspectVPN returns the site-to-site VPN configuration of the network
+func (n *network) InspectVPN(name string, timeout time.Duration) (*pb.NetworkVPN, error) {
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.InspectVPN(ctx, &pb.Reference{Name: name})
+}
+
+// AddVPNPeer connects a remote site to the VPN of the network
+func (n *network) AddVPNPeer(name string, peer *pb.NetworkVPNPeer, timeout time.Duration) (*pb.NetworkVPN, error) {
+	if peer == nil {
+		return nil, scerr.InvalidParameterError("peer", "cannot be nil")
+	}
+
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.AddVPNPeer(ctx, &pb.NetworkVPNPeerRequest{Network: &pb.Reference{Name: name}, Peer: peer})
+}
+
+// RemoveVPNPeer disconnects a remote site from the VPN of the network
+func (n *network) RemoveVPNPeer(name, peer string, timeout time.Duration) (*pb.NetworkVPN, error) {
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.RemoveVPNPeer(ctx, &pb.NetworkVPNPeerRequest{Network: &pb.Reference{Name: name}, Peer: &pb.NetworkVPNPeer{Name: peer}})
+}
+
+// InspectDNS returns the records served by the internal DNS of the network
+func (n *network) InspectDNS(name string, timeout time.Duration) (*pb.NetworkDNS, error) {
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.InspectDNS(ctx, &pb.Reference{Name: name})
+}
+
+// AddDNSAlias adds to the internal DNS of the network a CNAME record named alias pointing to target
+func (n *network) AddDNSAlias(name, alias, target string, timeout time.Duration) (*pb.NetworkDNS, error) {
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.AddDNSAlias(ctx, &pb.NetworkDNSAliasRequest{Network: &pb.Reference{Name: name}, Alias: &pb.NetworkDNSAlias{Alias: alias, Target: target}})
+}
+
+// RemoveDNSAlias removes the CNAME record named alias from the internal DNS of the network
+func (n *network) RemoveDNSAlias(name, alias string, timeout time.Duration) (*pb.NetworkDNS, error) {
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.RemoveDNSAlias(ctx, &pb.NetworkDNSAliasRequest{Network: &pb.Reference{Name: name}, Alias: &pb.NetworkDNSAlias{Alias: alias}})
+}
+
+// Repair recreates the dead gateway(s) of the network with the image os
+func (n *network) Repair(name, os string, timeout time.Duration) (*pb.Network, error) {
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewNetworkServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Repair(ctx, &pb.NetworkRepairRequest{Network: &pb.Reference{Name: name}, ImageId: os})
+}
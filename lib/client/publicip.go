@@ -0,0 +1,109 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"time"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// publicIP is the part of safescale client handling public IPs
+type publicIP struct {
+	// session is not used currently
+	session *Session
+}
+
+// Allocate reserves a new public IP named name
+func (p *publicIP) Allocate(name string, timeout time.Duration) (*pb.PublicIP, error) {
+	p.session.Connect()
+	defer p.session.Disconnect()
+	service := pb.NewPublicIPServiceClient(p.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Allocate(ctx, &pb.Reference{Name: name})
+}
+
+// Attach binds the public IP to host, moving it from the host it's currently attached to if any
+func (p *publicIP) Attach(name, host string, timeout time.Duration) (*pb.PublicIP, error) {
+	p.session.Connect()
+	defer p.session.Disconnect()
+	service := pb.NewPublicIPServiceClient(p.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Attach(ctx, &pb.PublicIPAttachment{PublicIp: &pb.Reference{Name: name}, Host: &pb.Reference{Name: host}})
+}
+
+// Detach unbinds the public IP from its host
+func (p *publicIP) Detach(name string, timeout time.Duration) (*pb.PublicIP, error) {
+	p.session.Connect()
+	defer p.session.Disconnect()
+	service := pb.NewPublicIPServiceClient(p.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Detach(ctx, &pb.Reference{Name: name})
+}
+
+// Release gives back the public IP to the provider
+func (p *publicIP) Release(name string, timeout time.Duration) error {
+	p.session.Connect()
+	defer p.session.Disconnect()
+	service := pb.NewPublicIPServiceClient(p.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Release(ctx, &pb.Reference{Name: name})
+	return err
+}
+
+// List ...
+func (p *publicIP) List(all bool, timeout time.Duration) (*pb.PublicIPList, error) {
+	p.session.Connect()
+	defer p.session.Disconnect()
+	service := pb.NewPublicIPServiceClient(p.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.List(ctx, &pb.PublicIPListRequest{All: all})
+}
+
+// Inspect ...
+func (p *publicIP) Inspect(name string, timeout time.Duration) (*pb.PublicIP, error) {
+	p.session.Connect()
+	defer p.session.Disconnect()
+	service := pb.NewPublicIPServiceClient(p.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Inspect(ctx, &pb.Reference{Name: name})
+}
@@ -127,6 +127,48 @@ func (n *share) Unmount(def *pb.ShareMountDefinition, timeout time.Duration) err
 	return nil
 }
 
+// AddACL allows hosts to mount a share
+func (n *share) AddACL(def *pb.ShareACLDefinition, timeout time.Duration) error {
+	if def == nil {
+		return scerr.InvalidParameterError("def", "cannot be nil")
+	}
+
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewShareServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.AddACL(ctx, def)
+	if err != nil {
+		return DecorateError(err, "addition of share ACL", true)
+	}
+	return nil
+}
+
+// RemoveACL forbids hosts to mount a share
+func (n *share) RemoveACL(def *pb.ShareACLDefinition, timeout time.Duration) error {
+	if def == nil {
+		return scerr.InvalidParameterError("def", "cannot be nil")
+	}
+
+	n.session.Connect()
+	defer n.session.Disconnect()
+	service := pb.NewShareServiceClient(n.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.RemoveACL(ctx, def)
+	if err != nil {
+		return DecorateError(err, "removal of share ACL", true)
+	}
+	return nil
+}
+
 // Inspect ...
 func (n *share) Inspect(name string, timeout time.Duration) (*pb.ShareMountList, error) {
 	n.session.Connect()
@@ -19,6 +19,8 @@ package client
 import (
 	"time"
 
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/server/utils"
 )
@@ -29,8 +31,9 @@ type template struct {
 	session *Session
 }
 
-// List return the list of availble templates on the current tenant
-func (t *template) List(all bool, timeout time.Duration) (*pb.TemplateList, error) {
+// List return the list of availble templates on the current tenant; with scanned, only the templates measured by the
+// scanner are returned, with their measurements
+func (t *template) List(all, scanned bool, timeout time.Duration) (*pb.TemplateList, error) {
 	t.session.Connect()
 	defer t.session.Disconnect()
 	service := pb.NewTemplateServiceClient(t.session.connection)
@@ -38,6 +41,18 @@ func (t *template) List(all bool, timeout time.Duration) (*pb.TemplateList, erro
 	if err != nil {
 		return nil, err
 	}
-	return service.List(ctx, &pb.TemplateListRequest{All: all})
+	return service.List(ctx, &pb.TemplateListRequest{All: all, Scanned: scanned})
+
+}
 
+// Presets returns the presets defined in the configuration of the current tenant
+func (t *template) Presets(timeout time.Duration) (*pb.TemplatePresetList, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+	service := pb.NewTemplateServiceClient(t.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+	return service.Presets(ctx, &googleprotobuf.Empty{})
 }
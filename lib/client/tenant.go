@@ -70,3 +70,16 @@ func (t *tenant) Set(name string, timeout time.Duration) error {
 	_, err = service.Set(ctx, &pb.TenantName{Name: name})
 	return err
 }
+
+// Cost returns the estimated price per hour of the hosts and volumes of the current tenant
+func (t *tenant) Cost(timeout time.Duration) (*pb.TenantCost, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+	service := pb.NewTenantServiceClient(t.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.Cost(ctx, &googleprotobuf.Empty{})
+}
@@ -145,3 +145,20 @@ func (v *volume) Detach(volumeName string, hostName string, timeout time.Duratio
 	})
 	return err
 }
+
+// Migrate moves a volume to another host
+func (v *volume) Migrate(volumeName string, hostName string, timeout time.Duration) error {
+	v.session.Connect()
+	defer v.session.Disconnect()
+	service := pb.NewVolumeServiceClient(v.session.connection)
+	ctx, err := utils.GetContext(true)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.Migrate(ctx, &pb.VolumeMigration{
+		Volume: &pb.Reference{Name: volumeName},
+		Host:   &pb.Reference{Name: hostName},
+	})
+	return err
+}
@@ -23,13 +23,17 @@ func (x *HostSizing) Clone() *HostSizing {
 		return &HostSizing{}
 	}
 	return &HostSizing{
-		MinCpuCount: x.MinCpuCount,
-		MaxCpuCount: x.MaxCpuCount,
-		MinRamSize:  x.MinRamSize,
-		MaxRamSize:  x.MaxRamSize,
-		MinDiskSize: x.MinDiskSize,
-		GpuCount:    x.GpuCount,
-		MinCpuFreq:  x.MinCpuFreq,
+		MinCpuCount:  x.MinCpuCount,
+		MaxCpuCount:  x.MaxCpuCount,
+		MinRamSize:   x.MinRamSize,
+		MaxRamSize:   x.MaxRamSize,
+		MinDiskSize:  x.MinDiskSize,
+		GpuCount:     x.GpuCount,
+		MinCpuFreq:   x.MinCpuFreq,
+		MinDiskSpeed: x.MinDiskSpeed,
+		MinNetSpeed:  x.MinNetSpeed,
+		Replaceable:  x.Replaceable,
+		Cheapest:     x.Cheapest,
 	}
 }
 
@@ -52,5 +56,20 @@ func (x *HostDefinition) Clone() *HostDefinition {
 		Sizing:        x.Sizing.Clone(),
 		Domain:        x.Domain,
 		KeepOnFailure: x.KeepOnFailure,
+		Networks:      append([]string(nil), x.Networks...),
+		UserData:      x.UserData,
+		Lifecycle:     x.Lifecycle.Clone(),
+	}
+}
+
+// Clone makes a copy of a HostLifecycle
+func (x *HostLifecycle) Clone() *HostLifecycle {
+	if x == nil {
+		return nil
+	}
+	return &HostLifecycle{
+		ExpiresIn: x.ExpiresIn,
+		OnExpiry:  x.OnExpiry,
+		IdleStop:  x.IdleStop,
 	}
 }
@@ -54,6 +54,8 @@ type Cluster interface {
 	DeleteLastNode(concurrency.Task, string) error
 	// DeleteSpecificNode deletes a node identified by its ID
 	DeleteSpecificNode(concurrency.Task, string, string) error
+	// ReplaceReclaimedNodes replaces the spot nodes reclaimed by the provider
+	ReplaceReclaimedNodes(concurrency.Task) ([]string, error)
 	// ListMasters lists the masters (if there is such masters in the flavor...)
 	ListMasters(concurrency.Task) []*propsv1.Node
 	// ListMasterNames lists the names of masters (if there is such masters in the flavor...)
@@ -712,6 +712,7 @@ func (c *Controller) getImageAndNodeDescriptionUsedInClusterFromMetadata(task co
 		defaultsV2 := clonable.(*clusterpropsv2.Defaults)
 		sizing := srvutils.ToPBHostSizing(defaultsV2.NodeSizing)
 		nodeDef.Sizing = sizing
+		nodeDef.UserData = defaultsV2.UserData
 		hostImage = defaultsV2.Image
 		return nil
 	})
@@ -1104,6 +1105,76 @@ func (c *Controller) DeleteSpecificNode(task concurrency.Task, hostID string, se
 	return c.deleteNode(task, node, selectedMaster)
 }
 
+// ReplaceReclaimedNodes replaces the spot nodes that have been reclaimed by the provider with new spot nodes,
+// and returns the IDs of the new nodes
+func (c *Controller) ReplaceReclaimedNodes(task concurrency.Task) (hosts []string, err error) {
+	if c == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if task == nil {
+		return nil, scerr.InvalidParameterError("task", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(task, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	// A stopped cluster has stopped nodes, that cannot be told apart from reclaimed ones on some providers
+	state, err := c.GetState(task)
+	if err != nil {
+		return nil, err
+	}
+	if state != clusterstate.Nominal && state != clusterstate.Degraded {
+		return nil, nil
+	}
+
+	var reclaimed []*clusterpropsv1.Node
+	for _, node := range c.ListNodes(task) {
+		if !node.Spot {
+			continue
+		}
+		// A stopped or failed node may have been stopped by someone, or be repaired; only the provider can tell if
+		// it has been reclaimed (GCP keeps preempted VMs as stopped ones, for instance)
+		isReclaimed, err := c.GetService(task).IsHostReclaimed(node.ID)
+		if err != nil {
+			log.Warnf("failed to tell if node '%s' has been reclaimed: %v", node.Name, err)
+			continue
+		}
+		if isReclaimed {
+			reclaimed = append(reclaimed, node)
+		}
+	}
+	if len(reclaimed) == 0 {
+		return nil, nil
+	}
+
+	// The reclaimed hosts cannot leave the cluster by themselves, only the remains are deleted
+	var errs []error
+	deleted := 0
+	for _, node := range reclaimed {
+		log.Infof("Spot node '%s' of cluster '%s' has been reclaimed, replacing it", node.Name, c.Identity.Name)
+		err = c.deleteNode(task, node, "")
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete reclaimed node '%s': %v", node.Name, err))
+			continue
+		}
+		deleted++
+	}
+	if deleted > 0 {
+		def := &pb.HostDefinition{
+			Sizing: &pb.HostSizing{Replaceable: true},
+		}
+		hosts, err = c.AddNodes(task, deleted, def)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return hosts, scerr.ErrListError(errs)
+	}
+	return hosts, nil
+}
+
 // deleteNode deletes the node specified by its ID
 func (c *Controller) deleteNode(task concurrency.Task, node *clusterpropsv1.Node, selectedMaster string) (err error) {
 	if c == nil {
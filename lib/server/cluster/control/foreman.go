@@ -279,6 +279,12 @@ func (b *foreman) construct(task concurrency.Task, req Request) (err error) {
 	nodesDefault.ImageId = imageID
 	nodesDef := complementHostDefinition(req.NodesDef, nodesDefault)
 
+	// The script supplied by the user is run on masters and nodes; gateways belong to the network
+	if req.UserData != "" {
+		mastersDef.UserData = req.UserData
+		nodesDef.UserData = req.UserData
+	}
+
 	// Initialize service to use
 	clientInstance := client.New()
 	tenant, err := clientInstance.Tenant.Get(temporal.GetExecutionTimeout())
@@ -306,11 +312,12 @@ func (b *foreman) construct(task concurrency.Task, req Request) (err error) {
 	networkName := "net-" + req.Name
 	sizing := srvutils.FromPBHostDefinitionToPBGatewayDefinition(gatewaysDef)
 	def := pb.NetworkDefinition{
-		Name:     networkName,
-		Cidr:     req.CIDR,
-		Gateway:  sizing,
-		FailOver: !gwFailoverDisabled,
-		Domain:   req.Domain,
+		Name:       networkName,
+		Cidr:       req.CIDR,
+		SubnetSize: int32(req.SubnetSize),
+		Gateway:    sizing,
+		FailOver:   !gwFailoverDisabled,
+		Domain:     req.Domain,
 	}
 	clientNetwork := clientInstance.Network
 	network, err := clientNetwork.Create(&def, temporal.GetExecutionTimeout())
@@ -319,6 +326,8 @@ func (b *foreman) construct(task concurrency.Task, req Request) (err error) {
 	}
 	logrus.Debugf("[cluster %s] network '%s' creation successful.", req.Name, networkName)
 	req.NetworkID = network.Id
+	// The CIDR may have been allocated by safescaled
+	req.CIDR = network.Cidr
 
 	defer func() {
 		if err != nil && !req.KeepOnFailure {
@@ -405,6 +414,7 @@ func (b *foreman) construct(task concurrency.Task, req Request) (err error) {
 			defaultsV2.MasterSizing = srvutils.FromPBHostSizing(mastersDef.Sizing)
 			defaultsV2.NodeSizing = srvutils.FromPBHostSizing(nodesDef.Sizing)
 			defaultsV2.Image = imageID
+			defaultsV2.UserData = req.UserData
 			return nil
 		})
 		if err != nil {
@@ -829,6 +839,9 @@ func complementHostDefinition(req *pb.HostDefinition, def *pb.HostDefinition) *p
 
 	//finalDef := srvutils.ClonePBHostDefinition(req)
 	finalDef := req.Clone()
+	if finalDef.UserData == "" {
+		finalDef.UserData = def.UserData
+	}
 	if finalDef.Sizing == nil {
 		finalDef.Sizing = srvutils.ClonePBHostSizing(def.Sizing)
 		return finalDef
@@ -855,6 +868,18 @@ func complementHostDefinition(req *pb.HostDefinition, def *pb.HostDefinition) *p
 	if finalDef.Sizing.MinCpuFreq == 0 && def.Sizing.MinCpuFreq > 0 {
 		finalDef.Sizing.MinCpuFreq = def.Sizing.MinCpuFreq
 	}
+	if finalDef.Sizing.MinDiskSpeed == 0 && def.Sizing.MinDiskSpeed > 0 {
+		finalDef.Sizing.MinDiskSpeed = def.Sizing.MinDiskSpeed
+	}
+	if finalDef.Sizing.MinNetSpeed == 0 && def.Sizing.MinNetSpeed > 0 {
+		finalDef.Sizing.MinNetSpeed = def.Sizing.MinNetSpeed
+	}
+	if !finalDef.Sizing.Replaceable && def.Sizing.Replaceable {
+		finalDef.Sizing.Replaceable = def.Sizing.Replaceable
+	}
+	if !finalDef.Sizing.Cheapest && def.Sizing.Cheapest {
+		finalDef.Sizing.Cheapest = def.Sizing.Cheapest
+	}
 	if finalDef.ImageId == "" {
 		finalDef.ImageId = def.ImageId
 	}
@@ -888,7 +913,7 @@ func (b *foreman) getState(task concurrency.Task) (clusterstate.Enum, error) {
 		stateV1 = clonable.(*clusterpropsv1.State).State
 		return nil
 	})
-	if err != nil{
+	if err != nil {
 		return clusterstate.Unknown, err
 	}
 	return stateV1, nil
@@ -2140,6 +2165,7 @@ func (b *foreman) taskCreateNode(t concurrency.Task, params concurrency.TaskPara
 					Name:      pbHost.Name,
 					PrivateIP: pbHost.PrivateIp,
 					PublicIP:  pbHost.PublicIp,
+					Spot:      hostDef.Sizing != nil && hostDef.Sizing.Replaceable,
 				}
 				nodesV1.PrivateNodes = append(nodesV1.PrivateNodes, node)
 				return nil
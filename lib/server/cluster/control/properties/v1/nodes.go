@@ -27,10 +27,11 @@ import (
 // Note: if tagged as FROZEN, must not be changed ever.
 //       Create a new version instead with updated/additional fields
 type Node struct {
-	ID        string `json:"id"`         // ID of the node
-	Name      string `json:"name"`       // Name of the node
-	PublicIP  string `json:"public_ip"`  // public ip of the node
-	PrivateIP string `json:"private_ip"` // private ip of the node
+	ID        string `json:"id"`             // ID of the node
+	Name      string `json:"name"`           // Name of the node
+	PublicIP  string `json:"public_ip"`      // public ip of the node
+	PrivateIP string `json:"private_ip"`     // private ip of the node
+	Spot      bool   `json:"spot,omitempty"` // tells if the node is a spot host, replaced when reclaimed by the provider
 }
 
 // Nodes ...
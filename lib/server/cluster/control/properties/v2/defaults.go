@@ -37,6 +37,8 @@ type Defaults struct {
 	NodeSizing resources.SizingRequirements `json:"node_sizing"`
 	// Image keeps the default Linux image to use
 	Image string `json:"image"`
+	// UserData keeps the script supplied by the user, run on nodes added later too
+	UserData string `json:"user_data,omitempty"`
 }
 
 func newDefaults() *Defaults {
@@ -26,8 +26,10 @@ import (
 type Request struct {
 	// Name is the name of the cluster wanted
 	Name string
-	// CIDR defines the network to create
+	// CIDR defines the network to create; if empty, a CIDR is allocated from the address pool of the tenant
 	CIDR string
+	// SubnetSize is the prefix length of the CIDR allocated when CIDR is empty
+	SubnetSize int
 	// Domain defines the domain to use to build host names
 	Domain string
 	// Complexity is the implementation wanted, can be Small, Normal or Large
@@ -48,4 +50,6 @@ type Request struct {
 	NodesDef *pb.HostDefinition
 	// DisabledDefaultFeatures contains the list of features that should be installed by default but we don't want actually
 	DisabledDefaultFeatures map[string]struct{}
+	// UserData contains a script supplied by the user, run on masters and nodes once configured by SafeScale
+	UserData string
 }
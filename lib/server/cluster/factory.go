@@ -30,6 +30,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/cluster/flavors/k8s"
 	"github.com/CS-SI/SafeScale/lib/server/cluster/flavors/swarm"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
@@ -96,9 +97,6 @@ func Create(task concurrency.Task, req control.Request) (_ api.Cluster, err erro
 	if req.Name == "" {
 		return nil, scerr.InvalidParameterError("req.Name", "cannot be empty!")
 	}
-	if req.CIDR == "" {
-		return nil, scerr.InvalidParameterError("req.CIDR", "cannot be empty!")
-	}
 
 	log.Infof("Creating infrastructure for cluster '%s'", req.Name)
 
@@ -188,6 +186,36 @@ func List() (clusterList []api.Cluster, err error) {
 	return clusterList, err
 }
 
+// ReplaceReclaimedNodes replaces, in every cluster, the spot nodes that have been reclaimed by the provider
+func ReplaceReclaimedNodes(task concurrency.Task) error {
+	list, err := List()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, item := range list {
+		name := item.GetIdentity(task).Name
+		instance, err := Load(task, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to load cluster '%s': %v", name, err))
+			continue
+		}
+		hosts, err := instance.ReplaceReclaimedNodes(task)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to replace reclaimed nodes of cluster '%s': %v", name, err))
+			continue
+		}
+		if len(hosts) > 0 {
+			log.Infof("Replaced %d reclaimed node%s of cluster '%s'", len(hosts), utils.Plural(len(hosts)), name)
+		}
+	}
+	if len(errs) > 0 {
+		return scerr.ErrListError(errs)
+	}
+	return nil
+}
+
 // // Sanitize ...
 // func Sanitize(svc *providers.Service, name string) error {
 // m, err := control.NewMetadata(svc)
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
+)
+
+//go:generate mockgen -destination=../mocks/mock_auditapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers AuditAPI
+
+// AuditAPI defines API to manipulate the audit trail
+type AuditAPI interface {
+	Record(ctx context.Context, event *metadata.AuditEvent) error
+	List(ctx context.Context, since time.Time, kind string) ([]*metadata.AuditEvent, error)
+}
+
+// AuditRecorder records in the audit trail an action done by safescaled itself
+type AuditRecorder func(ctx context.Context, event *metadata.AuditEvent)
+
+// AuditHandler audit service
+type AuditHandler struct {
+	service iaas.Service
+}
+
+// NewAuditHandler creates an audit service
+func NewAuditHandler(svc iaas.Service) AuditAPI {
+	return &AuditHandler{
+		service: svc,
+	}
+}
+
+// Record stores an audit event in the metadata of the tenant
+func (handler *AuditHandler) Record(ctx context.Context, event *metadata.AuditEvent) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if event == nil {
+		return scerr.InvalidParameterError("event", "cannot be nil")
+	}
+
+	ma, err := metadata.NewAudit(handler.service)
+	if err != nil {
+		return err
+	}
+	return ma.Write(event)
+}
+
+// List returns the audit events since a date, concerning a kind of resource (all kinds if empty)
+func (handler *AuditHandler) List(ctx context.Context, since time.Time, kind string) (events []*metadata.AuditEvent, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%s, '%s')", since.String(), kind), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "AuditHandler.List")
+	defer tracing.EndSpan(span, &err)
+	handler = &AuditHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	ma, err := metadata.NewAudit(handler.service)
+	if err != nil {
+		return nil, err
+	}
+	err = ma.Browse(since, kind, func(event *metadata.AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -19,13 +19,19 @@ package handlers
 import (
 	"context"
 	"fmt"
-	"regexp"
+	"strings"
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/system"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
 )
 
 //go:generate mockgen -destination=../mocks/mock_bucketapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers BucketAPI
@@ -60,6 +66,9 @@ func (handler *BucketHandler) List(ctx context.Context) (rv []string, err error)
 	tracer := concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "BucketHandler.List")
+	defer tracing.EndSpan(span, &err)
+	handler = &BucketHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	rv, err = handler.service.ListBuckets(objectstorage.RootPath)
 	return rv, err
@@ -77,6 +86,9 @@ func (handler *BucketHandler) Create(ctx context.Context, name string) (err erro
 	tracer := concurrency.NewTracer(nil, "('"+name+"')", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "BucketHandler.Create")
+	defer tracing.EndSpan(span, &err)
+	handler = &BucketHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	bucket, err := handler.service.GetBucket(name)
 	if err != nil {
@@ -99,6 +111,9 @@ func (handler *BucketHandler) Destroy(ctx context.Context, name string) (err err
 	tracer := concurrency.NewTracer(nil, "('"+name+"')", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "BucketHandler.Destroy")
+	defer tracing.EndSpan(span, &err)
+	handler = &BucketHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	err = handler.service.ClearBucket(name, "/", "")
 	if err != nil {
@@ -117,6 +132,9 @@ func (handler *BucketHandler) Delete(ctx context.Context, name string) (err erro
 	tracer := concurrency.NewTracer(nil, "('"+name+"')", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "BucketHandler.Delete")
+	defer tracing.EndSpan(span, &err)
+	handler = &BucketHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	err = handler.service.DeleteBucket(name)
 	if err != nil {
@@ -130,6 +148,9 @@ func (handler *BucketHandler) Inspect(ctx context.Context, name string) (mb *res
 	tracer := concurrency.NewTracer(nil, "('"+name+"')", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "BucketHandler.Inspect")
+	defer tracing.EndSpan(span, &err)
+	handler = &BucketHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	b, err := handler.service.GetBucket(name)
 	if err != nil {
@@ -144,11 +165,45 @@ func (handler *BucketHandler) Inspect(ctx context.Context, name string) (mb *res
 	return mb, nil
 }
 
+// bucketMountScriptData contains the data used by the scripts mounting and unmounting buckets
+type bucketMountScriptData struct {
+	BashLibrary string
+	Bucket      string
+	MountPoint  string
+	Backend     string
+	AuthURL     string
+	AuthVersion int
+	User        string
+	Password    string
+	Tenant      string
+	Domain      string
+	Region      string
+	Endpoint    string
+	Credentials string
+}
+
+// bucketMountBackend returns the FUSE backend used to mount the buckets of an Object Storage of type storageType
+func bucketMountBackend(storageType string) (string, error) {
+	switch storageType {
+	case "swift":
+		return "rclone", nil
+	case "s3":
+		return "s3fs", nil
+	case "google":
+		return "gcsfuse", nil
+	default:
+		return "", scerr.NotImplementedError(fmt.Sprintf("mount of buckets of Object Storage type '%s' is not supported", storageType))
+	}
+}
+
 // Mount a bucket on an host on the given mount point
 func (handler *BucketHandler) Mount(ctx context.Context, bucketName, hostName, path string) (err error) {
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', '%s')", bucketName, hostName, path), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "BucketHandler.Mount")
+	defer tracing.EndSpan(span, &err)
+	handler = &BucketHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	// Check bucket existence
 	_, err = handler.service.GetBucket(bucketName)
@@ -169,46 +224,92 @@ func (handler *BucketHandler) Mount(ctx context.Context, bucketName, hostName, p
 		mountPoint = resources.DefaultBucketMountPoint + bucketName
 	}
 
-	authOpts, _ := handler.service.GetAuthenticationOptions()
-	authurlCfg, _ := authOpts.Config("AuthUrl")
-	authurl := authurlCfg.(string)
-	authurl = regexp.MustCompile("https?:/+(.*)/.*").FindStringSubmatch(authurl)[1]
-	tenantCfg, _ := authOpts.Config("TenantName")
-	tenant := tenantCfg.(string)
-	loginCfg, _ := authOpts.Config("Login")
-	login := loginCfg.(string)
-	passwordCfg, _ := authOpts.Config("Password")
-	password := passwordCfg.(string)
-	regionCfg, _ := authOpts.Config("Region")
-	region := regionCfg.(string)
-
-	objStorageProtocol := handler.service.GetType()
-	if objStorageProtocol == "swift" {
-		objStorageProtocol = "swiftks"
-	}
-
-	data := struct {
-		Bucket     string
-		Tenant     string
-		Login      string
-		Password   string
-		AuthURL    string
-		Region     string
-		MountPoint string
-		Protocol   string
-	}{
-		Bucket:     bucketName,
-		Tenant:     tenant,
-		Login:      login,
-		Password:   password,
-		AuthURL:    authurl,
-		Region:     region,
-		MountPoint: mountPoint,
-		Protocol:   objStorageProtocol,
-	}
-
-	rerr := exec(ctx, "mount_object_storage.sh", data, host.ID, handler.service)
-	return rerr
+	// Check the bucket isn't already mounted and the mount point is free
+	err = host.Properties.LockForRead(hostproperty.MountsV1).ThenUse(func(clonable data.Clonable) error {
+		hostMountsV1 := clonable.(*propsv1.HostMounts)
+		if p, ok := hostMountsV1.RemoteMountsByExport[bucketName]; ok {
+			return fmt.Errorf("bucket '%s' is already mounted in '%s:%s'", bucketName, host.Name, p)
+		}
+		for _, i := range hostMountsV1.LocalMountsByPath {
+			if i.Path == mountPoint {
+				return fmt.Errorf("there is already a volume in path '%s:%s'", host.Name, mountPoint)
+			}
+		}
+		for _, i := range hostMountsV1.RemoteMountsByPath {
+			if strings.Index(mountPoint, i.Path) == 0 {
+				return fmt.Errorf("there is already a share or a bucket mounted in '%s:%s'", host.Name, i.Path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	cfg := handler.service.GetConfig()
+	backend, err := bucketMountBackend(cfg.Type)
+	if err != nil {
+		return err
+	}
+	bashLibrary, err := system.GetBashLibrary()
+	if err != nil {
+		return err
+	}
+	scriptData := bucketMountScriptData{
+		BashLibrary: bashLibrary,
+		Bucket:      bucketName,
+		MountPoint:  mountPoint,
+		Backend:     backend,
+		AuthURL:     cfg.AuthURL,
+		AuthVersion: cfg.AuthVersion,
+		User:        cfg.User,
+		Password:    cfg.SecretKey,
+		Tenant:      cfg.Tenant,
+		Domain:      cfg.Domain,
+		Region:      cfg.Region,
+		Endpoint:    cfg.Endpoint,
+		Credentials: cfg.Credentials,
+	}
+	if scriptData.Domain == "" {
+		scriptData.Domain = "Default"
+	}
+	if scriptData.AuthVersion == 0 && strings.HasSuffix(strings.TrimSuffix(scriptData.AuthURL, "/"), "/v3") {
+		scriptData.AuthVersion = 3
+	}
+
+	err = exec(ctx, "mount_object_storage.sh", scriptData, host.ID, handler.service)
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("failed to mount bucket '%s' on host '%s' with %s", bucketName, host.Name, backend))
+	}
+	defer func() {
+		if err != nil {
+			derr := exec(ctx, "umount_object_storage.sh", bucketMountScriptData{Bucket: bucketName}, host.ID, handler.service)
+			if derr != nil {
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	// Records the mount in host metadata
+	err = host.Properties.LockForWrite(hostproperty.MountsV1).ThenUse(func(clonable data.Clonable) error {
+		hostMountsV1 := clonable.(*propsv1.HostMounts)
+		// Make sure the HostMounts is correctly init if there are no mount yet
+		if !host.Properties.Lookup(hostproperty.MountsV1) {
+			hostMountsV1.Reset()
+		}
+		mount := propsv1.NewHostRemoteMount()
+		mount.Export = bucketName
+		mount.Path = mountPoint
+		mount.FileSystem = "fuse." + backend
+		hostMountsV1.RemoteMountsByPath[mount.Path] = mount
+		hostMountsV1.RemoteMountsByExport[mount.Export] = mount.Path
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = metadata.SaveHost(handler.service, host)
+	return err
 }
 
 // Unmount a bucket
@@ -216,6 +317,9 @@ func (handler *BucketHandler) Unmount(ctx context.Context, bucketName, hostName
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", bucketName, hostName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "BucketHandler.Unmount")
+	defer tracing.EndSpan(span, &err)
+	handler = &BucketHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	// Check bucket existence
 	_, err = handler.Inspect(ctx, bucketName)
@@ -236,12 +340,26 @@ func (handler *BucketHandler) Unmount(ctx context.Context, bucketName, hostName
 		return err
 	}
 
-	data := struct {
-		Bucket string
-	}{
+	scriptData := bucketMountScriptData{
 		Bucket: bucketName,
 	}
+	err = exec(ctx, "umount_object_storage.sh", scriptData, host.ID, handler.service)
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("failed to unmount bucket '%s' from host '%s'", bucketName, host.Name))
+	}
 
-	rerr := exec(ctx, "umount_object_storage.sh", data, host.ID, handler.service)
-	return rerr
+	// Removes the mount from host metadata (buckets mounted by previous releases are not recorded)
+	err = host.Properties.LockForWrite(hostproperty.MountsV1).ThenUse(func(clonable data.Clonable) error {
+		hostMountsV1 := clonable.(*propsv1.HostMounts)
+		if p, ok := hostMountsV1.RemoteMountsByExport[bucketName]; ok {
+			delete(hostMountsV1.RemoteMountsByPath, p)
+			delete(hostMountsV1.RemoteMountsByExport, bucketName)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = metadata.SaveHost(handler.service, host)
+	return err
 }
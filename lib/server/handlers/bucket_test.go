@@ -0,0 +1,38 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketMountBackend(t *testing.T) {
+	for storageType, expected := range map[string]string{
+		"swift":  "rclone",
+		"s3":     "s3fs",
+		"google": "gcsfuse",
+	} {
+		backend, err := bucketMountBackend(storageType)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, backend)
+	}
+
+	_, err := bucketMountBackend("azure")
+	assert.NotNil(t, err)
+}
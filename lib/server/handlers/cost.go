@@ -0,0 +1,160 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
+)
+
+//go:generate mockgen -destination=../mocks/mock_costapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers CostAPI
+
+// CostAPI defines API to estimate the cost of the resources of a tenant
+type CostAPI interface {
+	Estimate(ctx context.Context) (string, []*ResourceCost, error)
+}
+
+// ResourceCost contains the estimated price per hour of a host or a volume recorded in metadata
+type ResourceCost struct {
+	Kind         string
+	ID           string
+	Name         string
+	NetworkID    string // network of the host, or of the host the volume is attached to
+	NetworkName  string
+	PricePerHour float64
+	Priced       bool // false if no price is known for the template of the host or the speed of the volume
+}
+
+// CostHandler cost service
+type CostHandler struct {
+	service iaas.Service
+}
+
+// NewCostHandler creates a cost service
+func NewCostHandler(svc iaas.Service) CostAPI {
+	return &CostHandler{
+		service: svc,
+	}
+}
+
+// Estimate returns the currency of the prices and the price per hour of each host and volume recorded in metadata
+func (handler *CostHandler) Estimate(ctx context.Context) (currency string, costs []*ResourceCost, err error) {
+	if handler == nil {
+		return "", nil, scerr.InvalidInstanceError()
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "CostHandler.Estimate")
+	defer tracing.EndSpan(span, &err)
+	handler = &CostHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	templates, err := handler.service.ListTemplates(true)
+	if err != nil {
+		return "", nil, err
+	}
+	prices := map[string]float64{}
+	for _, tpl := range templates {
+		if tpl.PricePerHour > 0 {
+			prices[tpl.ID] = tpl.PricePerHour
+			prices[tpl.Name] = tpl.PricePerHour
+		}
+	}
+
+	networkNames := map[string]string{}
+	mn, err := metadata.NewNetwork(handler.service)
+	if err != nil {
+		return "", nil, err
+	}
+	err = mn.Browse(func(network *resources.Network) error {
+		networkNames[network.ID] = network.Name
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The network of a volume is the one of the host it is attached to
+	volumeNetworks := map[string]string{}
+	mh, err := metadata.NewHost(handler.service)
+	if err != nil {
+		return "", nil, err
+	}
+	err = mh.Browse(func(host *resources.Host) error {
+		cost := &ResourceCost{Kind: "host", ID: host.ID, Name: host.Name}
+		err := host.Properties.LockForRead(hostproperty.SizingV1).ThenUse(func(clonable data.Clonable) error {
+			cost.PricePerHour, cost.Priced = prices[clonable.(*propsv1.HostSizing).Template]
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		err = host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+			hostNetworkV1 := clonable.(*propsv1.HostNetwork)
+			cost.NetworkID = hostNetworkV1.DefaultNetworkID
+			if cost.NetworkID == "" && len(hostNetworkV1.NetworksByID) == 1 {
+				for k := range hostNetworkV1.NetworksByID {
+					cost.NetworkID = k
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		cost.NetworkName = networkNames[cost.NetworkID]
+		costs = append(costs, cost)
+
+		return host.Properties.LockForRead(hostproperty.VolumesV1).ThenUse(func(clonable data.Clonable) error {
+			for id := range clonable.(*propsv1.HostVolumes).VolumesByID {
+				volumeNetworks[id] = cost.NetworkID
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	mv, err := metadata.NewVolume(handler.service)
+	if err != nil {
+		return "", nil, err
+	}
+	err = mv.Browse(func(volume *resources.Volume) error {
+		cost := &ResourceCost{Kind: "volume", ID: volume.ID, Name: volume.Name}
+		cost.PricePerHour, cost.Priced = handler.service.GetVolumePrice(volume)
+		cost.NetworkID = volumeNetworks[volume.ID]
+		cost.NetworkName = networkNames[cost.NetworkID]
+		costs = append(costs, cost)
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return handler.service.GetCurrency(), costs, nil
+}
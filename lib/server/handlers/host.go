@@ -50,6 +50,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/retry/enums/verdict"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
 )
 
 //go:generate mockgen -destination=../mocks/mock_hostapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers HostAPI
@@ -58,7 +59,7 @@ import (
 
 // HostAPI defines API to manipulate hosts
 type HostAPI interface {
-	Create(ctx context.Context, name string, net string, os string, public bool, sizingParam interface{}, force bool, domain string, keeponfailure bool) (*resources.Host, error)
+	Create(ctx context.Context, name string, nets []string, os string, public bool, sizingParam interface{}, force bool, domain string, userData string, keeponfailure bool) (*resources.Host, error)
 	List(ctx context.Context, all bool) ([]*resources.Host, error)
 	ForceInspect(ctx context.Context, ref string) (*resources.Host, error)
 	Inspect(ctx context.Context, ref string) (*resources.Host, error)
@@ -68,6 +69,9 @@ type HostAPI interface {
 	Resize(ctx context.Context, name string, cpu int, ram float32, disk int, gpuNumber int, freq float32) (*resources.Host, error)
 	Start(ctx context.Context, ref string) error
 	Stop(ctx context.Context, ref string) error
+	ConsoleLog(ctx context.Context, ref string) (string, error)
+	SetLifecycle(ctx context.Context, ref string, expiresIn time.Duration, onExpiry string, idleStop time.Duration) error
+	EnforceLifecycles(ctx context.Context, record AuditRecorder) error
 }
 
 // HostHandler host service
@@ -87,6 +91,9 @@ func (handler *HostHandler) Start(ctx context.Context, ref string) (err error) {
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.Start")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	mh, err := metadata.LoadHost(handler.service, ref)
 	if err != nil {
@@ -125,6 +132,9 @@ func (handler *HostHandler) Stop(ctx context.Context, ref string) (err error) {
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.Stop")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	mh, err := metadata.LoadHost(handler.service, ref)
 	if err != nil {
@@ -167,6 +177,9 @@ func (handler *HostHandler) Reboot(ctx context.Context, ref string) (err error)
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.Reboot")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	mh, err := metadata.LoadHost(handler.service, ref)
 	if err != nil {
@@ -215,6 +228,9 @@ func (handler *HostHandler) Resize(ctx context.Context, ref string, cpu int, ram
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d, %.02f, %d, %d, %.02f)", ref, cpu, ram, disk, gpuNumber, freq), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.Resize")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	mh, err := metadata.LoadHost(handler.service, ref)
 	if err != nil {
@@ -289,13 +305,15 @@ func (handler *HostHandler) Resize(ctx context.Context, ref string, cpu int, ram
 }
 
 // Create creates a host
+// The host is attached to all the networks in nets; the first one is the default network of the host,
+// used for the default route
 // func (handler *HostHandler) Create(
 // 	ctx context.Context,
 // 	name string, net string, cpu int, ram float32, disk int, los string, public bool, gpuNumber int, freq float32,
 // 	force bool,
 func (handler *HostHandler) Create(
 	ctx context.Context,
-	name string, net string, los string, public bool, sizingParam interface{}, force bool, domain string, keeponfailure bool) (newHost *resources.Host, err error) {
+	name string, nets []string, los string, public bool, sizingParam interface{}, force bool, domain string, userData string, keeponfailure bool) (newHost *resources.Host, err error) {
 
 	if handler == nil {
 		return nil, scerr.InvalidInstanceError()
@@ -307,9 +325,12 @@ func (handler *HostHandler) Create(
 		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
 	}
 
-	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', '%s', %v, <sizingParam>, %v)", name, net, los, public, force), true).WithStopwatch().GoingIn()
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', '%s', %v, <sizingParam>, %v)", name, strings.Join(nets, ","), los, public, force), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.Create")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	var (
 		sizing       *resources.SizingRequirements
@@ -323,6 +344,10 @@ func (handler *HostHandler) Create(
 	default:
 		return nil, scerr.InvalidParameterError("sizing", "must be *resources.SizingRequirements or string")
 	}
+	spot := sizing != nil && sizing.Replaceable
+	if spot && !handler.service.GetCapabilities().SpotHosts {
+		return nil, scerr.NotImplementedError("the provider of the tenant does not support spot or preemptible hosts")
+	}
 
 	host, err := handler.service.GetHostByName(name)
 	if err != nil {
@@ -351,19 +376,31 @@ func (handler *HostHandler) Create(
 		// secondaryGateway *resources.Host
 		defaultRouteIP string
 	)
+	net := ""
+	if len(nets) > 0 {
+		net = nets[0]
+	}
 	if net != "" && net != "net-safescale" {
 		networkHandler := NewNetworkHandler(handler.service)
-		defaultNetwork, err = networkHandler.Inspect(ctx, net)
-		if err != nil {
-			if _, ok := err.(scerr.ErrNotFound); ok {
+		for _, n := range nets {
+			network, err := networkHandler.Inspect(ctx, n)
+			if err != nil {
+				if _, ok := err.(scerr.ErrNotFound); ok {
+					return nil, err
+				}
 				return nil, err
 			}
-			return nil, err
-		}
-		if defaultNetwork == nil {
-			return nil, fmt.Errorf("failed to find network '%s'", net)
+			if network == nil {
+				return nil, fmt.Errorf("failed to find network '%s'", n)
+			}
+			for _, i := range networks {
+				if i.ID == network.ID {
+					return nil, scerr.InvalidParameterError("nets", fmt.Sprintf("network '%s' is given more than once", n))
+				}
+			}
+			networks = append(networks, network)
 		}
-		networks = append(networks, defaultNetwork)
+		defaultNetwork = networks[0]
 
 		mgw, err := metadata.LoadHost(handler.service, defaultNetwork.GatewayID)
 		if err != nil {
@@ -407,6 +444,9 @@ func (handler *HostHandler) Create(
 				msg += fmt.Sprintf(" at %.01f GHz", template.CPUFreq)
 			}
 			msg += fmt.Sprintf(", %.01f GB RAM, %d GB disk", template.RAMSize, template.DiskSize)
+			if template.PricePerHour > 0 {
+				msg += fmt.Sprintf(", %.04f %s/hour", template.PricePerHour, handler.service.GetCurrency())
+			}
 			if template.GPUNumber > 0 {
 				msg += fmt.Sprintf(", %d GPU%s", template.GPUNumber, utils.Plural(template.GPUNumber))
 				if template.GPUType != "" {
@@ -465,13 +505,26 @@ func (handler *HostHandler) Create(
 		Networks:       networks,
 		DefaultRouteIP: defaultRouteIP,
 		DefaultGateway: primaryGateway,
+		Spot:           spot,
+		UserData:       userData,
+	}
+	// Resolves the names of the hosts of the default network with its internal DNS, if any
+	if defaultNetwork != nil {
+		dns, err := (&NetworkHandler{service: handler.service}).loadDNS(defaultNetwork)
+		if err != nil {
+			return nil, err
+		}
+		if dns.Enabled {
+			hostRequest.DNSServers = []string{defaultRouteIP}
+			hostRequest.DNSDomain = dns.Domain
+		}
 	}
 
-	var userData *userdata.Content
+	var userDataContent *userdata.Content
 	retryErr = retryOnCommunicationFailure(
 		func() error {
 			var innerErr error
-			host, userData, innerErr = handler.service.CreateHost(hostRequest)
+			host, userDataContent, innerErr = handler.service.CreateHost(hostRequest)
 			return innerErr
 		},
 		0,
@@ -600,6 +653,7 @@ func (handler *HostHandler) Create(
 		hostDescriptionV1.Created = time.Now()
 		hostDescriptionV1.Creator = creator
 		hostDescriptionV1.Domain = domain
+		hostDescriptionV1.Spot = spot
 		return nil
 	})
 	if err != nil {
@@ -630,16 +684,10 @@ func (handler *HostHandler) Create(
 		hostNetworkV1.DefaultGatewayID = gatewayID
 
 		if net != "" {
-			mn, err := metadata.LoadNetwork(handler.service, net)
-			if err != nil {
-				return err
-			}
-			network, err := mn.Get()
-			if err != nil {
-				return err
+			for _, network := range networks {
+				hostNetworkV1.NetworksByID[network.ID] = network.Name
+				hostNetworkV1.NetworksByName[network.Name] = network.ID
 			}
-			hostNetworkV1.NetworksByID[network.ID] = network.Name
-			hostNetworkV1.NetworksByName[network.Name] = network.ID
 		}
 
 		return nil
@@ -669,7 +717,7 @@ func (handler *HostHandler) Create(
 		derr := err
 		err = nil
 		if client.IsTimeoutError(derr) {
-			return nil, scerr.Wrap(derr, fmt.Sprintf("timeout waiting host '%s' to become ready", host.Name))
+			return nil, scerr.Wrap(derr, handler.withConsoleTail(fmt.Sprintf("timeout waiting host '%s' to become ready", host.Name), host.ID))
 		}
 
 		if client.IsProvisioningError(derr) {
@@ -700,7 +748,7 @@ func (handler *HostHandler) Create(
 	}
 
 	// Executes userdata phase2 script to finalize host installation
-	userDataPhase2, err := userData.Generate("phase2")
+	userDataPhase2, err := userDataContent.Generate("phase2")
 	if err != nil {
 		return nil, err
 	}
@@ -779,7 +827,7 @@ func (handler *HostHandler) Create(
 	_, err = sshCfg.WaitServerReady("ready", temporal.GetConnectSSHTimeout())
 	if err != nil {
 		if client.IsTimeoutError(err) {
-			return nil, err
+			return nil, scerr.Wrap(err, handler.withConsoleTail(fmt.Sprintf("timeout waiting host '%s' to restart", host.Name), host.ID))
 		}
 
 		if client.IsProvisioningError(err) {
@@ -791,6 +839,21 @@ func (handler *HostHandler) Create(
 	}
 	logrus.Infof("SSH service started on host '%s'.", host.Name)
 
+	// Executes the script supplied by the user, once SafeScale has finished its own configuration
+	if userDataContent.HasPhase3() {
+		err = handler.installPhase3(ctx, sshHandler, host, userDataContent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Registers the host in the internal DNS of its networks; a failure does not prevent the use of the host
+	err = handler.updateDNSRecords(ctx, host, networks, false)
+	if err != nil {
+		logrus.Errorf("failed to register host '%s' in internal DNS: %v", host.Name, err)
+		err = nil
+	}
+
 	select {
 	case <-ctx.Done():
 		err = fmt.Errorf("host creation cancelled by safescale")
@@ -802,12 +865,82 @@ func (handler *HostHandler) Create(
 	return host, nil
 }
 
-func getPhaseWarningsAndErrors(ctx context.Context, sshHandler *SSHHandler, host *resources.Host) ([]string, []string) {
+// installPhase3 runs on the host the script supplied by the user, reporting its failure with the errors found in its log
+func (handler *HostHandler) installPhase3(ctx context.Context, sshHandler *SSHHandler, host *resources.Host, userData *userdata.Content) (err error) {
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%s)", host.Name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	content, err := userData.Generate("phase3")
+	if err != nil {
+		return err
+	}
+	filepath := utils.TempFolder + "/user_data.phase3.sh"
+	err = install.UploadStringToRemoteFile(string(content), srvutils.ToPBHost(host), filepath, "", "", "")
+	if err != nil {
+		return err
+	}
+
+	retcode, _, _, err := sshHandler.Run(ctx, host.Name, fmt.Sprintf("sudo bash %s; exit $?", filepath), outputs.COLLECT)
+	if err != nil {
+		retrieveForensicsData(ctx, sshHandler, host)
+		return err
+	}
+	if retcode != 0 {
+		retrieveForensicsData(ctx, sshHandler, host)
+
+		warnings, errs := getPhaseWarningsAndErrors(ctx, sshHandler, host, "phase3")
+		return fmt.Errorf("failed to run user script on host '%s' (see %s/user_data.phase3.log on the host): errorcode '%d', warnings '%s', errors '%s'", host.Name, utils.LogFolder, retcode, warnings, errs)
+	}
+	logrus.Infof("User script successfully run on host '%s'", host.Name)
+	return nil
+}
+
+// updateDNSRecords registers the host in the internal DNS of the networks having one, or unregisters it if remove is true
+func (handler *HostHandler) updateDNSRecords(ctx context.Context, host *resources.Host, networks []*resources.Network, remove bool) error {
+	addresses := map[string]string{}
+	err := host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+		for k, v := range clonable.(*propsv1.HostNetwork).IPv4Addresses {
+			addresses[k] = v
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	netHandler := &NetworkHandler{service: handler.service}
+	var errs []error
+	for _, network := range networks {
+		dns, err := netHandler.loadDNS(network)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !dns.Enabled {
+			continue
+		}
+		ip := ""
+		if !remove {
+			ip = addresses[network.ID]
+			if ip == "" {
+				continue
+			}
+		}
+		err = netHandler.updateDNSRecord(ctx, network.ID, host.Name, ip)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return scerr.ErrListError(errs)
+}
+
+func getPhaseWarningsAndErrors(ctx context.Context, sshHandler *SSHHandler, host *resources.Host, phase string) ([]string, []string) {
 	if sshHandler == nil || host == nil {
 		return []string{}, []string{}
 	}
 
-	recoverCode, recoverStdOut, _, recoverErr := sshHandler.Run(ctx, host.Name, fmt.Sprintf("cat %s/user_data.phase2.log; exit $?", utils.LogFolder), outputs.COLLECT)
+	recoverCode, recoverStdOut, _, recoverErr := sshHandler.Run(ctx, host.Name, fmt.Sprintf("cat %s/user_data.%s.log; exit $?", utils.LogFolder, phase), outputs.COLLECT)
 	var warnings []string
 	var errs []string
 
@@ -854,6 +987,9 @@ func retrieveForensicsData(ctx context.Context, sshHandler *SSHHandler, host *re
 			_, _, _, _ = sshHandler.Copy(ctx, host.Name+":"+utils.LogFolder+"/user_data.phase2.log", dumpName+"log")
 			_, _, _, _ = sshHandler.Copy(ctx, host.Name+":"+utils.LogFolder+"/packages_installed_before.phase2.list", utils.AbsPathify(fmt.Sprintf("$HOME/.safescale/forensics/%s/packages_installed_before.%s.list", host.Name, "phase2")))
 			_, _, _, _ = sshHandler.Copy(ctx, host.Name+":"+utils.LogFolder+"/packages_installed_after.phase2.list", utils.AbsPathify(fmt.Sprintf("$HOME/.safescale/forensics/%s/packages_installed_after.%s.list", host.Name, "phase2")))
+
+			dumpName = utils.AbsPathify(fmt.Sprintf("$HOME/.safescale/forensics/%s/userdata-%s.", host.Name, "phase3"))
+			_, _, _, _ = sshHandler.Copy(ctx, host.Name+":"+utils.LogFolder+"/user_data.phase3.log", dumpName+"log")
 		}
 	}
 }
@@ -916,6 +1052,9 @@ func (handler *HostHandler) List(ctx context.Context, all bool) (hosts []*resour
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.List")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	if all {
 		return handler.service.ListHosts()
@@ -941,6 +1080,9 @@ func (handler *HostHandler) ForceInspect(ctx context.Context, ref string) (host
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.ForceInspect")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	host, err = handler.Inspect(ctx, ref)
 	if err != nil {
@@ -956,6 +1098,9 @@ func (handler *HostHandler) Inspect(ctx context.Context, ref string) (host *reso
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.Inspect")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	mh, err := metadata.LoadHost(handler.service, ref)
 	if err != nil {
@@ -1043,6 +1188,9 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.Delete")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	mh, err := metadata.LoadHost(handler.service, ref)
 	if err != nil {
@@ -1102,6 +1250,10 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 	err = host.Properties.LockForRead(hostproperty.MountsV1).ThenUse(func(clonable data.Clonable) error {
 		hostMountsV1 := clonable.(*propsv1.HostMounts)
 		for _, i := range hostMountsV1.RemoteMountsByPath {
+			if i.ShareID == "" {
+				// Bucket mount, disappearing with the host
+				continue
+			}
 			// Gets share data
 			_, share, _, err := shareHandler.Inspect(ctx, i.ShareID)
 			if err != nil {
@@ -1138,6 +1290,7 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 
 	// Update networks property prosv1.NetworkHosts to remove the reference to the host
 	netHandler := NewNetworkHandler(handler.service)
+	var networks []*resources.Network
 	err = host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
 		hostNetworkV1 := clonable.(*propsv1.HostNetwork)
 		for k := range hostNetworkV1.NetworksByID {
@@ -1146,6 +1299,7 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 				logrus.Errorf(err.Error())
 				continue
 			}
+			networks = append(networks, network)
 			err = network.Properties.LockForWrite(networkproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
 				networkHostsV1 := clonable.(*propsv1.NetworkHosts)
 				delete(networkHostsV1.ByID, host.ID)
@@ -1166,6 +1320,18 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 		return err
 	}
 
+	// Unregisters the host from the internal DNS of its networks
+	err = handler.updateDNSRecords(ctx, host, networks, true)
+	if err != nil {
+		logrus.Errorf("failed to unregister host '%s' from internal DNS: %v", host.Name, err)
+	}
+
+	// Detach the public IPs managed by SafeScale, otherwise they would be released with the host
+	err = (&PublicIPHandler{service: handler.service}).detachFromHost(host.ID)
+	if err != nil {
+		return err
+	}
+
 	// Conditions are met, delete host
 	var (
 		deleteMetadataOnly bool
@@ -1232,7 +1398,7 @@ func (handler *HostHandler) Delete(ctx context.Context, ref string) (err error)
 						MaxRAMSize:  hostSizingV1.AllocatedSize.RAMSize,
 						MinDiskSize: hostSizingV1.AllocatedSize.DiskSize,
 					}
-					hostBis, err3 = handler.Create(context.Background(), host.Name, hostNetworkV1.DefaultNetworkID, "ubuntu 18.04", (len(hostNetworkV1.PublicIPv4)+len(hostNetworkV1.PublicIPv6)) != 0, &sizing, true, hostDescriptionV1.Domain, false)
+					hostBis, err3 = handler.Create(context.Background(), host.Name, []string{hostNetworkV1.DefaultNetworkID}, "ubuntu 18.04", (len(hostNetworkV1.PublicIPv4)+len(hostNetworkV1.PublicIPv6)) != 0, &sizing, true, hostDescriptionV1.Domain, "", false)
 					if err3 != nil {
 						return fmt.Errorf("failed to stop host deletion : %s", err3.Error())
 					}
@@ -1261,6 +1427,9 @@ func (handler *HostHandler) SSH(ctx context.Context, ref string) (sshConfig *sys
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.SSH")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	sshHandler := NewSSHHandler(handler.service)
 	sshConfig, err = sshHandler.GetConfig(ctx, ref)
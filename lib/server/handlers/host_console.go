@@ -0,0 +1,84 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
+)
+
+// consoleTailLines is the number of lines of the console output added to the error of a host that failed to boot
+const consoleTailLines = 30
+
+// ConsoleLog returns the output of the console of a host, available even when the host cannot be reached with SSH
+func (handler *HostHandler) ConsoleLog(ctx context.Context, ref string) (output string, err error) {
+	if handler == nil {
+		return "", scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return "", scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.ConsoleLog")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	host, err := handler.ForceInspect(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return handler.service.GetHostConsoleOutput(host.ID)
+}
+
+// consoleTail returns the last lines of the console output of the host, or an empty string if it cannot be retrieved
+func (handler *HostHandler) consoleTail(hostID string) string {
+	output, err := handler.service.GetHostConsoleOutput(hostID)
+	if err != nil {
+		logrus.Warnf("failed to get console output of host '%s': %v", hostID, err)
+		return ""
+	}
+	return lastLines(output, consoleTailLines)
+}
+
+// withConsoleTail completes msg with the last lines of the console output of the host, if available
+func (handler *HostHandler) withConsoleTail(msg, hostID string) string {
+	tail := handler.consoleTail(hostID)
+	if tail == "" {
+		return msg
+	}
+	return msg + "; last lines of its console output:\n" + tail
+}
+
+// lastLines returns at most the 'count' last lines of text, ignoring trailing empty lines
+func lastLines(text string, count int) string {
+	lines := strings.Split(strings.TrimRight(strings.Replace(text, "\r\n", "\n", -1), "\n"), "\n")
+	if len(lines) > count {
+		lines = lines[len(lines)-count:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,317 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
+)
+
+const (
+	// expiryWarningDelay is the delay before its expiration when the users of a host are warned
+	expiryWarningDelay = 24 * time.Hour
+	// idleLoadThreshold is the load average over 5 minutes under which a host is considered idle
+	idleLoadThreshold = 0.1
+	// lifecycleSSHTimeout is the timeout of the commands run on hosts to enforce their lifecycle policies
+	lifecycleSSHTimeout = time.Minute
+)
+
+// CheckLifecycle validates the lifecycle policies of a host
+func CheckLifecycle(expiresIn time.Duration, onExpiry string, idleStop time.Duration) error {
+	if expiresIn < 0 {
+		return scerr.InvalidParameterError("expiresIn", "cannot be negative")
+	}
+	if idleStop < 0 {
+		return scerr.InvalidParameterError("idleStop", "cannot be negative")
+	}
+	switch onExpiry {
+	case "", propsv1.HostExpiryStop, propsv1.HostExpiryDelete:
+	default:
+		return scerr.InvalidParameterError("onExpiry", fmt.Sprintf("must be '%s' or '%s'", propsv1.HostExpiryStop, propsv1.HostExpiryDelete))
+	}
+	return nil
+}
+
+// SetLifecycle records the lifecycle policies of a host: it expires in expiresIn (never if 0) and is then stopped or
+// deleted according to onExpiry, and it is stopped after idleStop of inactivity (never if 0)
+func (handler *HostHandler) SetLifecycle(ctx context.Context, ref string, expiresIn time.Duration, onExpiry string, idleStop time.Duration) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if err = CheckLifecycle(expiresIn, onExpiry, idleStop); err != nil {
+		return err
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %s, '%s', %s)", ref, expiresIn, onExpiry, idleStop), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.SetLifecycle")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	mh, err := metadata.LoadHost(handler.service, ref)
+	if err != nil {
+		return err
+	}
+	if mh == nil {
+		return resources.ResourceNotFoundError("host", ref)
+	}
+	host, err := mh.Get()
+	if err != nil {
+		return err
+	}
+
+	err = host.Properties.LockForWrite(hostproperty.LifecycleV1).ThenUse(func(clonable data.Clonable) error {
+		hostLifecycleV1 := clonable.(*propsv1.HostLifecycle)
+		hostLifecycleV1.Reset()
+		if expiresIn > 0 {
+			hostLifecycleV1.ExpiresAt = time.Now().Add(expiresIn)
+			hostLifecycleV1.OnExpiry = onExpiry
+			if hostLifecycleV1.OnExpiry == "" {
+				hostLifecycleV1.OnExpiry = propsv1.HostExpiryStop
+			}
+		}
+		hostLifecycleV1.IdleStop = idleStop
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return mh.Write()
+}
+
+// EnforceLifecycles applies the lifecycle policies of the hosts recorded in metadata: expired hosts are stopped or
+// deleted, after a warning sent to their users, and idle hosts are stopped; each stop or deletion is given to 'record'
+func (handler *HostHandler) EnforceLifecycles(ctx context.Context, record AuditRecorder) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "HostHandler.EnforceLifecycles")
+	defer tracing.EndSpan(span, &err)
+	handler = &HostHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	mh, err := metadata.NewHost(handler.service)
+	if err != nil {
+		return err
+	}
+	var hosts []*resources.Host
+	err = mh.Browse(func(host *resources.Host) error {
+		if host.Properties.Lookup(hostproperty.LifecycleV1) {
+			hosts = append(hosts, host)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, host := range hosts {
+		err = handler.enforceLifecycle(ctx, host, time.Now(), record)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to enforce lifecycle policies of host '%s': %v", host.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return scerr.ErrListError(errs)
+	}
+	return nil
+}
+
+// enforceLifecycle applies at now the lifecycle policies of a host
+func (handler *HostHandler) enforceLifecycle(ctx context.Context, host *resources.Host, now time.Time, record AuditRecorder) error {
+	var lifecycle *propsv1.HostLifecycle
+	err := host.Properties.LockForRead(hostproperty.LifecycleV1).ThenUse(func(clonable data.Clonable) error {
+		lifecycle = clonable.Clone().(*propsv1.HostLifecycle)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	state, err := handler.service.GetHostState(host.ID)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	if !lifecycle.ExpiresAt.IsZero() {
+		expired := !now.Before(lifecycle.ExpiresAt)
+		switch {
+		case lifecycle.WarnedAt.IsZero() && (expired || lifecycle.ExpiresAt.Sub(now) <= expiryWarningDelay):
+			// The users are always warned, and an expired host not warned yet (daemon stopped, expiration set in
+			// the past, ...) gets at least one more check interval before being stopped or deleted
+			msg := expiryWarning(lifecycle, now)
+			logrus.Warnf("Host '%s' %s", host.Name, msg)
+			if state == hoststate.STARTED {
+				handler.warnUsers(ctx, host, "This host "+msg)
+			}
+			lifecycle.WarnedAt = now
+			changed = true
+		case expired:
+			if lifecycle.OnExpiry == propsv1.HostExpiryDelete {
+				logrus.Warnf("Host '%s' expired at %s, deleting it", host.Name, lifecycle.ExpiresAt.Format(time.RFC1123))
+				return handler.auditLifecycleAction(ctx, record, host, "Delete", "expired", handler.Delete)
+			}
+			if state != hoststate.STOPPED {
+				logrus.Warnf("Host '%s' expired at %s, stopping it", host.Name, lifecycle.ExpiresAt.Format(time.RFC1123))
+				return handler.auditLifecycleAction(ctx, record, host, "Stop", "expired", handler.Stop)
+			}
+			return nil
+		}
+	}
+
+	if lifecycle.IdleStop > 0 {
+		switch {
+		case state != hoststate.STARTED:
+			// The delay of inactivity starts again when the host is started
+			if !lifecycle.LastActivity.IsZero() {
+				lifecycle.LastActivity = time.Time{}
+				changed = true
+			}
+		case lifecycle.LastActivity.IsZero():
+			lifecycle.LastActivity = now
+			changed = true
+		default:
+			load, err := handler.loadAverage(ctx, host)
+			if err != nil {
+				// A host that cannot be observed is not stopped
+				logrus.Warnf("failed to get load of host '%s': %v", host.Name, err)
+				break
+			}
+			if load >= idleLoadThreshold {
+				lifecycle.LastActivity = now
+				changed = true
+				break
+			}
+			if now.Sub(lifecycle.LastActivity) >= lifecycle.IdleStop {
+				logrus.Infof("Host '%s' idle since %s, stopping it", host.Name, lifecycle.LastActivity.Format(time.RFC1123))
+				err = handler.auditLifecycleAction(ctx, record, host, "Stop", "idle", handler.Stop)
+				if err != nil {
+					return err
+				}
+				lifecycle.LastActivity = time.Time{}
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	err = host.Properties.LockForWrite(hostproperty.LifecycleV1).ThenUse(func(clonable data.Clonable) error {
+		clonable.(*propsv1.HostLifecycle).Replace(lifecycle)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = metadata.SaveHost(handler.service, host)
+	return err
+}
+
+// auditLifecycleAction runs action ("Stop" or "Delete" of the host service) on host because of its lifecycle policies
+// ("expired" or "idle" reason), and gives to record the audit event of the action
+func (handler *HostHandler) auditLifecycleAction(ctx context.Context, record AuditRecorder, host *resources.Host, method, reason string, action func(context.Context, string) error) error {
+	start := time.Now()
+	err := action(ctx, host.ID)
+	if record == nil {
+		return err
+	}
+
+	event := &metadata.AuditEvent{
+		Time:         start,
+		Method:       "/HostService/" + method,
+		ResourceKind: "host",
+		ResourceRef:  host.Name,
+		Parameters:   map[string]interface{}{"reason": reason},
+		Outcome:      "success",
+		Duration:     time.Since(start).Seconds(),
+	}
+	if err != nil {
+		event.Outcome = "failure"
+		event.Error = err.Error()
+	}
+	record(ctx, event)
+	return err
+}
+
+// expiryWarning returns the message warning at now of the expiration of a host
+func expiryWarning(lifecycle *propsv1.HostLifecycle, now time.Time) string {
+	action := "stopped"
+	if lifecycle.OnExpiry == propsv1.HostExpiryDelete {
+		action = "deleted"
+	}
+	if !now.Before(lifecycle.ExpiresAt) {
+		return fmt.Sprintf("expired at %s and will be %s at the next check", lifecycle.ExpiresAt.Format(time.RFC1123), action)
+	}
+	return fmt.Sprintf("expires at %s and will then be %s", lifecycle.ExpiresAt.Format(time.RFC1123), action)
+}
+
+// warnUsers sends msg to the users logged on host
+func (handler *HostHandler) warnUsers(ctx context.Context, host *resources.Host, msg string) {
+	cmd := fmt.Sprintf("echo '%s' | sudo wall", strings.Replace(msg, "'", "", -1))
+	_, _, stderr, err := NewSSHHandler(handler.service).RunWithTimeout(ctx, host.Name, cmd, outputs.COLLECT, lifecycleSSHTimeout)
+	if err != nil {
+		logrus.Warnf("failed to warn users of host '%s': %v %s", host.Name, err, stderr)
+	}
+}
+
+// loadAverage returns the load average over 5 minutes of host
+func (handler *HostHandler) loadAverage(ctx context.Context, host *resources.Host) (float64, error) {
+	retcode, stdout, stderr, err := NewSSHHandler(handler.service).RunWithTimeout(ctx, host.Name, "cat /proc/loadavg", outputs.COLLECT, lifecycleSSHTimeout)
+	if err != nil {
+		return 0, err
+	}
+	if retcode != 0 {
+		return 0, fmt.Errorf("failed to read /proc/loadavg: %s", stderr)
+	}
+	return parseLoadAverage(stdout)
+}
+
+// parseLoadAverage returns the load average over 5 minutes from the content of /proc/loadavg
+func parseLoadAverage(content string) (float64, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("invalid content of /proc/loadavg: '%s'", strings.TrimSpace(content))
+	}
+	return strconv.ParseFloat(fields[1], 64)
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+)
+
+func TestCheckLifecycle(t *testing.T) {
+	assert.Nil(t, CheckLifecycle(72*time.Hour, "", 2*time.Hour))
+	assert.Nil(t, CheckLifecycle(0, propsv1.HostExpiryDelete, 0))
+	assert.NotNil(t, CheckLifecycle(-time.Hour, "", 0))
+	assert.NotNil(t, CheckLifecycle(0, "", -time.Hour))
+	assert.NotNil(t, CheckLifecycle(time.Hour, "archive", 0))
+}
+
+func TestExpiryWarning(t *testing.T) {
+	now := time.Now()
+	lifecycle := &propsv1.HostLifecycle{ExpiresAt: now.Add(time.Hour), OnExpiry: propsv1.HostExpiryDelete}
+	assert.Contains(t, expiryWarning(lifecycle, now), "will then be deleted")
+	assert.Contains(t, expiryWarning(lifecycle, now.Add(2*time.Hour)), "will be deleted at the next check")
+}
+
+func TestParseLoadAverage(t *testing.T) {
+	load, err := parseLoadAverage("0.52 0.08 0.02 1/123 4567\n")
+	assert.Nil(t, err)
+	assert.Equal(t, 0.08, load)
+
+	_, err = parseLoadAverage("")
+	assert.NotNil(t, err)
+}
+
+func TestAuditLifecycleAction(t *testing.T) {
+	handler := &HostHandler{}
+	host := &resources.Host{ID: "abcd", Name: "myhost"}
+	var events []*metadata.AuditEvent
+	record := func(ctx context.Context, event *metadata.AuditEvent) {
+		events = append(events, event)
+	}
+
+	err := handler.auditLifecycleAction(context.Background(), record, host, "Delete", "expired", func(ctx context.Context, ref string) error {
+		assert.Equal(t, "abcd", ref)
+		return nil
+	})
+	assert.Nil(t, err)
+	err = handler.auditLifecycleAction(context.Background(), record, host, "Stop", "idle", func(ctx context.Context, ref string) error {
+		return fmt.Errorf("host unreachable")
+	})
+	assert.NotNil(t, err)
+
+	assert.Len(t, events, 2)
+	assert.Equal(t, "/HostService/Delete", events[0].Method)
+	assert.Equal(t, "myhost", events[0].ResourceRef)
+	assert.Equal(t, "expired", events[0].Parameters["reason"])
+	assert.Equal(t, "success", events[0].Outcome)
+	assert.Equal(t, "/HostService/Stop", events[1].Method)
+	assert.Equal(t, "failure", events[1].Outcome)
+	assert.Equal(t, "host unreachable", events[1].Error)
+}
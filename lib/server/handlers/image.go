@@ -24,6 +24,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
 )
 
 //go:generate mockgen -destination=../mocks/mock_imageapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers ImageAPI
@@ -54,6 +55,9 @@ func (handler *ImageHandler) List(ctx context.Context, all bool) (images []resou
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "ImageHandler.List")
+	defer tracing.EndSpan(span, &err)
+	handler = &ImageHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	return handler.service.ListImages(all)
 }
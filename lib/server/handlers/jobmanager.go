@@ -18,6 +18,7 @@ package handlers
 
 import (
 	"context"
+	"time"
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
@@ -29,6 +30,8 @@ import (
 type JobManagerAPI interface {
 	List(ctx context.Context) (map[string]string, error)
 	Stop(ctx context.Context, uuid string)
+	Inspect(ctx context.Context, uuid string) (*srvutils.JobRecord, error)
+	History(ctx context.Context, since time.Time, limit int) ([]*srvutils.JobRecord, error)
 }
 
 // JobManagerHandler service
@@ -52,3 +55,13 @@ func (pmh *JobManagerHandler) List(ctx context.Context) (map[string]string, erro
 func (pmh *JobManagerHandler) Stop(ctx context.Context, uuid string) {
 	srvutils.JobCancelUUID(uuid)
 }
+
+// Inspect returns the record of the job, running or ended
+func (pmh *JobManagerHandler) Inspect(ctx context.Context, uuid string) (*srvutils.JobRecord, error) {
+	return srvutils.JobInspect(uuid)
+}
+
+// History returns the records of the jobs started after 'since', the most recent first
+func (pmh *JobManagerHandler) History(ctx context.Context, since time.Time, limit int) ([]*srvutils.JobRecord, error) {
+	return srvutils.JobHistory(since, limit)
+}
@@ -18,8 +18,14 @@ package handlers
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"net"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
 
@@ -39,13 +45,17 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/install"
 	"github.com/CS-SI/SafeScale/lib/server/metadata"
 	safescaleutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/system"
 	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/cidr"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/retry"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
 )
 
 //go:generate mockgen -destination=../mocks/mock_networkapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers NetworkAPI
@@ -54,11 +64,21 @@ import (
 
 // NetworkAPI defines API to manage networks
 type NetworkAPI interface {
-	Create(context.Context, string, string, ipversion.Enum, resources.SizingRequirements, string, string, bool, string) (*resources.Network, error)
+	Create(context.Context, string, string, int, ipversion.Enum, string, resources.SizingRequirements, string, string, bool, string) (*resources.Network, error)
 	List(context.Context, bool) ([]*resources.Network, error)
 	Inspect(context.Context, string) (*resources.Network, error)
 	Delete(context.Context, string) error
 	Destroy(context.Context, string) error
+	Peer(context.Context, string, string) error
+	Unpeer(context.Context, string, string) error
+	InspectVPN(context.Context, string) (*propsv1.NetworkVPN, error)
+	AddVPNPeer(context.Context, string, propsv1.NetworkVPNPeer) (*propsv1.NetworkVPN, error)
+	RemoveVPNPeer(context.Context, string, string) (*propsv1.NetworkVPN, error)
+	InspectDNS(context.Context, string) (*propsv1.NetworkDNS, error)
+	AddDNSAlias(context.Context, string, string, string) (*propsv1.NetworkDNS, error)
+	RemoveDNSAlias(context.Context, string, string) (*propsv1.NetworkDNS, error)
+	CheckGateways(context.Context, string, bool) ([]*resources.GatewayHealth, error)
+	Repair(context.Context, string, string) (*resources.Network, error)
 }
 
 // NetworkHandler an implementation of NetworkAPI
@@ -67,6 +87,36 @@ type NetworkHandler struct {
 	ipVersion ipversion.Enum
 }
 
+const (
+	// defaultAddressPool is the pool of addresses used to allocate the CIDR of networks when the tenant does not define one
+	defaultAddressPool = "192.168.0.0/16"
+	// defaultSubnetSize is the prefix length of the CIDR allocated to a network when not specified
+	defaultSubnetSize = 24
+	// peeringBasePort is the first UDP port used by the tunnels of network peerings on gateways
+	peeringBasePort = 51820
+	// peeringModeGateway tells the peering is routed by the gateways of the networks
+	peeringModeGateway = "gateway"
+	// vpnInterface is the name of the WireGuard interface of the site-to-site VPN on gateways
+	vpnInterface = "sfvpn"
+	// vpnPort is the UDP port listened by the site-to-site VPN on gateways
+	vpnPort = 51800
+)
+
+var (
+	// mutexIPAM prevents 2 network creations from allocating the same CIDR
+	mutexIPAM sync.Mutex
+	// mutexPeering prevents 2 peerings or VPN changes from configuring the same gateway concurrently
+	mutexPeering sync.Mutex
+	// dnsLocks contains, by network ID, the locks preventing 2 changes of the internal DNS of a network from
+	// configuring its gateways concurrently
+	dnsLocks      = map[string]*sync.Mutex{}
+	mutexDNSLocks sync.Mutex
+)
+
+// dnsLabelRegexp matches a valid DNS label (RFC 1123), underscores being accepted as they are common in the names
+// of hosts and gateways (ex: gw-my_network)
+var dnsLabelRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]([a-zA-Z0-9_-]{0,61}[a-zA-Z0-9_])?$`)
+
 // NewNetworkHandler Creates new Network service
 func NewNetworkHandler(svc iaas.Service) NetworkAPI {
 	return &NetworkHandler{
@@ -77,7 +127,7 @@ func NewNetworkHandler(svc iaas.Service) NetworkAPI {
 // Create creates a network
 func (handler *NetworkHandler) Create(
 	ctx context.Context,
-	name string, cidr string, ipVersion ipversion.Enum,
+	name string, cidr string, subnetSize int, ipVersion ipversion.Enum, ipv6CIDR string,
 	sizing resources.SizingRequirements, theos string, gwname string,
 	failover bool, domain string,
 ) (network *resources.Network, err error) {
@@ -91,14 +141,27 @@ func (handler *NetworkHandler) Create(
 	if failover && gwname != "" {
 		return nil, scerr.InvalidParameterError("gwname", "cannot be set if failover is set")
 	}
+	if ipv6CIDR != "" && ipVersion != ipversion.DualStack {
+		return nil, scerr.InvalidParameterError("ipv6CIDR", "can be set only for dual-stack network")
+	}
+	if d := strings.Trim(domain, "."); d != "" && !isDNSName(d) {
+		return nil, scerr.InvalidParameterError("domain", "must be a valid DNS domain name")
+	}
 
 	tracer := concurrency.NewTracer(
 		nil,
-		fmt.Sprintf("('%s', '%s', %s, <sizing>, '%s', '%s', %v)", name, cidr, ipVersion.String(), theos, gwname, failover),
+		fmt.Sprintf("('%s', '%s', %d, %s, '%s', <sizing>, '%s', '%s', %v)", name, cidr, subnetSize, ipVersion.String(), ipv6CIDR, theos, gwname, failover),
 		true,
 	).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.Create")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
+
+	if ipVersion.HasIPv6() && !handler.service.GetCapabilities().IPv6 {
+		return nil, scerr.NotImplementedError("the provider of the tenant does not support IPv6 networks")
+	}
 
 	// Verify that the network doesn't exist first
 	_, err = handler.service.GetNetworkByName(name)
@@ -114,13 +177,56 @@ func (handler *NetworkHandler) Create(
 		return nil, fmt.Errorf("network '%s' already exists", name)
 	}
 
-	// Verify the CIDR is not routable
-	routable, err := utils.IsCIDRRoutable(cidr)
+	// Allocates a CIDR from the address pool of the tenant if none is provided, otherwise verifies the one provided
+	// does not overlap existing networks; the lock is kept until the network is created
+	mutexIPAM.Lock()
+	ipamLocked := true
+	unlockIPAM := func() {
+		if ipamLocked {
+			mutexIPAM.Unlock()
+			ipamLocked = false
+		}
+	}
+	defer unlockIPAM()
+	switch {
+	case cidr != "":
+		err = handler.checkCIDROverlap(cidr)
+	case ipVersion == ipversion.IPv6:
+		cidr, err = handler.allocateIPv6CIDR()
+	default:
+		cidr, err = handler.allocateCIDR(subnetSize)
+	}
+	if err == nil && ipv6CIDR != "" {
+		err = handler.checkCIDROverlap(ipv6CIDR)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to determine if CIDR is not routable: %v", err)
+		return nil, err
+	}
+
+	// Verify the CIDRs are of the requested IP version and are not routable
+	cidrs := []string{cidr}
+	if ipv6CIDR != "" {
+		cidrs = append(cidrs, ipv6CIDR)
 	}
-	if routable {
-		return nil, fmt.Errorf("cannot create such a network, CIDR must be not routable; please provide an appropriate CIDR (RFC1918)")
+	for i, c := range cidrs {
+		v := ipversion.IPv4
+		if ipVersion == ipversion.IPv6 || i > 0 {
+			v = ipversion.IPv6
+		}
+		ip, _, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, scerr.InvalidParameterError("cidr", fmt.Sprintf("'%s' is not a valid CIDR: %v", c, err))
+		}
+		if !v.Is(ip.String()) {
+			return nil, scerr.InvalidParameterError("cidr", fmt.Sprintf("'%s' is not an IPv%d CIDR", c, v))
+		}
+		routable, err := utils.IsCIDRRoutable(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine if CIDR is not routable: %v", err)
+		}
+		if routable {
+			return nil, fmt.Errorf("cannot create such a network, CIDR must be not routable; please provide an appropriate CIDR (RFC1918 or RFC4193)")
+		}
 	}
 
 	// Create the network
@@ -129,8 +235,10 @@ func (handler *NetworkHandler) Create(
 		Name:      name,
 		IPVersion: ipVersion,
 		CIDR:      cidr,
+		IPv6CIDR:  ipv6CIDR,
 		Domain:    domain,
 	})
+	unlockIPAM()
 	if err != nil {
 		switch err.(type) {
 		case scerr.ErrNotFound, scerr.ErrInvalidRequest, scerr.ErrTimeout:
@@ -169,6 +277,10 @@ func (handler *NetworkHandler) Create(
 		logrus.Warningf("Provider doesn't support private Virtual IP, cannot set up high availability of network default route.")
 		failover = false
 	}
+	if failover && ipVersion == ipversion.IPv6 {
+		logrus.Warningf("Gateway failover uses an IPv4 Virtual IP, cannot set up high availability of IPv6-only network default route.")
+		failover = false
+	}
 
 	// Creates VIP for gateways if asked for
 	if failover {
@@ -420,6 +532,7 @@ func (handler *NetworkHandler) Create(
 	if secondaryGateway != nil {
 		network.SecondaryGatewayID = secondaryGateway.ID
 	}
+	network.GatewayImageID = img.ID
 	err = mn.Write()
 	if err != nil {
 		return nil, err
@@ -512,6 +625,23 @@ func (handler *NetworkHandler) Create(
 		}
 	}
 
+	// Starts the internal DNS of the network on gateway(s), knowing only the gateway(s) for now
+	dns := propsv1.NewNetworkDNS()
+	dns.Enabled = true
+	dns.Domain = strings.Trim(domain, ".")
+	dns.Records[primaryGateway.Name] = primaryGateway.GetPrivateIP()
+	if failover && secondaryGateway != nil {
+		dns.Records[secondaryGateway.Name] = secondaryGateway.GetPrivateIP()
+	}
+	err = handler.configureDNS(ctx, network, dns)
+	if err != nil {
+		return nil, err
+	}
+	err = handler.saveDNS(network, dns)
+	if err != nil {
+		return nil, err
+	}
+
 	select {
 	case <-ctx.Done():
 		logrus.Warnf("Network creation cancelled by user")
@@ -704,7 +834,7 @@ func (handler *NetworkHandler) installPhase2OnGateway(task concurrency.Task, par
 	if returnCode != 0 {
 		retrieveForensicsData(task.GetContext(), sshHandler, gw)
 
-		warnings, errs := getPhaseWarningsAndErrors(task.GetContext(), sshHandler, gw)
+		warnings, errs := getPhaseWarningsAndErrors(task.GetContext(), sshHandler, gw, "phase2")
 
 		return nil, fmt.Errorf("failed to finalize gateway '%s' installation: errorcode '%d', warnings '%s', errors '%s'", gw.Name, returnCode, warnings, errs)
 	}
@@ -791,11 +921,128 @@ func (handler *NetworkHandler) unbindHostFromVIP(vip *resources.VirtualIP, host
 	return err
 }
 
+// addressPool returns the pool of addresses of the tenant, defined by the parameter 'AddressPool' of the section
+// 'network' of the tenant, or defaultAddressPool
+func (handler *NetworkHandler) addressPool() (*net.IPNet, error) {
+	pool := defaultAddressPool
+	if params := handler.service.GetTenantParameters(); params != nil {
+		if networkParams, ok := params["network"].(map[string]interface{}); ok {
+			if value, ok := networkParams["AddressPool"].(string); ok && value != "" {
+				pool = value
+			}
+		}
+	}
+	_, ipNet, err := net.ParseCIDR(pool)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address pool '%s' in tenant configuration: %v", pool, err)
+	}
+	return ipNet, nil
+}
+
+// existingCIDRs returns the CIDRs of the networks of the tenant, indexed by network name
+func (handler *NetworkHandler) existingCIDRs() (map[string]*net.IPNet, error) {
+	networks, err := handler.service.ListNetworks()
+	if err != nil {
+		return nil, err
+	}
+	cidrs := map[string]*net.IPNet{}
+	for _, n := range networks {
+		if n.CIDR == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(n.CIDR)
+		if err != nil {
+			logrus.Warnf("ignoring network '%s' with invalid CIDR '%s'", n.Name, n.CIDR)
+			continue
+		}
+		cidrs[n.Name] = ipNet
+	}
+	return cidrs, nil
+}
+
+// allocateCIDR returns the first CIDR with a prefix length of 'size' in the address pool of the tenant
+// not overlapping an existing network
+func (handler *NetworkHandler) allocateCIDR(size int) (string, error) {
+	if size <= 0 {
+		size = defaultSubnetSize
+	}
+	pool, err := handler.addressPool()
+	if err != nil {
+		return "", err
+	}
+	existing, err := handler.existingCIDRs()
+	if err != nil {
+		return "", err
+	}
+	used := make([]*net.IPNet, 0, len(existing))
+	for _, c := range existing {
+		used = append(used, c)
+	}
+	subnet, err := cidr.FirstFreeSubnet(pool, size, used)
+	if err != nil {
+		return "", scerr.OverloadError(fmt.Sprintf("failed to allocate a CIDR for the network: %v", err))
+	}
+	logrus.Infof("Allocated CIDR '%s' from address pool '%s'", subnet.String(), pool.String())
+	return subnet.String(), nil
+}
+
+// allocateIPv6CIDR returns a random IPv6 unique local prefix not overlapping an existing network; the address pool
+// of the tenant is IPv4 only
+func (handler *NetworkHandler) allocateIPv6CIDR() (string, error) {
+	existing, err := handler.existingCIDRs()
+	if err != nil {
+		return "", err
+	}
+	for {
+		ula, err := cidr.RandomULA()
+		if err != nil {
+			return "", scerr.Wrap(err, "failed to allocate an IPv6 CIDR for the network")
+		}
+		overlaps := false
+		for _, c := range existing {
+			if cidr.Overlaps(ula, c) {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			logrus.Infof("Allocated IPv6 CIDR '%s'", ula.String())
+			return ula.String(), nil
+		}
+	}
+}
+
+// checkCIDROverlap verifies 'requested' does not overlap the CIDR of an existing network
+func (handler *NetworkHandler) checkCIDROverlap(requested string) error {
+	_, ipNet, err := net.ParseCIDR(requested)
+	if err != nil {
+		return scerr.InvalidParameterError("cidr", fmt.Sprintf("'%s' is not a valid CIDR: %v", requested, err))
+	}
+	existing, err := handler.existingCIDRs()
+	if err != nil {
+		return err
+	}
+	var conflicts []string
+	for name, c := range existing {
+		if cidr.Overlaps(ipNet, c) {
+			conflicts = append(conflicts, fmt.Sprintf("'%s' (%s)", name, c.String()))
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return scerr.DuplicateError(fmt.Sprintf("CIDR '%s' overlaps existing network(s) %s", requested, strings.Join(conflicts, ", ")))
+	}
+	return nil
+}
+
 // List returns the network list
 func (handler *NetworkHandler) List(ctx context.Context, all bool) (netList []*resources.Network, err error) {
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.List")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
 
 	if all {
 		return handler.service.ListNetworks()
@@ -822,6 +1069,9 @@ func (handler *NetworkHandler) Inspect(ctx context.Context, ref string) (network
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.Inspect")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
 
 	mn, err := metadata.LoadNetwork(handler.service, ref)
 	if err != nil {
@@ -836,6 +1086,9 @@ func (handler *NetworkHandler) Delete(ctx context.Context, ref string) (err erro
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.Delete")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
 
 	mn, err := metadata.LoadNetwork(handler.service, ref)
 	if err != nil {
@@ -893,6 +1146,27 @@ func (handler *NetworkHandler) Delete(ctx context.Context, ref string) (err erro
 		return err
 	}
 
+	// Check if network is still peered with other networks
+	err = network.Properties.LockForRead(networkproperty.PeeringsV1).ThenUse(func(clonable data.Clonable) error {
+		peeringsV1 := clonable.(*propsv1.NetworkPeerings)
+		if len(peeringsV1.ByName) > 0 {
+			list := make([]string, 0, len(peeringsV1.ByName))
+			for k := range peeringsV1.ByName {
+				list = append(list, k)
+			}
+			sort.Strings(list)
+			errorMsg = fmt.Sprintf("cannot delete network '%s': still peered with %s", network.Name, strings.Join(list, ", "))
+			return resources.ResourceNotAvailableError("network", network.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotAvailable); ok {
+			return fmt.Errorf(errorMsg)
+		}
+		return err
+	}
+
 	// Delete gateway(s)
 	if network.GatewayID != "" {
 		mh, err := metadata.LoadHost(handler.service, network.GatewayID)
@@ -1042,6 +1316,9 @@ func (handler *NetworkHandler) Destroy(ctx context.Context, ref string) (err err
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.Destroy")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
 
 	mn, err := metadata.LoadNetwork(handler.service, ref)
 	if err != nil {
@@ -1207,3 +1484,1392 @@ func (handler *NetworkHandler) Destroy(ctx context.Context, ref string) (err err
 
 	return nil
 }
+
+// peeringScriptData contains the values used to fill the scripts configuring a peering on a gateway
+type peeringScriptData struct {
+	BashLibrary   string
+	CIDR          string
+	Interface     string
+	Port          int
+	PrivateKey    string
+	PeerPublicKey string
+	PeerEndpoint  string
+	PeerCIDR      string
+}
+
+// Peer connects the networks referenced by ref and peerRef, allowing their hosts to talk without public IP
+// None of the stack drivers exposes native network peering yet, so the peering is always routed
+// by the gateways of the networks through WireGuard tunnels (mode "gateway")
+func (handler *NetworkHandler) Peer(ctx context.Context, ref, peerRef string) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if peerRef == "" {
+		return scerr.InvalidParameterError("peerRef", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, peerRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.Peer")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
+
+	mutexPeering.Lock()
+	defer mutexPeering.Unlock()
+
+	network, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return err
+	}
+	peer, err := handler.Inspect(ctx, peerRef)
+	if err != nil {
+		return err
+	}
+	if network.ID == peer.ID {
+		return scerr.InvalidRequestError(fmt.Sprintf("cannot peer network '%s' with itself", network.Name))
+	}
+	if network.GatewayID == "" || peer.GatewayID == "" {
+		return scerr.InvalidRequestError("cannot peer networks without gateway")
+	}
+	_, networkCIDR, err := net.ParseCIDR(network.CIDR)
+	if err != nil {
+		return err
+	}
+	_, peerCIDR, err := net.ParseCIDR(peer.CIDR)
+	if err != nil {
+		return err
+	}
+	if cidr.Overlaps(networkCIDR, peerCIDR) {
+		return scerr.InvalidRequestError(fmt.Sprintf("cannot peer networks '%s' (%s) and '%s' (%s): CIDRs overlap", network.Name, network.CIDR, peer.Name, peer.CIDR))
+	}
+
+	var alreadyPeered bool
+	err = network.Properties.LockForRead(networkproperty.PeeringsV1).ThenUse(func(clonable data.Clonable) error {
+		_, alreadyPeered = clonable.(*propsv1.NetworkPeerings).ByID[peer.ID]
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if alreadyPeered {
+		return resources.ResourceDuplicateError("peering", network.Name+"<->"+peer.Name)
+	}
+
+	local, err := handler.newPeering(network, peer)
+	if err != nil {
+		return err
+	}
+	remote, err := handler.newPeering(peer, network)
+	if err != nil {
+		return err
+	}
+	local.PeerPublicKey, remote.PeerPublicKey = remote.PublicKey, local.PublicKey
+	localEndpoint, remoteEndpoint := local.PeerEndpoint, remote.PeerEndpoint
+	local.PeerEndpoint = fmt.Sprintf("%s:%d", remoteEndpoint, remote.Port)
+	remote.PeerEndpoint = fmt.Sprintf("%s:%d", localEndpoint, local.Port)
+
+	err = handler.configurePeering(ctx, network, local)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			derr := handler.unconfigurePeering(ctx, network, local)
+			if derr != nil {
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	err = handler.configurePeering(ctx, peer, remote)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			derr := handler.unconfigurePeering(ctx, peer, remote)
+			if derr != nil {
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	err = handler.savePeering(network, local)
+	if err != nil {
+		return err
+	}
+	err = handler.savePeering(peer, remote)
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("Networks '%s' and '%s' peered through their gateways", network.Name, peer.Name)
+	return nil
+}
+
+// Unpeer removes the peering between the networks referenced by ref and peerRef
+// If the peer network does not exist anymore, only the side of the peering on network 'ref' is removed
+func (handler *NetworkHandler) Unpeer(ctx context.Context, ref, peerRef string) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if peerRef == "" {
+		return scerr.InvalidParameterError("peerRef", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, peerRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.Unpeer")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
+
+	mutexPeering.Lock()
+	defer mutexPeering.Unlock()
+
+	network, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return err
+	}
+	peer, err := handler.Inspect(ctx, peerRef)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); !ok {
+			return err
+		}
+		peer = nil
+	}
+
+	var local *propsv1.NetworkPeering
+	err = network.Properties.LockForRead(networkproperty.PeeringsV1).ThenUse(func(clonable data.Clonable) error {
+		peeringsV1 := clonable.(*propsv1.NetworkPeerings)
+		peerID := peerRef
+		if peer != nil {
+			peerID = peer.ID
+		} else if id, ok := peeringsV1.ByName[peerRef]; ok {
+			peerID = id
+		}
+		if p, ok := peeringsV1.ByID[peerID]; ok {
+			local = p
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if local == nil {
+		return resources.ResourceNotFoundError("peering", network.Name+"<->"+peerRef)
+	}
+
+	err = handler.unconfigurePeering(ctx, network, local)
+	if err != nil {
+		return err
+	}
+	err = handler.removePeering(network, local.NetworkID)
+	if err != nil {
+		return err
+	}
+
+	if peer != nil {
+		var remote *propsv1.NetworkPeering
+		err = peer.Properties.LockForRead(networkproperty.PeeringsV1).ThenUse(func(clonable data.Clonable) error {
+			remote = clonable.(*propsv1.NetworkPeerings).ByID[network.ID]
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if remote != nil {
+			err = handler.unconfigurePeering(ctx, peer, remote)
+			if err != nil {
+				return err
+			}
+			err = handler.removePeering(peer, network.ID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	logrus.Infof("Peering between networks '%s' and '%s' removed", network.Name, local.NetworkName)
+	return nil
+}
+
+// newPeering prepares the side of a peering on network towards peer; PeerEndpoint is temporarily
+// set to the public address of the gateway(s) of network, and has to be swapped by the caller
+func (handler *NetworkHandler) newPeering(network, peer *resources.Network) (*propsv1.NetworkPeering, error) {
+	slot := 0
+	err := network.Properties.LockForRead(networkproperty.PeeringsV1).ThenUse(func(clonable data.Clonable) error {
+		used := map[int]bool{}
+		for _, p := range clonable.(*propsv1.NetworkPeerings).ByID {
+			used[p.Port-peeringBasePort] = true
+		}
+		for used[slot] {
+			slot++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := handler.publicEndpoint(network)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, publicKey, err := crypt.GenerateWireGuardKeyPair()
+	if err != nil {
+		return nil, err
+	}
+
+	return &propsv1.NetworkPeering{
+		NetworkID:    peer.ID,
+		NetworkName:  peer.Name,
+		CIDR:         peer.CIDR,
+		Mode:         peeringModeGateway,
+		Interface:    fmt.Sprintf("sfpeer%d", slot),
+		Port:         peeringBasePort + slot,
+		PrivateKey:   privateKey,
+		PublicKey:    publicKey,
+		PeerEndpoint: endpoint,
+		Created:      time.Now(),
+	}, nil
+}
+
+// publicEndpoint returns the public IP address the gateway(s) of the network are reachable on;
+// the public IP of the VIP is used if the network has one, so the endpoint follows a failover
+func (handler *NetworkHandler) publicEndpoint(network *resources.Network) (string, error) {
+	if network.VIP != nil && network.VIP.PublicIP != "" {
+		return network.VIP.PublicIP, nil
+	}
+	mgw, err := metadata.LoadHost(handler.service, network.GatewayID)
+	if err != nil {
+		return "", err
+	}
+	gw, err := mgw.Get()
+	if err != nil {
+		return "", err
+	}
+	ip := gw.GetPublicIP()
+	if ip == "" {
+		return "", scerr.InvalidRequestError(fmt.Sprintf("gateway of network '%s' has no public IP", network.Name))
+	}
+	return ip, nil
+}
+
+// gatewayIDs returns the IDs of the gateway(s) of the network
+func gatewayIDs(network *resources.Network) []string {
+	ids := []string{network.GatewayID}
+	if network.SecondaryGatewayID != "" {
+		ids = append(ids, network.SecondaryGatewayID)
+	}
+	return ids
+}
+
+// configurePeering sets up the tunnel of the peering on every gateway of the network
+func (handler *NetworkHandler) configurePeering(ctx context.Context, network *resources.Network, peering *propsv1.NetworkPeering) error {
+	return handler.execPeeringScript(ctx, "network_peer.sh", network, peering)
+}
+
+// unconfigurePeering tears down the tunnel of the peering on every gateway of the network
+func (handler *NetworkHandler) unconfigurePeering(ctx context.Context, network *resources.Network, peering *propsv1.NetworkPeering) error {
+	return handler.execPeeringScript(ctx, "network_unpeer.sh", network, peering)
+}
+
+func (handler *NetworkHandler) execPeeringScript(ctx context.Context, script string, network *resources.Network, peering *propsv1.NetworkPeering) error {
+	bashLibrary, err := system.GetBashLibrary()
+	if err != nil {
+		return err
+	}
+	scriptData := peeringScriptData{
+		BashLibrary:   bashLibrary,
+		CIDR:          network.CIDR,
+		Interface:     peering.Interface,
+		Port:          peering.Port,
+		PrivateKey:    peering.PrivateKey,
+		PeerPublicKey: peering.PeerPublicKey,
+		PeerEndpoint:  peering.PeerEndpoint,
+		PeerCIDR:      peering.CIDR,
+	}
+	for _, id := range gatewayIDs(network) {
+		err = exec(ctx, script, scriptData, id, handler.service)
+		if err != nil {
+			return scerr.Wrap(err, fmt.Sprintf("failed to run '%s' on gateway of network '%s'", script, network.Name))
+		}
+	}
+	return nil
+}
+
+// savePeering records the peering in the metadata of the network
+func (handler *NetworkHandler) savePeering(network *resources.Network, peering *propsv1.NetworkPeering) error {
+	err := network.Properties.LockForWrite(networkproperty.PeeringsV1).ThenUse(func(clonable data.Clonable) error {
+		peeringsV1 := clonable.(*propsv1.NetworkPeerings)
+		peeringsV1.ByID[peering.NetworkID] = peering
+		peeringsV1.ByName[peering.NetworkName] = peering.NetworkID
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = metadata.SaveNetwork(handler.service, network)
+	return err
+}
+
+// removePeering removes the peering with the network identified by peerID from the metadata of the network
+func (handler *NetworkHandler) removePeering(network *resources.Network, peerID string) error {
+	err := network.Properties.LockForWrite(networkproperty.PeeringsV1).ThenUse(func(clonable data.Clonable) error {
+		peeringsV1 := clonable.(*propsv1.NetworkPeerings)
+		if p, ok := peeringsV1.ByID[peerID]; ok {
+			delete(peeringsV1.ByName, p.NetworkName)
+			delete(peeringsV1.ByID, peerID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = metadata.SaveNetwork(handler.service, network)
+	return err
+}
+
+// vpnScriptData contains the values used to fill the script configuring the VPN on a gateway
+type vpnScriptData struct {
+	BashLibrary string
+	CIDR        string
+	Interface   string
+	Port        int
+	PrivateKey  string
+	PrivateVIP  string
+	Peers       []*propsv1.NetworkVPNPeer
+}
+
+// InspectVPN returns the site-to-site VPN configuration of the network referenced by ref
+func (handler *NetworkHandler) InspectVPN(ctx context.Context, ref string) (vpn *propsv1.NetworkVPN, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.InspectVPN")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
+
+	network, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	err = network.Properties.LockForRead(networkproperty.VPNV1).ThenUse(func(clonable data.Clonable) error {
+		vpn = clonable.Clone().(*propsv1.NetworkVPN)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vpn, nil
+}
+
+// AddVPNPeer connects the remote site described by peer to the network referenced by ref, through
+// a WireGuard endpoint on the gateway(s) of the network; keys are created on first use
+func (handler *NetworkHandler) AddVPNPeer(ctx context.Context, ref string, peer propsv1.NetworkVPNPeer) (vpn *propsv1.NetworkVPN, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if peer.Name == "" {
+		return nil, scerr.InvalidParameterError("peer.Name", "cannot be empty string")
+	}
+	if key, err := base64.StdEncoding.DecodeString(peer.PublicKey); err != nil || len(key) != 32 {
+		return nil, scerr.InvalidParameterError("peer.PublicKey", "must be a base64 encoded WireGuard public key")
+	}
+	if len(peer.AllowedIPs) == 0 {
+		return nil, scerr.InvalidParameterError("peer.AllowedIPs", "cannot be empty")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, peer.Name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.AddVPNPeer")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
+
+	mutexPeering.Lock()
+	defer mutexPeering.Unlock()
+
+	network, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if network.GatewayID == "" {
+		return nil, scerr.InvalidRequestError(fmt.Sprintf("cannot add VPN to network '%s': network has no gateway", network.Name))
+	}
+	_, networkCIDR, err := net.ParseCIDR(network.CIDR)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range peer.AllowedIPs {
+		_, ipNet, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, scerr.InvalidParameterError("peer.AllowedIPs", fmt.Sprintf("'%s' is not a valid CIDR", c))
+		}
+		if cidr.Overlaps(networkCIDR, ipNet) {
+			return nil, scerr.InvalidRequestError(fmt.Sprintf("CIDR '%s' of site '%s' overlaps CIDR '%s' of network '%s'", c, peer.Name, network.CIDR, network.Name))
+		}
+	}
+
+	err = network.Properties.LockForRead(networkproperty.VPNV1).ThenUse(func(clonable data.Clonable) error {
+		vpn = clonable.Clone().(*propsv1.NetworkVPN)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := vpn.Peers[peer.Name]; ok {
+		return nil, resources.ResourceDuplicateError("VPN peer", peer.Name)
+	}
+	if vpn.PrivateKey == "" {
+		vpn.PrivateKey, vpn.PublicKey, err = crypt.GenerateWireGuardKeyPair()
+		if err != nil {
+			return nil, err
+		}
+		vpn.Interface = vpnInterface
+		vpn.Port = vpnPort
+	}
+	endpoint, err := handler.publicEndpoint(network)
+	if err != nil {
+		return nil, err
+	}
+	vpn.Endpoint = fmt.Sprintf("%s:%d", endpoint, vpn.Port)
+	peer.Created = time.Now()
+	vpn.Peers[peer.Name] = &peer
+
+	err = handler.configureVPN(ctx, network, vpn)
+	if err != nil {
+		return nil, err
+	}
+	err = handler.saveVPN(network, vpn)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Site '%s' connected to network '%s' by VPN", peer.Name, network.Name)
+	return vpn, nil
+}
+
+// RemoveVPNPeer disconnects the remote site named name from the VPN of the network referenced by ref;
+// the VPN is removed from the gateway(s) with the last remote site
+func (handler *NetworkHandler) RemoveVPNPeer(ctx context.Context, ref, name string) (vpn *propsv1.NetworkVPN, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.RemoveVPNPeer")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
+
+	mutexPeering.Lock()
+	defer mutexPeering.Unlock()
+
+	network, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	err = network.Properties.LockForRead(networkproperty.VPNV1).ThenUse(func(clonable data.Clonable) error {
+		vpn = clonable.Clone().(*propsv1.NetworkVPN)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := vpn.Peers[name]; !ok {
+		return nil, resources.ResourceNotFoundError("VPN peer", name)
+	}
+	delete(vpn.Peers, name)
+
+	err = handler.configureVPN(ctx, network, vpn)
+	if err != nil {
+		return nil, err
+	}
+	if len(vpn.Peers) == 0 {
+		vpn = propsv1.NewNetworkVPN()
+	}
+	err = handler.saveVPN(network, vpn)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Site '%s' disconnected from network '%s'", name, network.Name)
+	return vpn, nil
+}
+
+// configureVPN applies the VPN configuration on every gateway of the network; with failover, only
+// the gateway holding the VIP runs the VPN, keepalived moving it with the VIP
+func (handler *NetworkHandler) configureVPN(ctx context.Context, network *resources.Network, vpn *propsv1.NetworkVPN) error {
+	bashLibrary, err := system.GetBashLibrary()
+	if err != nil {
+		return err
+	}
+	scriptData := vpnScriptData{
+		BashLibrary: bashLibrary,
+		CIDR:        network.CIDR,
+		Interface:   vpn.Interface,
+		Port:        vpn.Port,
+		PrivateKey:  vpn.PrivateKey,
+	}
+	if network.VIP != nil && network.SecondaryGatewayID != "" {
+		scriptData.PrivateVIP = network.VIP.PrivateIP
+	}
+	names := make([]string, 0, len(vpn.Peers))
+	for k := range vpn.Peers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		scriptData.Peers = append(scriptData.Peers, vpn.Peers[k])
+	}
+	for _, id := range gatewayIDs(network) {
+		err = exec(ctx, "network_vpn.sh", scriptData, id, handler.service)
+		if err != nil {
+			return scerr.Wrap(err, fmt.Sprintf("failed to configure VPN on gateway of network '%s'", network.Name))
+		}
+	}
+	return nil
+}
+
+// saveVPN records the VPN configuration in the metadata of the network
+func (handler *NetworkHandler) saveVPN(network *resources.Network, vpn *propsv1.NetworkVPN) error {
+	err := network.Properties.LockForWrite(networkproperty.VPNV1).ThenUse(func(clonable data.Clonable) error {
+		clonable.(*propsv1.NetworkVPN).Replace(vpn)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = metadata.SaveNetwork(handler.service, network)
+	return err
+}
+
+// dnsScriptEntry is a record or an alias of the internal DNS, as used by the script configuring the DNS on a gateway
+type dnsScriptEntry struct {
+	Name    string
+	Address string
+	Target  string
+}
+
+// dnsScriptData contains the values used to fill the script configuring the DNS on a gateway
+type dnsScriptData struct {
+	BashLibrary string
+	GatewayIP   string
+	Domain      string
+	Records     []dnsScriptEntry
+	Aliases     []dnsScriptEntry
+}
+
+// InspectDNS returns the records served by the internal DNS of the network referenced by ref
+func (handler *NetworkHandler) InspectDNS(ctx context.Context, ref string) (dns *propsv1.NetworkDNS, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.InspectDNS")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
+
+	network, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return handler.loadDNS(network)
+}
+
+// AddDNSAlias adds to the internal DNS of the network referenced by ref a CNAME record named alias
+// pointing to target; an existing alias is replaced
+func (handler *NetworkHandler) AddDNSAlias(ctx context.Context, ref, alias, target string) (dns *propsv1.NetworkDNS, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if alias == "" {
+		return nil, scerr.InvalidParameterError("alias", "cannot be empty string")
+	}
+	if target == "" {
+		return nil, scerr.InvalidParameterError("target", "cannot be empty string")
+	}
+	if !dnsLabelRegexp.MatchString(alias) {
+		return nil, scerr.InvalidParameterError("alias", "must be a valid DNS label (letters, digits, hyphens and underscores)")
+	}
+	if !isDNSName(strings.TrimSuffix(target, ".")) {
+		return nil, scerr.InvalidParameterError("target", "must be a valid DNS name")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', '%s')", ref, alias, target), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.AddDNSAlias")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
+
+	network, err := handler.inspectLockingDNS(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockDNS(network.ID)
+	dns, err = handler.loadDNS(network)
+	if err != nil {
+		return nil, err
+	}
+	if !dns.Enabled {
+		return nil, scerr.InvalidRequestError(fmt.Sprintf("network '%s' has no internal DNS", network.Name))
+	}
+	if _, ok := dns.Records[alias]; ok {
+		return nil, scerr.InvalidRequestError(fmt.Sprintf("'%s' is the name of a host of network '%s'", alias, network.Name))
+	}
+	dns.Aliases[alias] = strings.TrimSuffix(target, ".")
+
+	err = handler.configureDNS(ctx, network, dns)
+	if err != nil {
+		return nil, err
+	}
+	err = handler.saveDNS(network, dns)
+	if err != nil {
+		return nil, err
+	}
+	return dns, nil
+}
+
+// RemoveDNSAlias removes the CNAME record named alias from the internal DNS of the network referenced by ref
+func (handler *NetworkHandler) RemoveDNSAlias(ctx context.Context, ref, alias string) (dns *propsv1.NetworkDNS, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if alias == "" {
+		return nil, scerr.InvalidParameterError("alias", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, alias), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.RemoveDNSAlias")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
+
+	network, err := handler.inspectLockingDNS(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockDNS(network.ID)
+	dns, err = handler.loadDNS(network)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := dns.Aliases[alias]; !ok {
+		return nil, resources.ResourceNotFoundError("DNS alias", alias)
+	}
+	delete(dns.Aliases, alias)
+
+	err = handler.configureDNS(ctx, network, dns)
+	if err != nil {
+		return nil, err
+	}
+	err = handler.saveDNS(network, dns)
+	if err != nil {
+		return nil, err
+	}
+	return dns, nil
+}
+
+// updateDNSRecord sets (or removes if ip is empty) the record of the host named name in the internal DNS of
+// the network identified by networkID; does nothing if the network has no internal DNS
+func (handler *NetworkHandler) updateDNSRecord(ctx context.Context, networkID, name, ip string) error {
+	if ip != "" && (!isDNSName(name) || net.ParseIP(ip) == nil) {
+		logrus.Warnf("host '%s' (%s) cannot be registered in the internal DNS of network '%s': invalid DNS name or IP address", name, ip, networkID)
+		return nil
+	}
+
+	network, err := handler.inspectLockingDNS(ctx, networkID)
+	if err != nil {
+		return err
+	}
+	defer unlockDNS(network.ID)
+	dns, err := handler.loadDNS(network)
+	if err != nil {
+		return err
+	}
+	if !dns.Enabled {
+		return nil
+	}
+	if ip == "" {
+		if _, ok := dns.Records[name]; !ok {
+			return nil
+		}
+		delete(dns.Records, name)
+	} else {
+		dns.Records[name] = ip
+	}
+
+	err = handler.configureDNS(ctx, network, dns)
+	if err != nil {
+		return err
+	}
+	return handler.saveDNS(network, dns)
+}
+
+// inspectLockingDNS locks the internal DNS of the network referenced by ref, and returns the network as recorded
+// once locked; the caller must call unlockDNS with the ID of the network once done
+func (handler *NetworkHandler) inspectLockingDNS(ctx context.Context, ref string) (*resources.Network, error) {
+	network, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	mutexDNSLocks.Lock()
+	lock, ok := dnsLocks[network.ID]
+	if !ok {
+		lock = &sync.Mutex{}
+		dnsLocks[network.ID] = lock
+	}
+	mutexDNSLocks.Unlock()
+	lock.Lock()
+
+	// Reloads the network, its DNS may have been changed while waiting for the lock
+	network, err = handler.Inspect(ctx, network.ID)
+	if err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	return network, nil
+}
+
+// unlockDNS unlocks the internal DNS of the network identified by networkID
+func unlockDNS(networkID string) {
+	mutexDNSLocks.Lock()
+	lock := dnsLocks[networkID]
+	mutexDNSLocks.Unlock()
+	if lock != nil {
+		lock.Unlock()
+	}
+}
+
+// isDNSName tells if name is made of valid DNS labels separated by dots
+func isDNSName(name string) bool {
+	if name == "" || len(name) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !dnsLabelRegexp.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadDNS returns a copy of the internal DNS configuration of the network
+func (handler *NetworkHandler) loadDNS(network *resources.Network) (dns *propsv1.NetworkDNS, err error) {
+	err = network.Properties.LockForRead(networkproperty.DNSV1).ThenUse(func(clonable data.Clonable) error {
+		dns = clonable.Clone().(*propsv1.NetworkDNS)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dns, nil
+}
+
+// configureDNS applies the internal DNS configuration on every gateway of the network, so the records stay
+// available when the VIP moves to the secondary gateway
+func (handler *NetworkHandler) configureDNS(ctx context.Context, network *resources.Network, dns *propsv1.NetworkDNS) error {
+	bashLibrary, err := system.GetBashLibrary()
+	if err != nil {
+		return err
+	}
+	scriptData := dnsScriptData{
+		BashLibrary: bashLibrary,
+		Domain:      dns.Domain,
+	}
+	names := make([]string, 0, len(dns.Records))
+	for k := range dns.Records {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		scriptData.Records = append(scriptData.Records, dnsScriptEntry{Name: k, Address: dns.Records[k]})
+	}
+	names = make([]string, 0, len(dns.Aliases))
+	for k := range dns.Aliases {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		scriptData.Aliases = append(scriptData.Aliases, dnsScriptEntry{Name: k, Target: dns.Aliases[k]})
+	}
+	for _, id := range gatewayIDs(network) {
+		gw, err := handler.service.InspectHost(id)
+		if err != nil {
+			return err
+		}
+		scriptData.GatewayIP = gw.GetPrivateIP()
+		err = exec(ctx, "network_dns.sh", scriptData, id, handler.service)
+		if err != nil {
+			return scerr.Wrap(err, fmt.Sprintf("failed to configure DNS on gateway '%s' of network '%s'", gw.Name, network.Name))
+		}
+	}
+	return nil
+}
+
+// saveDNS records the internal DNS configuration in the metadata of the network
+func (handler *NetworkHandler) saveDNS(network *resources.Network, dns *propsv1.NetworkDNS) error {
+	err := network.Properties.LockForWrite(networkproperty.DNSV1).ThenUse(func(clonable data.Clonable) error {
+		clonable.(*propsv1.NetworkDNS).Replace(dns)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = metadata.SaveNetwork(handler.service, network)
+	return err
+}
+
+// failoverScriptData contains the values used to fill the script updating keepalived on a gateway when the other one is recreated
+type failoverScriptData struct {
+	BashLibrary string
+	OldPeerIP   string
+	NewPeerIP   string
+}
+
+// CheckGateways returns the health of the gateway(s) of the network referenced by ref
+// If probe is false, only the state of the gateway(s) at the provider is checked, without SSH probe
+func (handler *NetworkHandler) CheckGateways(ctx context.Context, ref string, probe bool) (health []*resources.GatewayHealth, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %v)", ref, probe), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.CheckGateways")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
+
+	network, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return handler.checkGateways(ctx, network, probe), nil
+}
+
+func (handler *NetworkHandler) checkGateways(ctx context.Context, network *resources.Network, probe bool) []*resources.GatewayHealth {
+	var health []*resources.GatewayHealth
+	if network.GatewayID == "" {
+		return health
+	}
+	for i, id := range gatewayIDs(network) {
+		health = append(health, handler.checkGateway(ctx, network, id, i == 0, probe))
+	}
+	return health
+}
+
+// gatewayProbeCount is the number of SSH probes a gateway must fail to be considered unreachable
+const gatewayProbeCount = 3
+
+// checkGateway checks the gateway identified by id is started at the provider and, if probe is true, if it answers by
+// SSH and holds the VIP of the network
+// The gateway is probed even if not started at the provider, whose state may lag behind
+func (handler *NetworkHandler) checkGateway(ctx context.Context, network *resources.Network, id string, primary bool, probe bool) *resources.GatewayHealth {
+	health := &resources.GatewayHealth{
+		ID:      id,
+		Primary: primary,
+		State:   hoststate.UNKNOWN,
+	}
+	mgw, err := metadata.LoadHost(handler.service, id)
+	if err != nil {
+		health.Error = fmt.Sprintf("failed to load metadata: %v", err)
+		return health
+	}
+	gw, err := mgw.Get()
+	if err != nil {
+		health.Error = fmt.Sprintf("failed to load metadata: %v", err)
+		return health
+	}
+	health.Name = gw.Name
+
+	inspected, err := handler.service.InspectHost(gw)
+	if err != nil {
+		if _, ok := scerr.Cause(err).(scerr.ErrNotFound); ok {
+			health.State = hoststate.TERMINATED
+			health.Error = "gateway not found at the provider"
+			return health
+		}
+		health.Error = err.Error()
+	} else {
+		health.State = inspected.LastState
+		if health.State != hoststate.STARTED {
+			health.Error = fmt.Sprintf("gateway is %s", health.State.String())
+		}
+	}
+	if !probe {
+		return health
+	}
+
+	health.Probed = true
+	sshHandler := NewSSHHandler(handler.service)
+	for i := 0; i < gatewayProbeCount; i++ {
+		if i > 0 {
+			time.Sleep(temporal.GetMinDelay())
+		}
+		retcode, stdout, _, err := sshHandler.RunWithTimeout(ctx, id, "ip -o -4 addr show", outputs.COLLECT, temporal.GetDefaultDelay())
+		if err == nil && retcode == 0 {
+			health.Reachable = true
+			if network.VIP != nil && network.VIP.PrivateIP != "" {
+				health.HoldsVIP = strings.Contains(stdout, " "+network.VIP.PrivateIP+"/")
+			}
+			return health
+		}
+	}
+	if health.Error == "" {
+		health.Error = fmt.Sprintf("gateway does not answer by SSH (%d attempts)", gatewayProbeCount)
+	}
+	return health
+}
+
+// Repair recreates the gateway(s) of the network referenced by ref that are dead (stopped or gone at the provider and
+// not answering by SSH after several attempts), using the sizing recorded in the metadata and the image of os (by
+// default the image the gateways were created with), and binds them to the VIP of the network; the hosts of the
+// network, routed through the VIP, keep their route and have their default gateway updated in the metadata.
+// Peerings, VPN and internal DNS are configured again on the gateway(s).
+func (handler *NetworkHandler) Repair(ctx context.Context, ref, theos string) (network *resources.Network, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, theos), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "NetworkHandler.Repair")
+	defer tracing.EndSpan(span, &err)
+	handler = &NetworkHandler{service: iaas.WithContext(ctx, handler.service), ipVersion: handler.ipVersion}
+
+	mutexPeering.Lock()
+	defer mutexPeering.Unlock()
+
+	network, err = handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if network.VIP == nil || network.SecondaryGatewayID == "" {
+		return nil, scerr.InvalidRequestError(fmt.Sprintf("cannot repair network '%s': only networks created with failover can have a gateway recreated without touching their hosts", network.Name))
+	}
+
+	health := handler.checkGateways(ctx, network, true)
+	var dead []*resources.GatewayHealth
+	for _, v := range health {
+		if v.Dead() {
+			dead = append(dead, v)
+		} else if !v.Healthy() {
+			logrus.Warnf("gateway '%s' of network '%s' is not healthy (%s) but not dead, not recreated", v.Name, network.Name, v.Error)
+		}
+	}
+	if len(dead) == 0 {
+		logrus.Infof("Gateways of network '%s' are healthy, nothing to repair", network.Name)
+		return network, nil
+	}
+
+	// Recreates the dead gateway(s) from the sizing of a gateway of the network
+	sizing, templateID, err := handler.gatewaySizing(network)
+	if err != nil {
+		return nil, err
+	}
+	imageID := network.GatewayImageID
+	if theos != "" {
+		img, err := handler.service.SearchImage(theos)
+		if err != nil {
+			return nil, err
+		}
+		imageID = img.ID
+	}
+	if imageID == "" {
+		return nil, scerr.InvalidRequestError(fmt.Sprintf("no image recorded for the gateways of network '%s', an os must be given", network.Name))
+	}
+	network.GatewayImageID = imageID
+	recreated := map[string]bool{}
+	for _, v := range dead {
+		var (
+			oldIP, newIP  string
+			newID, peerID string
+		)
+		oldIP, newIP, err = handler.recreateGateway(ctx, network, v, sizing, templateID, imageID)
+		if err != nil {
+			return nil, scerr.Wrap(err, fmt.Sprintf("failed to repair gateway '%s' of network '%s'", v.Name, network.Name))
+		}
+		if v.Primary {
+			newID = network.GatewayID
+			peerID = network.SecondaryGatewayID
+		} else {
+			newID = network.SecondaryGatewayID
+			peerID = network.GatewayID
+		}
+		recreated[newID] = true
+
+		// The hosts of the network keep routing through the VIP, but their metadata still references the dead gateway
+		err = handler.updateHostsDefaultGateway(network, v.ID, newID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Tells keepalived of the other gateway, if it's alive, where to find its new peer
+		peerAlive := recreated[peerID]
+		for _, p := range health {
+			if p.ID == peerID && p.Healthy() {
+				peerAlive = true
+			}
+		}
+		if peerAlive && oldIP != "" && oldIP != newIP {
+			err = handler.updateFailoverPeer(ctx, peerID, oldIP, newIP)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Configures again the services of the network on the gateway(s)
+	err = handler.reconfigureGateways(ctx, network)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Network '%s' repaired, %d gateway%s recreated", network.Name, len(dead), utils.Plural(len(dead)))
+	return network, nil
+}
+
+// updateHostsDefaultGateway replaces oldID by newID as default gateway in the metadata of the hosts of the network
+func (handler *NetworkHandler) updateHostsDefaultGateway(network *resources.Network, oldID, newID string) error {
+	var hostIDs []string
+	err := network.Properties.LockForRead(networkproperty.HostsV1).ThenUse(func(clonable data.Clonable) error {
+		for id := range clonable.(*propsv1.NetworkHosts).ByID {
+			hostIDs = append(hostIDs, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, id := range hostIDs {
+		mh, err := metadata.LoadHost(handler.service, id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		host, err := mh.Get()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		changed := false
+		err = host.Properties.LockForWrite(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+			hostNetworkV1 := clonable.(*propsv1.HostNetwork)
+			if hostNetworkV1.DefaultGatewayID == oldID {
+				hostNetworkV1.DefaultGatewayID = newID
+				changed = true
+			}
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if changed {
+			_, err = metadata.SaveHost(handler.service, host)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return scerr.ErrListError(errs)
+	}
+	return nil
+}
+
+// gatewaySizing returns the sizing requested and the template used for the gateways of the network, read from the
+// metadata of the first gateway having them
+func (handler *NetworkHandler) gatewaySizing(network *resources.Network) (sizing resources.SizingRequirements, templateID string, err error) {
+	for _, id := range gatewayIDs(network) {
+		mgw, err := metadata.LoadHost(handler.service, id)
+		if err != nil {
+			continue
+		}
+		gw, err := mgw.Get()
+		if err != nil {
+			continue
+		}
+		err = gw.Properties.LockForRead(hostproperty.SizingV1).ThenUse(func(clonable data.Clonable) error {
+			hostSizingV1 := clonable.(*propsv1.HostSizing)
+			templateID = hostSizingV1.Template
+			if hostSizingV1.RequestedSize != nil {
+				sizing = resources.SizingRequirements{
+					MinCores:    hostSizingV1.RequestedSize.Cores,
+					MinRAMSize:  hostSizingV1.RequestedSize.RAMSize,
+					MinDiskSize: hostSizingV1.RequestedSize.DiskSize,
+					MinGPU:      hostSizingV1.RequestedSize.GPUNumber,
+					MinFreq:     hostSizingV1.RequestedSize.CPUFreq,
+				}
+			}
+			return nil
+		})
+		if err == nil && templateID != "" {
+			return sizing, templateID, nil
+		}
+	}
+	return sizing, "", scerr.NotFoundError(fmt.Sprintf("failed to find the sizing of the gateways of network '%s'", network.Name))
+}
+
+// recreateGateway deletes what remains of the gateway described by health, creates a new one with the same name and
+// role, binds it to the VIP and installs it; returns the private IP of the old gateway, if known, and of the new one
+func (handler *NetworkHandler) recreateGateway(
+	ctx context.Context, network *resources.Network, health *resources.GatewayHealth,
+	sizing resources.SizingRequirements, templateID, imageID string,
+) (oldIP string, newIP string, err error) {
+
+	// Removes the remains of the dead gateway
+	name := health.Name
+	if mgw, err := metadata.LoadHost(handler.service, health.ID); err == nil {
+		if old, err := mgw.Get(); err == nil {
+			oldIP = old.GetPrivateIP()
+			name = old.Name
+			err = metadata.RemoveHost(handler.service, old)
+			if err != nil {
+				logrus.Warnf("failed to remove metadata of gateway '%s': %v", old.Name, err)
+			}
+		}
+	}
+	if name == "" {
+		prefix := "gw2-"
+		if health.Primary {
+			prefix = "gw-"
+		}
+		name = prefix + network.Name
+		if domain := strings.Trim(network.Domain, "."); domain != "" {
+			name += "." + domain
+		}
+	}
+	if health.State != hoststate.TERMINATED {
+		err = handler.service.DeleteHost(health.ID)
+		if err != nil {
+			if _, ok := err.(scerr.ErrNotFound); !ok {
+				return "", "", err
+			}
+		}
+	}
+	err = handler.service.UnbindHostFromVIP(network.VIP, health.ID)
+	if err != nil {
+		logrus.Debugf("failed to unbind dead gateway '%s' from VIP: %v", name, err)
+	}
+
+	// Creates the new gateway
+	keypairName := "kp_" + strings.Split(name, ".")[0]
+	keypair, err := handler.service.CreateKeyPair(keypairName)
+	if err != nil {
+		return "", "", err
+	}
+	defer func() {
+		_ = handler.service.DeleteKeyPair(keypairName)
+	}()
+	result, err := handler.createGateway(nil, data.Map{
+		"request": resources.GatewayRequest{
+			ImageID:    imageID,
+			Network:    network,
+			KeyPair:    keypair,
+			TemplateID: templateID,
+			CIDR:       network.CIDR,
+			Name:       name,
+		},
+		"sizing":  sizing,
+		"primary": health.Primary,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	gw := result.(data.Map)["host"].(*resources.Host)
+	userData := result.(data.Map)["userdata"].(*userdata.Content)
+	newIP = gw.GetPrivateIP()
+
+	// The secondary gateway is bound to the VIP too, keepalived electing the gateway holding it
+	if !health.Primary {
+		err = handler.service.BindHostToVIP(network.VIP, gw.ID)
+		if err != nil {
+			return "", "", err
+		}
+		userData.PrivateVIP = network.VIP.PrivateIP
+	}
+
+	// Records the new gateway in the network; metadata.SaveGateway has overwritten the primary gateway ID
+	if health.Primary {
+		network.GatewayID = gw.ID
+	} else {
+		network.SecondaryGatewayID = gw.ID
+	}
+	_, err = metadata.SaveNetwork(handler.service, network)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Installs the new gateway, peered with the other one
+	var primary, secondary *resources.Host
+	for _, id := range gatewayIDs(network) {
+		mh, err := metadata.LoadHost(handler.service, id)
+		if err != nil {
+			return "", "", err
+		}
+		h, err := mh.Get()
+		if err != nil {
+			return "", "", err
+		}
+		if id == network.GatewayID {
+			primary = h
+		} else {
+			secondary = h
+		}
+	}
+	userData.PrimaryGatewayPrivateIP = primary.GetPrivateIP()
+	userData.PrimaryGatewayPublicIP = primary.GetPublicIP()
+	userData.SecondaryGatewayPrivateIP = secondary.GetPrivateIP()
+	userData.SecondaryGatewayPublicIP = secondary.GetPublicIP()
+	if network.Domain != "" {
+		userData.HostName = name
+	}
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	_, err = handler.waitForInstallPhase1OnGateway(task, gw)
+	if err != nil {
+		return "", "", err
+	}
+	_, err = handler.installPhase2OnGateway(task, data.Map{
+		"host":     gw,
+		"userdata": userData,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	logrus.Infof("Gateway '%s' of network '%s' recreated", name, network.Name)
+	return oldIP, newIP, nil
+}
+
+// updateFailoverPeer replaces oldIP by newIP in the configuration of keepalived on the gateway identified by id
+func (handler *NetworkHandler) updateFailoverPeer(ctx context.Context, id, oldIP, newIP string) error {
+	bashLibrary, err := system.GetBashLibrary()
+	if err != nil {
+		return err
+	}
+	scriptData := failoverScriptData{
+		BashLibrary: bashLibrary,
+		OldPeerIP:   strings.Replace(oldIP, ".", "\\.", -1),
+		NewPeerIP:   newIP,
+	}
+	err = exec(ctx, "network_failover.sh", scriptData, id, handler.service)
+	if err != nil {
+		return scerr.Wrap(err, "failed to update keepalived configuration of gateway")
+	}
+	return nil
+}
+
+// reconfigureGateways applies again the peerings, the VPN and the internal DNS of the network on its gateway(s)
+func (handler *NetworkHandler) reconfigureGateways(ctx context.Context, network *resources.Network) error {
+	var peerings []*propsv1.NetworkPeering
+	err := network.Properties.LockForRead(networkproperty.PeeringsV1).ThenUse(func(clonable data.Clonable) error {
+		for _, v := range clonable.(*propsv1.NetworkPeerings).ByID {
+			peerings = append(peerings, v)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, v := range peerings {
+		err = handler.configurePeering(ctx, network, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	var vpn *propsv1.NetworkVPN
+	err = network.Properties.LockForRead(networkproperty.VPNV1).ThenUse(func(clonable data.Clonable) error {
+		vpn = clonable.Clone().(*propsv1.NetworkVPN)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(vpn.Peers) > 0 {
+		err = handler.configureVPN(ctx, network, vpn)
+		if err != nil {
+			return err
+		}
+	}
+
+	dns, err := handler.loadDNS(network)
+	if err != nil {
+		return err
+	}
+	if dns.Enabled {
+		for _, id := range gatewayIDs(network) {
+			mgw, err := metadata.LoadHost(handler.service, id)
+			if err != nil {
+				return err
+			}
+			gw, err := mgw.Get()
+			if err != nil {
+				return err
+			}
+			dns.Records[gw.Name] = gw.GetPrivateIP()
+		}
+		err = handler.configureDNS(ctx, network, dns)
+		if err != nil {
+			return err
+		}
+		return handler.saveDNS(network, dns)
+	}
+	return nil
+}
@@ -16,6 +16,12 @@
 
 package handlers
 
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
 // FIXME: iaas.Service became an interface, so cannot be used as before.
 //       Need to write a service struct satisfying iaas.Service interface
 //       and then initializes an instance of this service struct
@@ -71,3 +77,13 @@ package handlers
 
 // 	assert.Nil(t, result)
 // }
+
+func TestIsDNSName(t *testing.T) {
+	assert.True(t, isDNSName("myhost"))
+	assert.True(t, isDNSName("gw-my_network.example.com"))
+	assert.False(t, isDNSName(""))
+	assert.False(t, isDNSName("-myhost"))
+	assert.False(t, isDNSName("example..com"))
+	assert.False(t, isDNSName("db$(reboot)"))
+	assert.False(t, isDNSName("db\nserver=1.2.3.4"))
+}
@@ -0,0 +1,424 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
+)
+
+//go:generate mockgen -destination=../mocks/mock_publicipapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers PublicIPAPI
+
+// PublicIPAPI defines API to manipulate public IPs
+type PublicIPAPI interface {
+	Allocate(ctx context.Context, name string) (*resources.PublicIP, error)
+	List(ctx context.Context, all bool) ([]*resources.PublicIP, error)
+	Inspect(ctx context.Context, ref string) (*resources.PublicIP, error)
+	Attach(ctx context.Context, ref string, hostRef string) (*resources.PublicIP, error)
+	Detach(ctx context.Context, ref string) (*resources.PublicIP, error)
+	Release(ctx context.Context, ref string) error
+}
+
+// PublicIPHandler public IP service
+type PublicIPHandler struct {
+	service iaas.Service
+}
+
+// NewPublicIPHandler creates a PublicIP service
+func NewPublicIPHandler(svc iaas.Service) PublicIPAPI {
+	return &PublicIPHandler{
+		service: svc,
+	}
+}
+
+// mutexPublicIP serializes the moves of public IPs between hosts
+var mutexPublicIP sync.Mutex
+
+// Allocate reserves a new public IP on the provider
+func (handler *PublicIPHandler) Allocate(ctx context.Context, name string) (ip *resources.PublicIP, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "PublicIPHandler.Allocate")
+	defer tracing.EndSpan(span, &err)
+	handler = &PublicIPHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	_, err = metadata.LoadPublicIP(handler.service, name)
+	if err == nil {
+		return nil, resources.ResourceDuplicateError("public IP", name)
+	}
+	if _, ok := scerr.Cause(err).(scerr.ErrNotFound); !ok {
+		return nil, err
+	}
+
+	ip, err = handler.service.AllocatePublicIP(name)
+	if err != nil {
+		return nil, err
+	}
+	// Starting from here, releases the public IP if exiting with error
+	defer func() {
+		if err != nil {
+			derr := handler.service.ReleasePublicIP(ip)
+			if derr != nil {
+				logrus.Errorf("failed to release public IP '%s': %v", ip.Address, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+	ip.Name = name
+
+	_, err = metadata.SavePublicIP(handler.service, ip)
+	if err != nil {
+		return nil, err
+	}
+	logrus.Infof("Public IP '%s' allocated: %s", name, ip.Address)
+	return ip, nil
+}
+
+// List returns the public IPs managed by SafeScale, or all the public IPs of the tenant if all is true
+func (handler *PublicIPHandler) List(ctx context.Context, all bool) (ips []*resources.PublicIP, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "PublicIPHandler.List")
+	defer tracing.EndSpan(span, &err)
+	handler = &PublicIPHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	if all {
+		return handler.service.ListPublicIPs()
+	}
+
+	mip, err := metadata.NewPublicIP(handler.service)
+	if err != nil {
+		return nil, err
+	}
+	err = mip.Browse(func(ip *resources.PublicIP) error {
+		ips = append(ips, ip)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ips, nil
+}
+
+// Inspect returns the public IP referenced by ref
+func (handler *PublicIPHandler) Inspect(ctx context.Context, ref string) (ip *resources.PublicIP, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "PublicIPHandler.Inspect")
+	defer tracing.EndSpan(span, &err)
+	handler = &PublicIPHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	mip, err := metadata.LoadPublicIP(handler.service, ref)
+	if err != nil {
+		if _, ok := scerr.Cause(err).(scerr.ErrNotFound); ok {
+			return nil, resources.ResourceNotFoundError("public IP", ref)
+		}
+		return nil, err
+	}
+	return mip.Get()
+}
+
+// Attach binds the public IP referenced by ref to the host referenced by hostRef
+// If the public IP is attached to another host, it's moved; the host it's moved from may have been deleted already
+func (handler *PublicIPHandler) Attach(ctx context.Context, ref string, hostRef string) (ip *resources.PublicIP, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+	if hostRef == "" {
+		return nil, scerr.InvalidParameterError("hostRef", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, hostRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "PublicIPHandler.Attach")
+	defer tracing.EndSpan(span, &err)
+	handler = &PublicIPHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	mutexPublicIP.Lock()
+	defer mutexPublicIP.Unlock()
+
+	ip, err = handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	host, err := NewHostHandler(handler.service).Inspect(ctx, hostRef)
+	if err != nil {
+		return nil, err
+	}
+	if ip.HostID == host.ID {
+		return ip, nil
+	}
+	err = host.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+		if clonable.(*propsv1.HostNetwork).IsGateway {
+			return fmt.Errorf("cannot attach public IP to host '%s', it's a gateway whose public IP is managed by its network", host.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	previousHostID := ip.HostID
+	if previousHostID != "" {
+		err = handler.service.DetachPublicIP(ip)
+		if err != nil {
+			return nil, err
+		}
+		// Starting from here, attaches back the public IP to its previous host if exiting with error
+		defer func() {
+			if err != nil {
+				derr := handler.restore(ip, previousHostID, host.ID)
+				if derr != nil {
+					logrus.Errorf("failed to attach back public IP '%s' to host '%s': %v", ip.Address, previousHostID, derr)
+					err = scerr.AddConsequence(err, derr)
+				}
+			}
+		}()
+		err = handler.updateHostPublicIP(previousHostID, ip.Address, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = handler.service.AttachPublicIP(ip, host.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = handler.updateHostPublicIP(host.ID, "", ip.Address)
+	if err != nil {
+		return nil, err
+	}
+	_, err = metadata.SavePublicIP(handler.service, ip)
+	if err != nil {
+		return nil, err
+	}
+	logrus.Infof("Public IP '%s' (%s) attached to host '%s'", ip.Name, ip.Address, host.Name)
+	return ip, nil
+}
+
+// Detach unbinds the public IP referenced by ref from its host
+func (handler *PublicIPHandler) Detach(ctx context.Context, ref string) (ip *resources.PublicIP, err error) {
+	if handler == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "PublicIPHandler.Detach")
+	defer tracing.EndSpan(span, &err)
+	handler = &PublicIPHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	mutexPublicIP.Lock()
+	defer mutexPublicIP.Unlock()
+
+	ip, err = handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if ip.HostID == "" {
+		return nil, fmt.Errorf("cannot detach public IP '%s': not attached to any host", ref)
+	}
+	err = handler.detach(ip)
+	if err != nil {
+		return nil, err
+	}
+	return ip, nil
+}
+
+// Release gives back the public IP referenced by ref to the provider
+func (handler *PublicIPHandler) Release(ctx context.Context, ref string) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "PublicIPHandler.Release")
+	defer tracing.EndSpan(span, &err)
+	handler = &PublicIPHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	mutexPublicIP.Lock()
+	defer mutexPublicIP.Unlock()
+
+	ip, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if ip.HostID != "" {
+		return fmt.Errorf("cannot release public IP '%s': attached to host '%s', detach it first", ref, ip.HostID)
+	}
+	err = handler.service.ReleasePublicIP(ip)
+	if err != nil {
+		if _, ok := scerr.Cause(err).(scerr.ErrNotFound); !ok {
+			return err
+		}
+		logrus.Warnf("public IP '%s' not found on provider, removing metadata only", ref)
+	}
+	err = metadata.RemovePublicIP(handler.service, ip.ID)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Public IP '%s' (%s) released", ip.Name, ip.Address)
+	return nil
+}
+
+// detachFromHost detaches the public IPs attached to the host identified by hostID, keeping them allocated
+// Used before deleting a host, the stacks releasing the public IPs of the hosts they delete
+func (handler *PublicIPHandler) detachFromHost(hostID string) error {
+	mutexPublicIP.Lock()
+	defer mutexPublicIP.Unlock()
+
+	mip, err := metadata.NewPublicIP(handler.service)
+	if err != nil {
+		return err
+	}
+	var attached []*resources.PublicIP
+	err = mip.Browse(func(ip *resources.PublicIP) error {
+		if ip.HostID == hostID {
+			attached = append(attached, ip)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, ip := range attached {
+		err = handler.detach(ip)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detach unbinds ip from its host, updating the metadata of both
+func (handler *PublicIPHandler) detach(ip *resources.PublicIP) error {
+	hostID := ip.HostID
+	err := handler.service.DetachPublicIP(ip)
+	if err != nil {
+		return err
+	}
+	ip.HostID = ""
+	err = handler.updateHostPublicIP(hostID, ip.Address, "")
+	if err != nil {
+		return err
+	}
+	_, err = metadata.SavePublicIP(handler.service, ip)
+	return err
+}
+
+// restore attaches back ip to the host identified by previousHostID after a failed move to the host identified
+// by hostID, and updates the metadata of both hosts and of ip
+func (handler *PublicIPHandler) restore(ip *resources.PublicIP, previousHostID, hostID string) error {
+	if ip.HostID == hostID {
+		err := handler.service.DetachPublicIP(ip)
+		if err != nil {
+			return err
+		}
+	}
+	err := handler.updateHostPublicIP(hostID, ip.Address, "")
+	if err != nil {
+		return err
+	}
+	err = handler.service.AttachPublicIP(ip, previousHostID)
+	if err != nil {
+		return err
+	}
+	ip.HostID = previousHostID
+	err = handler.updateHostPublicIP(previousHostID, "", ip.Address)
+	if err != nil {
+		return err
+	}
+	_, err = metadata.SavePublicIP(handler.service, ip)
+	return err
+}
+
+// updateHostPublicIP replaces the public IPv4 oldIP of the host identified by hostID by newIP
+// When attaching (oldIP empty), the public IPv4 of the host is set only if it has none, to keep the public IP
+// the host was created with; when detaching (newIP empty), it's cleared only if it's the IP detached
+// A missing host is not an error, the public IP may be moved from a host already deleted
+func (handler *PublicIPHandler) updateHostPublicIP(hostID, oldIP, newIP string) error {
+	mh, err := metadata.LoadHost(handler.service, hostID)
+	if err != nil {
+		if _, ok := scerr.Cause(err).(scerr.ErrNotFound); ok {
+			return nil
+		}
+		return err
+	}
+	host, err := mh.Get()
+	if err != nil {
+		return err
+	}
+	err = host.Properties.LockForWrite(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
+		hostNetworkV1 := clonable.(*propsv1.HostNetwork)
+		if hostNetworkV1.PublicIPv4 == oldIP {
+			hostNetworkV1.PublicIPv4 = newIP
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = metadata.SaveHost(handler.service, host)
+	return err
+}
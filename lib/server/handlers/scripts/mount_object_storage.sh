@@ -14,44 +14,145 @@
 # See the License for the specific language governing permissions and
 # limitations under the License.
 
-# Instal s3ql
-# TODO move this installation in a dedicated go executable which will be eanble to handle different linux flavor (apt, yum, ...)
-apt-get update && apt-get install -y s3ql && apt-get clean && rm -rf /var/lib/apt/lists/*
-
-mkdir -p /etc/s3ql
-
-# Create auth file
-cat <<- EOF > /etc/s3ql/auth.{{.Bucket}}
-[swift]
-backend-login: {{.Tenant}}:{{.Login}}
-backend-password: {{.Password}}
-storage-url: {{.Protocol}}://{{.AuthURL}}/{{.Region}}:{{.Bucket}}
-fs-passpharse: {{.Password}}
-EOF
+# Mounts a bucket with a FUSE backend (rclone for Swift, s3fs for S3, gcsfuse for Google Cloud Storage), run by a
+# systemd service so the mount survives reboots; the credentials are kept in a file readable only by root
+
+{{ .BashLibrary }}
 
-chmod 0600 /etc/s3ql/auth.{{.Bucket}}
+BUCKET="{{ .Bucket }}"
+MOUNTPOINT="{{ .MountPoint }}"
+CONF_DIR=/etc/safescale/buckets
+CONF=${CONF_DIR}/${BUCKET}.conf
+UNIT=safescale-bucket-${BUCKET}
 
-# Format filesystem
-echo "{{.Password}}"| mkfs.s3ql --authfile /etc/s3ql/auth.{{.Bucket}} --quiet {{.Protocol}}://{{.AuthURL}}/{{.Region}}:{{.Bucket}}
+# Installs the backend if needed
+case $LINUX_KIND in
+ubuntu | debian)
+  sfApt update || exit 191
+  sfApt install -y fuse curl || exit 191
+  ;;
+redhat | rhel | centos | fedora)
+  yum install -q -y fuse curl || exit 191
+  ;;
+*)
+  echo "Unsupported Linux distribution '$LINUX_KIND'!"
+  exit 1
+  ;;
+esac
 
-# Create MountPoint
-mkdir -p {{.MountPoint}}
+{{- if eq .Backend "rclone" }}
+if ! which rclone &>/dev/null; then
+  case $LINUX_KIND in
+  ubuntu | debian)
+    sfApt install -y rclone || exit 191
+    ;;
+  *)
+    yum install -q -y epel-release || true
+    yum install -q -y rclone || exit 191
+    ;;
+  esac
+fi
+{{- else if eq .Backend "s3fs" }}
+if ! which s3fs &>/dev/null; then
+  case $LINUX_KIND in
+  ubuntu | debian)
+    sfApt install -y s3fs || exit 191
+    ;;
+  *)
+    yum install -q -y epel-release || true
+    yum install -q -y s3fs-fuse || exit 191
+    ;;
+  esac
+fi
+{{- else if eq .Backend "gcsfuse" }}
+if ! which gcsfuse &>/dev/null; then
+  case $LINUX_KIND in
+  ubuntu | debian)
+    sfApt install -y gnupg lsb-release || exit 191
+    echo "deb https://packages.cloud.google.com/apt gcsfuse-$(lsb_release -c -s) main" >/etc/apt/sources.list.d/gcsfuse.list
+    curl -fsSL https://packages.cloud.google.com/apt/doc/apt-key.gpg | apt-key add - || exit 191
+    sfApt update && sfApt install -y gcsfuse || exit 191
+    ;;
+  *)
+    cat >/etc/yum.repos.d/gcsfuse.repo <<-'EOF'
+[gcsfuse]
+name=gcsfuse (packages.cloud.google.com)
+baseurl=https://packages.cloud.google.com/yum/repos/gcsfuse-el7-x86_64
+enabled=1
+gpgcheck=1
+repo_gpgcheck=0
+gpgkey=https://packages.cloud.google.com/yum/doc/yum-key.gpg https://packages.cloud.google.com/yum/doc/rpm-package-key.gpg
+EOF
+    yum install -q -y gcsfuse || exit 191
+    ;;
+  esac
+fi
+{{- end }}
 
-# Create script to mount container
-cat <<- FOE > /usr/local/bin/mount-{{.Bucket}}
-sudo /bin/bash << EOF
-echo "{{.Password}}" |mount.s3ql --allow-other --authfile /etc/s3ql/auth.{{.Bucket}} {{.Protocol}}://{{.AuthURL}}/{{.Region}}:{{.Bucket}} {{.MountPoint}}
+# Writes the credentials in a file readable only by root
+mkdir -p ${CONF_DIR}
+chmod 0700 ${CONF_DIR}
+umask 077
+{{- if eq .Backend "rclone" }}
+cat >${CONF} <<-'EOF'
+[remote]
+type = swift
+user = {{ .User }}
+key = {{ .Password }}
+auth = {{ .AuthURL }}
+{{- if .AuthVersion }}
+auth_version = {{ .AuthVersion }}
+{{- end }}
+tenant = {{ .Tenant }}
+domain = {{ .Domain }}
+tenant_domain = {{ .Domain }}
+region = {{ .Region }}
+EOF
+# Releases of rclone packaged by older distributions don't have the VFS cache
+VFS_CACHE=
+rclone mount --help 2>&1 | grep -q -- "--vfs-cache-mode" && VFS_CACHE="--vfs-cache-mode writes"
+EXEC="$(which rclone) mount --config ${CONF} --allow-other ${VFS_CACHE} remote:${BUCKET} ${MOUNTPOINT}"
+{{- else if eq .Backend "s3fs" }}
+cat >${CONF} <<-'EOF'
+{{ .User }}:{{ .Password }}
 EOF
-FOE
-chmod +x /usr/local/bin/mount-{{.Bucket}}
+EXEC="$(which s3fs) ${BUCKET} ${MOUNTPOINT} -f -o passwd_file=${CONF},allow_other{{ if .Endpoint }},url={{ .Endpoint }},use_path_request_style{{ end }}{{ if .Region }},endpoint={{ .Region }}{{ end }}"
+{{- else if eq .Backend "gcsfuse" }}
+cat >${CONF} <<-'EOF'
+{{ .Credentials }}
+EOF
+EXEC="$(which gcsfuse) --foreground --implicit-dirs --key-file ${CONF} -o allow_other ${BUCKET} ${MOUNTPOINT}"
+{{- end }}
+chmod 0600 ${CONF}
+umask 022
+
+mkdir -p ${MOUNTPOINT}
+
+# Runs the mount as a service
+cat >/etc/systemd/system/${UNIT}.service <<-EOF
+[Unit]
+Description=Mount of bucket ${BUCKET} in ${MOUNTPOINT}
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=simple
+ExecStart=${EXEC}
+ExecStop=/bin/fusermount -uz ${MOUNTPOINT}
+Restart=on-failure
+RestartSec=5
 
-# Create script to umount container
-cat <<- FOE > /usr/local/bin/umount-{{.Bucket}}
-sudo /bin/bash << EOF
-echo "{{.Password}}" |umount.s3ql {{.MountPoint}}
+[Install]
+WantedBy=multi-user.target
 EOF
-FOE
-chmod +x /usr/local/bin/umount-{{.Bucket}}
+systemctl daemon-reload
+systemctl enable ${UNIT} &>/dev/null
+systemctl restart ${UNIT} || exit 192
 
-/usr/local/bin/mount-{{.Bucket}}
-chmod a+w {{.MountPoint}}
+# Waits for the mount to be effective
+for i in {1..30}; do
+  mountpoint -q ${MOUNTPOINT} && exit 0
+  sleep 2
+done
+journalctl -u ${UNIT} --no-pager -n 20
+exit 193
@@ -0,0 +1,72 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+{{ .BashLibrary }}
+
+CONF=/etc/dnsmasq.d/safescale.conf
+HOSTS=/etc/safescale/dns.hosts
+
+# Installs dnsmasq if needed
+if ! which dnsmasq &>/dev/null; then
+  case $LINUX_KIND in
+  ubuntu | debian)
+    sfApt update && sfApt install -y dnsmasq || exit 191
+    ;;
+  redhat | rhel | centos | fedora)
+    if which dnf; then
+      dnf install -q -y dnsmasq || exit 191
+    else
+      yum install -q -y dnsmasq || exit 191
+    fi
+    ;;
+  *)
+    echo "Unsupported Linux distribution '$LINUX_KIND'!"
+    exit 1
+    ;;
+  esac
+fi
+
+# Finds the interface of the gateway in the network; the DNS service listens only on it (private interfaces are
+# in zone trusted, so no firewall rule is needed), including on the VIP when keepalived sets it
+IF=$(ip -o -4 addr show | awk '{split($4, a, "/"); if (a[1] == "{{ .GatewayIP }}") print $2}' | head -n 1)
+[ -z "$IF" ] && echo "Failed to find the interface of IP {{ .GatewayIP }}" && exit 192
+
+# (Re)creates the records of the network
+mkdir -p /etc/safescale /etc/dnsmasq.d
+cat <<-'EOF' >${HOSTS}
+{{- range .Records }}
+{{ .Address }} {{ if $.Domain }}{{ .Name }}.{{ $.Domain }} {{ end }}{{ .Name }}
+{{- end }}
+EOF
+# The names and addresses are validated by safescaled; the heredocs are quoted anyway, nothing must be expanded
+echo "interface=${IF}" >${CONF}
+echo "addn-hosts=${HOSTS}" >>${CONF}
+cat <<-'EOF' >>${CONF}
+bind-dynamic
+no-hosts
+{{- if .Domain }}
+domain={{ .Domain }}
+local=/{{ .Domain }}/
+{{- end }}
+{{- range .Aliases }}
+cname={{ .Name }}{{ if $.Domain }},{{ .Name }}.{{ $.Domain }}{{ end }},{{ .Target }}
+{{- end }}
+EOF
+grep -q "^conf-dir=/etc/dnsmasq.d" /etc/dnsmasq.conf || echo "conf-dir=/etc/dnsmasq.d/,*.conf" >>/etc/dnsmasq.conf
+
+systemctl enable dnsmasq || exit 193
+systemctl restart dnsmasq || exit 193
+exit 0
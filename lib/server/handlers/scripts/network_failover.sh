@@ -0,0 +1,25 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+
+{{ .BashLibrary }}
+
+# Replaces the IP of the gateway recreated by the repair of the network in the configuration of keepalived
+CONF=/etc/keepalived/keepalived.conf
+[ -f ${CONF} ] || exit 0
+sed -i "s/^\([[:space:]]*\){{ .OldPeerIP }}[[:space:]]*$/\1{{ .NewPeerIP }}/" ${CONF} || exit 193
+sfService restart keepalived || exit 193
+exit 0
@@ -0,0 +1,65 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+{{ .BashLibrary }}
+
+# Installs WireGuard if needed
+if ! which wg-quick &>/dev/null; then
+  case $LINUX_KIND in
+  ubuntu | debian)
+    sfApt update && sfApt install -y wireguard || exit 191
+    ;;
+  redhat | rhel | centos | fedora)
+    if which dnf; then
+      dnf install -q -y epel-release elrepo-release || true
+      dnf install -q -y wireguard-tools kmod-wireguard || dnf install -q -y wireguard-tools || exit 191
+    else
+      yum install -q -y epel-release elrepo-release || true
+      yum install -q -y wireguard-tools kmod-wireguard || exit 191
+    fi
+    ;;
+  *)
+    echo "Unsupported Linux distribution '$LINUX_KIND'!"
+    exit 1
+    ;;
+  esac
+fi
+
+# Creates the tunnel towards the peer network; routes to the peer CIDR are created by wg-quick from AllowedIPs
+mkdir -p /etc/wireguard
+umask 077
+cat <<-EOF2 >/etc/wireguard/{{ .Interface }}.conf
+[Interface]
+PrivateKey = {{ .PrivateKey }}
+ListenPort = {{ .Port }}
+
+[Peer]
+PublicKey = {{ .PeerPublicKey }}
+Endpoint = {{ .PeerEndpoint }}
+AllowedIPs = {{ .PeerCIDR }}
+PersistentKeepalive = 25
+EOF2
+
+# Opens the tunnel port, and routes traffic between networks without masquerading
+sfFirewallAdd --zone=public --add-port={{ .Port }}/udp || exit 192
+sfFirewallAdd --zone=trusted --add-interface={{ .Interface }} || exit 192
+sfFirewallAdd --direct --add-rule ipv4 nat POSTROUTING 0 -o {{ .Interface }} -j ACCEPT || exit 192
+sfFirewallAdd --direct --add-rule ipv4 nat POSTROUTING 0 -s {{ .PeerCIDR }} -d {{ .CIDR }} -j ACCEPT || exit 192
+sfFirewallReload || exit 192
+
+systemctl enable wg-quick@{{ .Interface }} || exit 193
+systemctl restart wg-quick@{{ .Interface }} || exit 193
+exit 0
@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+{{ .BashLibrary }}
+
+systemctl disable wg-quick@{{ .Interface }} &>/dev/null
+systemctl stop wg-quick@{{ .Interface }} &>/dev/null
+rm -f /etc/wireguard/{{ .Interface }}.conf
+
+sfFirewallAdd --zone=public --remove-port={{ .Port }}/udp &>/dev/null
+sfFirewallAdd --zone=trusted --remove-interface={{ .Interface }} &>/dev/null
+sfFirewallAdd --direct --remove-rule ipv4 nat POSTROUTING 0 -o {{ .Interface }} -j ACCEPT &>/dev/null
+sfFirewallAdd --direct --remove-rule ipv4 nat POSTROUTING 0 -s {{ .PeerCIDR }} -d {{ .CIDR }} -j ACCEPT &>/dev/null
+sfFirewallReload || exit 192
+exit 0
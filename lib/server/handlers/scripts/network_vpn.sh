@@ -0,0 +1,135 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+{{ .BashLibrary }}
+
+IF={{ .Interface }}
+RULES=/etc/wireguard/${IF}.rules
+
+# Removes the firewall rules set by a previous run
+remove_rules() {
+  [ -f ${RULES} ] || return 0
+  while read -r rule; do
+    [ -z "$rule" ] && continue
+    sfFirewallAdd --direct --remove-rule ${rule} &>/dev/null
+  done <${RULES}
+  rm -f ${RULES}
+}
+
+{{- if not .Peers }}
+# No remote site left, removes the VPN
+systemctl disable wg-quick@${IF} &>/dev/null
+systemctl stop wg-quick@${IF} &>/dev/null
+rm -f /etc/wireguard/${IF}.conf /etc/keepalived/notify.d/${IF}
+remove_rules
+sfFirewallAdd --zone=public --remove-port={{ .Port }}/udp &>/dev/null
+sfFirewallAdd --zone=trusted --remove-interface=${IF} &>/dev/null
+sfFirewallReload || exit 192
+exit 0
+{{- else }}
+
+# Installs WireGuard if needed
+if ! which wg-quick &>/dev/null; then
+  case $LINUX_KIND in
+  ubuntu | debian)
+    sfApt update && sfApt install -y wireguard || exit 191
+    ;;
+  redhat | rhel | centos | fedora)
+    if which dnf; then
+      dnf install -q -y epel-release elrepo-release || true
+      dnf install -q -y wireguard-tools kmod-wireguard || dnf install -q -y wireguard-tools || exit 191
+    else
+      yum install -q -y epel-release elrepo-release || true
+      yum install -q -y wireguard-tools kmod-wireguard || exit 191
+    fi
+    ;;
+  *)
+    echo "Unsupported Linux distribution '$LINUX_KIND'!"
+    exit 1
+    ;;
+  esac
+fi
+
+# (Re)creates the configuration of the VPN; routes to the remote sites are created by wg-quick from AllowedIPs
+mkdir -p /etc/wireguard
+umask 077
+cat <<-EOF >/etc/wireguard/${IF}.conf
+[Interface]
+PrivateKey = {{ .PrivateKey }}
+ListenPort = {{ .Port }}
+{{ range .Peers }}
+# {{ .Name }}
+[Peer]
+PublicKey = {{ .PublicKey }}
+{{- if .Endpoint }}
+Endpoint = {{ .Endpoint }}
+{{- end }}
+AllowedIPs = {{ range $i, $ip := .AllowedIPs }}{{ if $i }}, {{ end }}{{ $ip }}{{ end }}
+PersistentKeepalive = 25
+{{ end }}
+EOF
+
+# Opens the VPN port, and routes traffic between the remote sites and the network without masquerading
+remove_rules
+cat <<-EOF >${RULES}
+ipv4 nat POSTROUTING 0 -o ${IF} -j ACCEPT
+{{- range .Peers }}{{ range .AllowedIPs }}
+ipv4 nat POSTROUTING 0 -s {{ . }} -d {{ $.CIDR }} -j ACCEPT
+{{- end }}{{ end }}
+EOF
+while read -r rule; do
+  [ -z "$rule" ] && continue
+  sfFirewallAdd --direct --add-rule ${rule} || exit 192
+done <${RULES}
+sfFirewallAdd --zone=public --add-port={{ .Port }}/udp || exit 192
+sfFirewallAdd --zone=trusted --add-interface=${IF} || exit 192
+sfFirewallReload || exit 192
+
+{{- if .PrivateVIP }}
+# With failover, only the gateway holding the VIP runs the VPN; keepalived starts or stops it on transitions
+systemctl disable wg-quick@${IF} &>/dev/null
+mkdir -p /etc/keepalived/notify.d
+cat <<-EOF >/etc/keepalived/notify.d/${IF}
+#!/usr/bin/env bash
+case "\$3" in
+MASTER) systemctl restart wg-quick@${IF} ;;
+*) systemctl stop wg-quick@${IF} ;;
+esac
+EOF
+chmod u+x /etc/keepalived/notify.d/${IF}
+cat <<-'EOF' >/etc/keepalived/notify.sh
+#!/usr/bin/env bash
+for s in /etc/keepalived/notify.d/*; do
+  [ -x "$s" ] && "$s" "$@"
+done
+exit 0
+EOF
+chmod u+x /etc/keepalived/notify.sh
+if ! grep -q "notify /etc/keepalived/notify.sh" /etc/keepalived/keepalived.conf; then
+  sed -i '/^vrrp_instance vrrp_group_gws_internal {/a\    notify /etc/keepalived/notify.sh' /etc/keepalived/keepalived.conf || exit 193
+  systemctl reload keepalived || exit 193
+fi
+if ip -o addr show | grep -q " {{ .PrivateVIP }}/"; then
+  systemctl restart wg-quick@${IF} || exit 193
+else
+  systemctl stop wg-quick@${IF} &>/dev/null
+fi
+{{- else }}
+systemctl enable wg-quick@${IF} || exit 193
+systemctl restart wg-quick@${IF} || exit 193
+{{- end }}
+exit 0
+{{- end }}
@@ -0,0 +1,61 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+{{ .BashLibrary }}
+
+# Installs CIFS client if needed
+if ! which mount.cifs &>/dev/null; then
+  case $LINUX_KIND in
+  ubuntu | debian)
+    sfApt update && sfApt install -y cifs-utils || exit 191
+    ;;
+  redhat | rhel | centos | fedora)
+    yum install -q -y cifs-utils || exit 191
+    ;;
+  *)
+    echo "Unsupported Linux distribution '$LINUX_KIND'!"
+    exit 1
+    ;;
+  esac
+fi
+
+{{- if eq .SecurityMode "sys" }}
+
+# Credentials of the Samba user of the share, readable by root only
+mkdir -p "$(dirname "{{ .Credentials }}")"
+(
+  umask 077
+  cat >"{{ .Credentials }}" <<-'EOF'
+username={{ .User }}
+password={{ .Password }}
+EOF
+)
+{{- else if ne .SecurityMode "none" }}
+
+# Kerberos needs a keytab to get the credentials of the host
+if [ ! -f /etc/krb5.keytab ]; then
+  echo "No Kerberos keytab found in /etc/krb5.keytab, the host must be joined to a Kerberos realm first" >&2
+  exit 192
+fi
+{{- end }}
+
+OPTIONS="{{ .Options }},cache={{ if .WithCache }}strict{{ else }}none{{ end }}"
+
+mkdir -p "{{ .MountPoint }}"
+mount -t cifs -o ${OPTIONS} "{{ .Export }}" "{{ .MountPoint }}" || exit 193
+sed -i '\#^{{ .Export }} #d' /etc/fstab
+echo "{{ .Export }} {{ .MountPoint }}   cifs _netdev,noatime,${OPTIONS} 0   0" >>/etc/fstab
+exit 0
@@ -0,0 +1,109 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+{{ .BashLibrary }}
+
+CONF=/etc/samba/smb.conf
+BEGIN="# BEGIN safescale share {{ .Name }}"
+END="# END safescale share {{ .Name }}"
+
+# Installs Samba if needed
+if ! which smbd &>/dev/null; then
+  case $LINUX_KIND in
+  ubuntu | debian)
+    sfApt update && sfApt install -y samba || exit 191
+    ;;
+  redhat | rhel | centos | fedora)
+    yum install -q -y samba || exit 191
+    ;;
+  *)
+    echo "Unsupported Linux distribution '$LINUX_KIND'!"
+    exit 1
+    ;;
+  esac
+fi
+case $LINUX_KIND in
+ubuntu | debian)
+  SERVICE=smbd
+  ;;
+*)
+  SERVICE=smb
+  setsebool -P samba_export_all_rw 1 &>/dev/null || true
+  ;;
+esac
+
+{{- if eq .SecurityMode "sys" }}
+
+# Samba user of the shares, without login on the host; its password is (re)set to the one of the shares
+id -u {{ .User }} &>/dev/null || useradd --system --no-create-home --shell /usr/sbin/nologin {{ .User }} || exit 192
+printf '%s\n%s\n' '{{ .Password }}' '{{ .Password }}' | smbpasswd -s -a {{ .User }} >/dev/null || exit 192
+smbpasswd -e {{ .User }} >/dev/null || exit 192
+{{- else if eq .SecurityMode "none" }}
+
+# Guest access, asked for explicitly
+grep -q "^[[:space:]]*map to guest" ${CONF} || sed -i '/^\[global\]/a \   map to guest = Bad User' ${CONF}
+{{- else }}
+
+# Kerberos needs the host to be a member of an Active Directory domain
+if ! testparm -s 2>/dev/null | grep -qi "^[[:space:]]*security = ads"; then
+  echo "Samba is not a member of an Active Directory domain, cannot export share with Kerberos security" >&2
+  exit 192
+fi
+{{- end }}
+
+# Replaces the previous definition of the share, if any
+sed -i "/^${BEGIN}\$/,/^${END}\$/d" ${CONF}
+
+mkdir -p "{{ .Path }}"
+{{- if and (eq .SecurityMode "sys") .RootSquash }}
+# The Samba user writes in the share as itself
+chgrp {{ .User }} "{{ .Path }}" && chmod g+rwx "{{ .Path }}"
+{{- end }}
+
+cat >>${CONF} <<-EOF
+${BEGIN}
+[{{ .Name }}]
+   path = {{ .Path }}
+   browseable = yes
+   read only = {{ if .ReadOnly }}yes{{ else }}no{{ end }}
+{{- if eq .SecurityMode "none" }}
+   guest ok = yes
+   guest only = yes
+{{- else }}
+   guest ok = no
+{{- if eq .SecurityMode "sys" }}
+   valid users = {{ .User }}
+{{- end }}
+{{- if not .RootSquash }}
+   force user = root
+{{- end }}
+{{- end }}
+{{- if eq .SecurityMode "krb5p" }}
+   smb encrypt = required
+{{- else if eq .SecurityMode "krb5i" }}
+   server signing = mandatory
+{{- end }}
+${END}
+EOF
+
+testparm -s ${CONF} &>/dev/null || exit 193
+
+sfFirewallAdd --zone=trusted --add-service=samba &>/dev/null
+sfFirewallReload &>/dev/null || true
+
+systemctl enable ${SERVICE} &>/dev/null
+systemctl restart ${SERVICE} || exit 194
+exit 0
@@ -0,0 +1,36 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+{{ .BashLibrary }}
+
+CONF=/etc/samba/smb.conf
+BEGIN="# BEGIN safescale share {{ .Name }}"
+END="# END safescale share {{ .Name }}"
+
+[ -f ${CONF} ] || exit 0
+sed -i "/^${BEGIN}\$/,/^${END}\$/d" ${CONF}
+
+# Reloads the configuration, closing the connections to the removed share
+case $LINUX_KIND in
+ubuntu | debian)
+  systemctl reload smbd || systemctl restart smbd || exit 194
+  ;;
+*)
+  systemctl reload smb || systemctl restart smb || exit 194
+  ;;
+esac
+smbcontrol all close-share "{{ .Name }}" &>/dev/null || true
+exit 0
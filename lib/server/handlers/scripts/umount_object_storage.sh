@@ -14,11 +14,23 @@
 # See the License for the specific language governing permissions and
 # limitations under the License.
 
-/usr/local/bin/umount-{{.Bucket}}
-echo "umount: $?" >/tmp/umount.log
-rm /etc/s3ql/auth.{{.Bucket}}
-echo "rm auth: $?" >>/tmp/umount.log
-rm /usr/local/bin/mount-{{.Bucket}}
-echo "rm mount: $?" >>/tmp/umount.log
-rm /usr/local/bin/umount-{{.Bucket}}
-echo "rm umount: $?" >>/tmp/umount.log
+BUCKET="{{ .Bucket }}"
+UNIT=safescale-bucket-${BUCKET}
+
+if [ -f /etc/systemd/system/${UNIT}.service ]; then
+  systemctl disable ${UNIT} &>/dev/null
+  systemctl stop ${UNIT} || exit 192
+  rm -f /etc/systemd/system/${UNIT}.service /etc/safescale/buckets/${BUCKET}.conf
+  systemctl daemon-reload
+  exit 0
+fi
+
+# Bucket mounted with s3ql by previous releases
+if [ -x /usr/local/bin/umount-${BUCKET} ]; then
+  /usr/local/bin/umount-${BUCKET} || exit 192
+  rm -f /etc/s3ql/auth.${BUCKET} /usr/local/bin/mount-${BUCKET} /usr/local/bin/umount-${BUCKET}
+  exit 0
+fi
+
+echo "Bucket '${BUCKET}' is not mounted" >&2
+exit 194
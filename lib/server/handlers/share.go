@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"sort"
 	"strings"
 
 	uuid "github.com/satori/go.uuid"
@@ -30,10 +31,11 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
 	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/server/metadata"
-	"github.com/CS-SI/SafeScale/lib/system/nfs"
+	"github.com/CS-SI/SafeScale/lib/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
 )
 
 //go:generate mockgen -destination=../mocks/mock_nasapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers ShareAPI
@@ -42,13 +44,15 @@ import (
 
 // ShareAPI defines API to manipulate Shares
 type ShareAPI interface {
-	Create(context.Context, string, string, string, []string, bool, bool, bool, bool, bool, bool, bool) (*propsv1.HostShare, error)
+	Create(context.Context, string, string, string, string, []string, map[string]bool, bool, bool, bool, bool, bool, bool, bool) (*propsv1.HostShare, error)
 	ForceInspect(context.Context, string) (*resources.Host, *propsv1.HostShare, map[string]*propsv1.HostRemoteMount, error)
 	Inspect(context.Context, string) (*resources.Host, *propsv1.HostShare, map[string]*propsv1.HostRemoteMount, error)
 	Delete(context.Context, string) error
 	List(context.Context) (map[string]map[string]*propsv1.HostShare, error)
 	Mount(context.Context, string, string, string, bool) (*propsv1.HostRemoteMount, error)
 	Unmount(context.Context, string, string) error
+	AddACL(context.Context, string, map[string]bool) error
+	RemoveACL(context.Context, string, []string) error
 }
 
 // ShareHandler nas service
@@ -71,10 +75,12 @@ func sanitize(in string) (string, error) {
 	return sanitized, nil
 }
 
-// Create a share on host
+// Create a share on host, exported with the protocol corresponding to shareType (nfs by default)
+// If acls isn't empty, only the hosts it contains (indexed by name or ID, with true for read-only access) are
+// allowed to mount the share
 func (handler *ShareHandler) Create(
 	ctx context.Context,
-	shareName, hostName, path string, securityModes []string,
+	shareName, hostName, path, shareType string, securityModes []string, acls map[string]bool,
 	readOnly, rootSquash, secure, async, noHide, crossMount, subtreeCheck bool,
 ) (share *propsv1.HostShare, err error) {
 	if handler == nil {
@@ -88,6 +94,22 @@ func (handler *ShareHandler) Create(
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%s)", shareName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "ShareHandler.Create")
+	defer tracing.EndSpan(span, &err)
+	handler = &ShareHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	if shareType == "" {
+		shareType = shareTypeNFS
+	}
+	shareType = strings.ToLower(shareType)
+	protocol, err := getShareProtocol(handler.service, shareType)
+	if err != nil {
+		return nil, err
+	}
+	securityModes, err = protocol.SecurityModes(securityModes)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check if a share already exists with the same name
 	server, _, _, err := handler.Inspect(ctx, shareName)
@@ -130,40 +152,46 @@ func (handler *ShareHandler) Create(
 		return nil, err
 	}
 
-	// Installs NFS Server software if needed
-	sshHandler := NewSSHHandler(handler.service)
-	sshConfig, err := sshHandler.GetConfig(ctx, server)
-	if err != nil {
-		return nil, err
-	}
-	nfsServer, err := nfs.NewServer(sshConfig)
+	share = propsv1.NewHostShare()
+	share.Name = shareName
+	shareID, err := uuid.NewV4()
 	if err != nil {
-		return nil, err
+		return nil, scerr.Wrap(err, "Error creating UUID for share")
+	}
+	share.ID = shareID.String()
+	share.Path = sharePath
+	share.Type = shareType
+	share.SecurityModes = securityModes
+	share.ShareOptions = shareExportOptions{
+		ReadOnly:     readOnly,
+		RootSquash:   rootSquash,
+		Secure:       secure,
+		Async:        async,
+		NoHide:       noHide,
+		CrossMount:   crossMount,
+		SubtreeCheck: subtreeCheck,
+	}.String()
+	share.ACLs = map[string]*propsv1.HostShareACL{}
+	for k, v := range acls {
+		acl, err := handler.newShareACL(ctx, k, v)
+		if err != nil {
+			return nil, err
+		}
+		share.ACLs[acl.HostID] = acl
 	}
 
+	// Exports the path, installing the software needed if it's the first share of its kind on the host
 	err = server.Properties.LockForRead(hostproperty.SharesV1).ThenUse(func(clonable data.Clonable) error {
-		serverSharesV1 := clonable.(*propsv1.HostShares)
-		if len(serverSharesV1.ByID) == 0 {
-			// Host doesn't have shares yet, so install NFS
-			err = nfsServer.Install()
-			if err != nil {
-				return err
-			}
-		}
-		return nil
+		return protocol.Export(ctx, server, clonable.(*propsv1.HostShares), share)
 	})
 	if err != nil {
 		return nil, err
 	}
-	err = nfsServer.AddShare(sharePath, securityModes, readOnly, rootSquash, secure, async, noHide, crossMount, subtreeCheck)
-	if err != nil {
-		return nil, err
-	}
 	defer func() {
 		if err != nil {
-			err2 := nfsServer.RemoveShare(sharePath)
+			err2 := protocol.Unexport(ctx, server, share)
 			if err2 != nil {
-				log.Warn("failed to RemoveShare")
+				log.Warn("failed to remove share export")
 				err = scerr.AddConsequence(err, err2)
 			}
 		}
@@ -172,17 +200,6 @@ func (handler *ShareHandler) Create(
 	// Updates Host property propsv1.HostShares
 	err = server.Properties.LockForWrite(hostproperty.SharesV1).ThenUse(func(clonable data.Clonable) error {
 		serverSharesV1 := clonable.(*propsv1.HostShares)
-
-		share = propsv1.NewHostShare()
-		share.Name = shareName
-		shareID, err := uuid.NewV4()
-		if err != nil {
-			return scerr.Wrap(err, "Error creating UUID for share")
-		}
-		share.ID = shareID.String()
-		share.Path = sharePath
-		share.Type = "nfs"
-
 		serverSharesV1.ByID[share.ID] = share
 		serverSharesV1.ByName[share.Name] = share.ID
 
@@ -254,6 +271,9 @@ func (handler *ShareHandler) Delete(ctx context.Context, name string) (err error
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%s)", name), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "ShareHandler.Delete")
+	defer tracing.EndSpan(span, &err)
+	handler = &ShareHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	// Retrieve info about the share
 	server, share, _, err := handler.ForceInspect(ctx, name)
@@ -277,17 +297,11 @@ func (handler *ShareHandler) Delete(ctx context.Context, name string) (err error
 			return fmt.Errorf("still used by: %s", strings.Join(list, ","))
 		}
 
-		sshHandler := NewSSHHandler(handler.service)
-		sshConfig, err := sshHandler.GetConfig(ctx, server.ID)
-		if err != nil {
-			return err
-		}
-
-		nfsServer, err := nfs.NewServer(sshConfig)
+		protocol, err := getShareProtocol(handler.service, share.Type)
 		if err != nil {
 			return err
 		}
-		err = nfsServer.RemoveShare(share.Path)
+		err = protocol.Unexport(ctx, server, share)
 		if err != nil {
 			return err
 		}
@@ -315,7 +329,12 @@ func (handler *ShareHandler) Delete(ctx context.Context, name string) (err error
 	select {
 	case <-ctx.Done():
 		log.Warnf("Share deletion cancelled by user")
-		_, err = handler.Create(context.Background(), share.Name, server.Name, share.Path, []string{}, false, false, false, false, false, false, false)
+		acls := map[string]bool{}
+		for k, v := range share.ACLs {
+			acls[k] = v.ReadOnly
+		}
+		options := parseShareExportOptions(share.ShareOptions)
+		_, err = handler.Create(context.Background(), share.Name, server.Name, share.Path, share.Type, share.SecurityModes, acls, options.ReadOnly, options.RootSquash, options.Secure, options.Async, options.NoHide, options.CrossMount, options.SubtreeCheck)
 		if err != nil {
 			return fmt.Errorf("failed to stop share deletion")
 		}
@@ -338,6 +357,9 @@ func (handler *ShareHandler) List(ctx context.Context) (props map[string]map[str
 	tracer := concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "ShareHandler.List")
+	defer tracing.EndSpan(span, &err)
+	handler = &ShareHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	shares := map[string]map[string]*propsv1.HostShare{}
 
@@ -406,6 +428,9 @@ func (handler *ShareHandler) Mount(
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", shareName, hostName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "ShareHandler.Mount")
+	defer tracing.EndSpan(span, &err)
+	handler = &ShareHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	// Retrieve info about the share
 	server, share, _, err := handler.Inspect(ctx, shareName)
@@ -436,6 +461,13 @@ func (handler *ShareHandler) Mount(
 		}
 	}
 
+	// Check if the host is allowed to mount the share
+	if len(share.ACLs) > 0 {
+		if _, ok := share.ACLs[target.ID]; !ok {
+			return nil, scerr.InvalidRequestError(fmt.Sprintf("host '%s' is not allowed to mount share '%s'", target.Name, share.Name))
+		}
+	}
+
 	// Check if share is already mounted
 	// Check if there is already volume mounted in the path (or in subpath)
 	err = target.Properties.LockForRead(hostproperty.MountsV1).ThenUse(func(clonable data.Clonable) error {
@@ -462,15 +494,15 @@ func (handler *ShareHandler) Mount(
 		return nil, err
 	}
 
-	export := ""
+	address := ""
 	err = target.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
 		if clonable.(*propsv1.HostNetwork).DefaultGatewayPrivateIP == server.GetPrivateIP() {
-			export = server.GetPrivateIP() + ":" + share.Path
+			address = server.GetPrivateIP()
 		} else {
 			if server.GetPrivateIP() != "" {
-				export = server.GetPrivateIP() + ":" + share.Path
+				address = server.GetPrivateIP()
 			} else {
-				export = server.GetAccessIP() + ":" + share.Path
+				address = server.GetAccessIP()
 			}
 		}
 		return nil
@@ -479,13 +511,13 @@ func (handler *ShareHandler) Mount(
 		return nil, err
 	}
 
-	sshHandler := NewSSHHandler(handler.service)
-	sshConfig, err := sshHandler.GetConfig(ctx, target)
+	protocol, err := getShareProtocol(handler.service, share.Type)
 	if err != nil {
 		return nil, err
 	}
 
 	// Mount the share on host
+	export := ""
 	err = server.Properties.LockForWrite(hostproperty.SharesV1).ThenUse(func(clonable data.Clonable) error {
 		serverSharesV1 := clonable.(*propsv1.HostShares)
 		_, found := serverSharesV1.ByID[serverSharesV1.ByName[shareName]]
@@ -494,16 +526,8 @@ func (handler *ShareHandler) Mount(
 		}
 		shareID := serverSharesV1.ByName[shareName]
 
-		nfsClient, err := nfs.NewNFSClient(sshConfig)
-		if err != nil {
-			return err
-		}
-		err = nfsClient.Install()
-		if err != nil {
-			return err
-		}
-
-		err = nfsClient.Mount(export, mountPath, withCache)
+		var err error
+		export, err = protocol.Mount(ctx, target, share, address, mountPath, withCache)
 		if err != nil {
 			return err
 		}
@@ -517,23 +541,7 @@ func (handler *ShareHandler) Mount(
 	}
 	defer func() {
 		if err != nil {
-			sshHandler := NewSSHHandler(handler.service)
-			sshConfig, derr := sshHandler.GetConfig(ctx, target)
-			if derr != nil {
-				err = scerr.AddConsequence(err, derr)
-			}
-
-			nfsClient, derr := nfs.NewNFSClient(sshConfig)
-			if derr != nil {
-				err = scerr.AddConsequence(err, derr)
-			}
-
-			derr = nfsClient.Install()
-			if derr != nil {
-				err = scerr.AddConsequence(err, derr)
-			}
-
-			derr = nfsClient.Unmount(export)
+			derr := protocol.Unmount(ctx, target, share, export)
 			if derr != nil {
 				err = scerr.AddConsequence(err, derr)
 			}
@@ -550,7 +558,7 @@ func (handler *ShareHandler) Mount(
 		mount.ShareID = share.ID
 		mount.Export = export
 		mount.Path = mountPath
-		mount.FileSystem = "nfs"
+		mount.FileSystem = protocol.FileSystem()
 		targetMountsV1.RemoteMountsByPath[mount.Path] = mount
 		targetMountsV1.RemoteMountsByShareID[mount.ShareID] = mount.Path
 		targetMountsV1.RemoteMountsByExport[mount.Export] = mount.Path
@@ -637,6 +645,9 @@ func (handler *ShareHandler) Unmount(ctx context.Context, shareName, hostName st
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", shareName, hostName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "ShareHandler.Unmount")
+	defer tracing.EndSpan(span, &err)
+	handler = &ShareHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	server, share, _, err := handler.ForceInspect(ctx, shareName)
 	if err != nil {
@@ -670,6 +681,11 @@ func (handler *ShareHandler) Unmount(ctx context.Context, shareName, hostName st
 		}
 	}
 
+	protocol, err := getShareProtocol(handler.service, share.Type)
+	if err != nil {
+		return err
+	}
+
 	var mountPath string
 	err = target.Properties.LockForWrite(hostproperty.MountsV1).ThenUse(func(clonable data.Clonable) error {
 		targetMountsV1 := clonable.(*propsv1.HostMounts)
@@ -678,17 +694,12 @@ func (handler *ShareHandler) Unmount(ctx context.Context, shareName, hostName st
 			return fmt.Errorf("not mounted on host '%s'", target.Name)
 		}
 
-		// Unmount share from client
-		sshHandler := NewSSHHandler(handler.service)
-		sshConfig, err := sshHandler.GetConfig(ctx, target.ID)
-		if err != nil {
-			return err
+		// Unmount share from client, using the export recorded when mounted
+		export := mount.Export
+		if export == "" {
+			export = utils.BracketIPv6(server.GetAccessIP()) + ":" + share.Path
 		}
-		nfsClient, err := nfs.NewNFSClient(sshConfig)
-		if err != nil {
-			return err
-		}
-		err = nfsClient.Unmount(server.GetAccessIP() + ":" + share.Path)
+		err := protocol.Unmount(ctx, target, share, export)
 		if err != nil {
 			return err
 		}
@@ -697,6 +708,7 @@ func (handler *ShareHandler) Unmount(ctx context.Context, shareName, hostName st
 		mountPath = mount.Path
 		delete(targetMountsV1.RemoteMountsByShareID, mount.ShareID)
 		delete(targetMountsV1.RemoteMountsByPath, mountPath)
+		delete(targetMountsV1.RemoteMountsByExport, mount.Export)
 		return nil
 	})
 	if err != nil {
@@ -740,6 +752,155 @@ func (handler *ShareHandler) Unmount(ctx context.Context, shareName, hostName st
 	return nil
 }
 
+// AddACL allows the hosts in acls (indexed by name or ID, with true for read-only access) to mount the share,
+// updating the access of the hosts already allowed
+// If the share was exported to every host, it becomes restricted to these hosts.
+func (handler *ShareHandler) AddACL(ctx context.Context, shareName string, acls map[string]bool) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return scerr.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if len(acls) == 0 {
+		return scerr.InvalidParameterError("acls", "cannot be empty")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", shareName), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "ShareHandler.AddACL")
+	defer tracing.EndSpan(span, &err)
+	handler = &ShareHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	var newACLs []*propsv1.HostShareACL
+	for k, v := range acls {
+		acl, err := handler.newShareACL(ctx, k, v)
+		if err != nil {
+			return err
+		}
+		newACLs = append(newACLs, acl)
+	}
+
+	return handler.updateACLs(ctx, shareName, func(share *propsv1.HostShare) error {
+		if share.ACLs == nil {
+			share.ACLs = map[string]*propsv1.HostShareACL{}
+		}
+		for _, acl := range newACLs {
+			share.ACLs[acl.HostID] = acl
+		}
+		return nil
+	})
+}
+
+// RemoveACL forbids the hosts (by name or ID) to mount the share
+// The hosts must not mount the share, and the share must keep at least one host allowed.
+func (handler *ShareHandler) RemoveACL(ctx context.Context, shareName string, hosts []string) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return scerr.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if len(hosts) == 0 {
+		return scerr.InvalidParameterError("hosts", "cannot be empty")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", shareName), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "ShareHandler.RemoveACL")
+	defer tracing.EndSpan(span, &err)
+	handler = &ShareHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	return handler.updateACLs(ctx, shareName, func(share *propsv1.HostShare) error {
+		for _, ref := range hosts {
+			found := false
+			for id, acl := range share.ACLs {
+				if id == ref || acl.HostName == ref {
+					delete(share.ACLs, id)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return resources.ResourceNotFoundError("ACL of host", ref)
+			}
+		}
+		if len(share.ACLs) == 0 {
+			return scerr.InvalidRequestError(fmt.Sprintf("cannot remove the last host allowed to mount share '%s'", share.Name))
+		}
+		return nil
+	})
+}
+
+// updateACLs applies on the share the changes of ACLs made by alter, then re-exports the share and saves the metadata
+// The hosts mounting the share must keep their access.
+func (handler *ShareHandler) updateACLs(ctx context.Context, shareName string, alter func(*propsv1.HostShare) error) error {
+	server, share, _, err := handler.ForceInspect(ctx, shareName)
+	if err != nil {
+		return err
+	}
+	protocol, err := getShareProtocol(handler.service, share.Type)
+	if err != nil {
+		return err
+	}
+
+	err = server.Properties.LockForWrite(hostproperty.SharesV1).ThenUse(func(clonable data.Clonable) error {
+		serverSharesV1 := clonable.(*propsv1.HostShares)
+		current, ok := serverSharesV1.ByID[share.ID]
+		if !ok {
+			return fmt.Errorf("failed to find metadata about share '%s'", shareName)
+		}
+		updated := current.Clone().(*propsv1.HostShare)
+		err := alter(updated)
+		if err != nil {
+			return err
+		}
+		var lost []string
+		for id, name := range updated.ClientsByID {
+			if _, ok := updated.ACLs[id]; !ok {
+				lost = append(lost, "'"+name+"'")
+			}
+		}
+		if len(lost) > 0 {
+			sort.Strings(lost)
+			return scerr.InvalidRequestError(fmt.Sprintf("share '%s' is mounted by hosts that wouldn't be allowed anymore: %s", shareName, strings.Join(lost, ",")))
+		}
+
+		err = protocol.UpdateACLs(ctx, server, updated)
+		if err != nil {
+			return err
+		}
+		serverSharesV1.ByID[share.ID] = updated
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = metadata.SaveHost(handler.service, server)
+	return err
+}
+
+// newShareACL returns the ACL allowing the host 'ref' to mount a share
+func (handler *ShareHandler) newShareACL(ctx context.Context, ref string, readOnly bool) (*propsv1.HostShareACL, error) {
+	host, err := NewHostHandler(handler.service).Inspect(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	ip := host.GetPrivateIP()
+	if ip == "" {
+		return nil, fmt.Errorf("failed to find the private IP address of host '%s'", host.Name)
+	}
+	return &propsv1.HostShareACL{
+		HostID:   host.ID,
+		HostName: host.Name,
+		IP:       ip,
+		ReadOnly: readOnly,
+	}, nil
+}
+
 // ForceInspect returns the host and share corresponding to 'shareName'
 func (handler *ShareHandler) ForceInspect(
 	ctx context.Context,
@@ -757,6 +918,9 @@ func (handler *ShareHandler) ForceInspect(
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%s)", shareName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "ShareHandler.ForceInspect")
+	defer tracing.EndSpan(span, &err)
+	handler = &ShareHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	host, share, mounts, err := handler.Inspect(ctx, shareName)
 	if err != nil {
@@ -786,6 +950,9 @@ func (handler *ShareHandler) Inspect(
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%s)", shareName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "ShareHandler.Inspect")
+	defer tracing.EndSpan(span, &err)
+	handler = &ShareHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	hostName, err := metadata.LoadShare(handler.service, shareName)
 	if err != nil {
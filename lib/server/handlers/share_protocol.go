@@ -0,0 +1,500 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/system"
+	"github.com/CS-SI/SafeScale/lib/system/nfs"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+const (
+	// shareTypeNFS is the default share protocol, NFS with the security modes asked (sys by default)
+	shareTypeNFS = "nfs"
+	// shareTypeNFS4Krb5 is NFSv4 restricted to Kerberos security modes (krb5 by default)
+	shareTypeNFS4Krb5 = "nfs4-krb5"
+	// shareTypeSMB is SMB/CIFS, served by Samba
+	shareTypeSMB = "smb"
+)
+
+// shareExportOptions contains the options of an export; protocols ignore the ones they don't support
+type shareExportOptions struct {
+	ReadOnly     bool
+	RootSquash   bool
+	Secure       bool
+	Async        bool
+	NoHide       bool
+	CrossMount   bool
+	SubtreeCheck bool
+}
+
+// String returns the options set, in the format stored in HostShare.ShareOptions
+func (o shareExportOptions) String() string {
+	var list []string
+	for _, v := range []struct {
+		set  bool
+		name string
+	}{
+		{o.ReadOnly, "ro"},
+		{o.RootSquash, "root_squash"},
+		{o.Secure, "secure"},
+		{o.Async, "async"},
+		{o.NoHide, "nohide"},
+		{o.CrossMount, "crossmnt"},
+		{o.SubtreeCheck, "subtree_check"},
+	} {
+		if v.set {
+			list = append(list, v.name)
+		}
+	}
+	return strings.Join(list, ",")
+}
+
+// parseShareExportOptions returns the shareExportOptions corresponding to the content of HostShare.ShareOptions
+func parseShareExportOptions(in string) shareExportOptions {
+	var o shareExportOptions
+	for _, v := range strings.Split(in, ",") {
+		switch strings.TrimSpace(v) {
+		case "ro":
+			o.ReadOnly = true
+		case "root_squash":
+			o.RootSquash = true
+		case "secure":
+			o.Secure = true
+		case "async":
+			o.Async = true
+		case "nohide":
+			o.NoHide = true
+		case "crossmnt":
+			o.CrossMount = true
+		case "subtree_check":
+			o.SubtreeCheck = true
+		}
+	}
+	return o
+}
+
+// shareProtocol is the interface to implement to export a share of a host and mount it on other hosts
+type shareProtocol interface {
+	// SecurityModes validates the security modes requested, and returns the ones to use
+	SecurityModes([]string) ([]string, error)
+	// Export exports the share from server, to the hosts of its ACLs if any; shares contains the shares
+	// already exported by server
+	Export(ctx context.Context, server *resources.Host, shares *propsv1.HostShares, share *propsv1.HostShare) error
+	// UpdateACLs applies the ACLs of the share already exported by server, without disturbing the clients keeping access
+	UpdateACLs(ctx context.Context, server *resources.Host, share *propsv1.HostShare) error
+	// Unexport stops the export of the share from server
+	Unexport(ctx context.Context, server *resources.Host, share *propsv1.HostShare) error
+	// Mount mounts the share exported by address in mountPath of target, and returns the export mounted
+	Mount(ctx context.Context, target *resources.Host, share *propsv1.HostShare, address, mountPath string, withCache bool) (string, error)
+	// Unmount unmounts export from target
+	Unmount(ctx context.Context, target *resources.Host, share *propsv1.HostShare, export string) error
+	// FileSystem returns the type of file system of the mounts
+	FileSystem() string
+}
+
+// getShareProtocol returns the shareProtocol corresponding to shareType
+func getShareProtocol(svc iaas.Service, shareType string) (shareProtocol, error) {
+	switch strings.ToLower(shareType) {
+	case "", shareTypeNFS:
+		return &nfsShareProtocol{service: svc}, nil
+	case shareTypeNFS4Krb5:
+		return &nfsShareProtocol{service: svc, kerberos: true}, nil
+	case shareTypeSMB:
+		return &smbShareProtocol{service: svc}, nil
+	default:
+		return nil, scerr.InvalidParameterError("type", fmt.Sprintf("unsupported share type '%s' (valid ones are '%s', '%s' and '%s')", shareType, shareTypeNFS, shareTypeNFS4Krb5, shareTypeSMB))
+	}
+}
+
+// isValidSecurityMode tells if mode is one of the security modes known
+func isValidSecurityMode(mode string) bool {
+	switch mode {
+	case "sys", "krb5", "krb5i", "krb5p":
+		return true
+	}
+	return false
+}
+
+// nfsShareProtocol exports shares with NFS, optionally restricted to NFSv4 with Kerberos
+type nfsShareProtocol struct {
+	service  iaas.Service
+	kerberos bool
+}
+
+// SecurityModes ...
+func (p *nfsShareProtocol) SecurityModes(modes []string) ([]string, error) {
+	if p.kerberos && len(modes) == 0 {
+		return []string{"krb5"}, nil
+	}
+	for _, m := range modes {
+		if !isValidSecurityMode(m) {
+			return nil, scerr.InvalidParameterError("securityModes", fmt.Sprintf("'%s' is not a valid security mode", m))
+		}
+		if p.kerberos && m == "sys" {
+			return nil, scerr.InvalidParameterError("securityModes", fmt.Sprintf("security mode 'sys' is not allowed for share type '%s'", shareTypeNFS4Krb5))
+		}
+	}
+	return modes, nil
+}
+
+// exportACLs returns the NFS ACLs of share: one per host allowed, or one for every host if the share has no ACL
+func (p *nfsShareProtocol) exportACLs(share *propsv1.HostShare) ([]nfs.ExportACL, error) {
+	options := parseShareExportOptions(share.ShareOptions)
+	if len(share.ACLs) == 0 {
+		acl, err := nfs.NewExportACL("*", share.SecurityModes, options.ReadOnly, options.RootSquash, options.Secure, options.Async, options.NoHide, options.CrossMount, options.SubtreeCheck)
+		if err != nil {
+			return nil, err
+		}
+		return []nfs.ExportACL{acl}, nil
+	}
+
+	// Sorts the hosts to keep /etc/exports stable
+	ids := make([]string, 0, len(share.ACLs))
+	for k := range share.ACLs {
+		ids = append(ids, k)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return share.ACLs[ids[i]].HostName < share.ACLs[ids[j]].HostName
+	})
+	acls := make([]nfs.ExportACL, 0, len(ids))
+	for _, id := range ids {
+		item := share.ACLs[id]
+		acl, err := nfs.NewExportACL(item.IP, share.SecurityModes, item.ReadOnly, options.RootSquash, options.Secure, options.Async, options.NoHide, options.CrossMount, options.SubtreeCheck)
+		if err != nil {
+			return nil, err
+		}
+		acls = append(acls, acl)
+	}
+	return acls, nil
+}
+
+// Export ...
+func (p *nfsShareProtocol) Export(
+	ctx context.Context,
+	server *resources.Host, shares *propsv1.HostShares, share *propsv1.HostShare,
+) error {
+
+	acls, err := p.exportACLs(share)
+	if err != nil {
+		return err
+	}
+
+	sshConfig, err := NewSSHHandler(p.service).GetConfig(ctx, server)
+	if err != nil {
+		return err
+	}
+	nfsServer, err := nfs.NewServer(sshConfig)
+	if err != nil {
+		return err
+	}
+
+	installed, kerberized := false, false
+	for _, s := range shares.ByID {
+		switch s.Type {
+		case "", shareTypeNFS:
+			installed = true
+		case shareTypeNFS4Krb5:
+			installed, kerberized = true, true
+		}
+	}
+	if !installed {
+		// Host doesn't have NFS shares yet, so install NFS
+		err = nfsServer.Install()
+		if err != nil {
+			return err
+		}
+	}
+	if p.kerberos && !kerberized {
+		err = nfsServer.EnableKerberos()
+		if err != nil {
+			return err
+		}
+	}
+	return nfsServer.AddShareWithACLs(share.Path, acls)
+}
+
+// UpdateACLs ...
+func (p *nfsShareProtocol) UpdateACLs(ctx context.Context, server *resources.Host, share *propsv1.HostShare) error {
+	acls, err := p.exportACLs(share)
+	if err != nil {
+		return err
+	}
+	sshConfig, err := NewSSHHandler(p.service).GetConfig(ctx, server)
+	if err != nil {
+		return err
+	}
+	nfsServer, err := nfs.NewServer(sshConfig)
+	if err != nil {
+		return err
+	}
+	return nfsServer.UpdateShare(share.Path, acls)
+}
+
+// Unexport ...
+func (p *nfsShareProtocol) Unexport(ctx context.Context, server *resources.Host, share *propsv1.HostShare) error {
+	sshConfig, err := NewSSHHandler(p.service).GetConfig(ctx, server)
+	if err != nil {
+		return err
+	}
+	nfsServer, err := nfs.NewServer(sshConfig)
+	if err != nil {
+		return err
+	}
+	return nfsServer.RemoveShare(share.Path)
+}
+
+// Mount ...
+func (p *nfsShareProtocol) Mount(
+	ctx context.Context,
+	target *resources.Host, share *propsv1.HostShare, address, mountPath string,
+	withCache bool,
+) (string, error) {
+
+	sshConfig, err := NewSSHHandler(p.service).GetConfig(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	nfsClient, err := nfs.NewNFSClient(sshConfig)
+	if err != nil {
+		return "", err
+	}
+	err = nfsClient.Install()
+	if err != nil {
+		return "", err
+	}
+
+	export := utils.BracketIPv6(address) + ":" + share.Path
+	options := ""
+	if p.kerberos {
+		err = nfsClient.EnableKerberos()
+		if err != nil {
+			return "", err
+		}
+		// Mounts with the first security mode exported, the server rejecting the mount otherwise
+		options = "vers=4.2,sec=" + share.SecurityModes[0]
+	}
+	return export, nfsClient.MountWithOptions(export, mountPath, withCache, options)
+}
+
+// Unmount ...
+func (p *nfsShareProtocol) Unmount(ctx context.Context, target *resources.Host, share *propsv1.HostShare, export string) error {
+	sshConfig, err := NewSSHHandler(p.service).GetConfig(ctx, target)
+	if err != nil {
+		return err
+	}
+	nfsClient, err := nfs.NewNFSClient(sshConfig)
+	if err != nil {
+		return err
+	}
+	return nfsClient.Unmount(export)
+}
+
+// FileSystem ...
+func (p *nfsShareProtocol) FileSystem() string {
+	if p.kerberos {
+		return "nfs4"
+	}
+	return "nfs"
+}
+
+// smbShareUser is the Samba user the hosts mount the shares of security mode 'sys' with
+const smbShareUser = "safescale-smb"
+
+// smbCredentials returns the path of the file containing the credentials used by the clients to mount the share
+func smbCredentials(share *propsv1.HostShare) string {
+	return "/etc/samba/credentials/" + share.ID
+}
+
+// smbShareProtocol exports shares with Samba
+// Security mode 'sys' gives access to the share to the Samba user smbShareUser, whose password is generated by the
+// first export of the host and recorded with the shares; 'none' gives guest access and must be asked for explicitly.
+// Kerberos security modes need the hosts to be members of an Active Directory domain
+type smbShareProtocol struct {
+	service iaas.Service
+}
+
+// smbScriptData contains the data used by the smb_*.sh scripts
+type smbScriptData struct {
+	BashLibrary  string
+	Name         string
+	Path         string
+	ReadOnly     bool
+	RootSquash   bool
+	SecurityMode string
+	User         string
+	Password     string
+	Credentials  string
+	Export       string
+	MountPoint   string
+	Options      string
+	WithCache    bool
+}
+
+// SecurityModes ...
+func (p *smbShareProtocol) SecurityModes(modes []string) ([]string, error) {
+	switch len(modes) {
+	case 0:
+		return []string{"sys"}, nil
+	case 1:
+		if modes[0] != "none" && !isValidSecurityMode(modes[0]) {
+			return nil, scerr.InvalidParameterError("securityModes", fmt.Sprintf("'%s' is not a valid security mode", modes[0]))
+		}
+		return modes, nil
+	default:
+		return nil, scerr.InvalidParameterError("securityModes", fmt.Sprintf("share type '%s' supports only one security mode", shareTypeSMB))
+	}
+}
+
+// Export ...
+func (p *smbShareProtocol) Export(
+	ctx context.Context,
+	server *resources.Host, shares *propsv1.HostShares, share *propsv1.HostShare,
+) error {
+
+	if len(share.ACLs) > 0 {
+		return scerr.NotImplementedError(fmt.Sprintf("per-host ACLs are not supported by share type '%s'", shareTypeSMB))
+	}
+	options := parseShareExportOptions(share.ShareOptions)
+	bashLibrary, err := system.GetBashLibrary()
+	if err != nil {
+		return err
+	}
+	mode := share.SecurityModes[0]
+	if mode == "sys" {
+		// The Samba user is shared by the shares of the host, so its password is kept
+		for _, v := range shares.ByID {
+			if v.Type == shareTypeSMB && v.Password != "" {
+				share.Password = v.Password
+				break
+			}
+		}
+		if share.Password == "" {
+			share.Password, err = utils.GeneratePassword(16)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	scriptData := smbScriptData{
+		BashLibrary:  bashLibrary,
+		Name:         share.Name,
+		Path:         share.Path,
+		ReadOnly:     options.ReadOnly,
+		RootSquash:   options.RootSquash,
+		SecurityMode: mode,
+		User:         smbShareUser,
+		Password:     share.Password,
+	}
+	err = exec(ctx, "smb_server_export.sh", scriptData, server.ID, p.service)
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("failed to export share '%s' with Samba", share.Name))
+	}
+	return nil
+}
+
+// UpdateACLs ...
+func (p *smbShareProtocol) UpdateACLs(ctx context.Context, server *resources.Host, share *propsv1.HostShare) error {
+	return scerr.NotImplementedError(fmt.Sprintf("per-host ACLs are not supported by share type '%s'", shareTypeSMB))
+}
+
+// Unexport ...
+func (p *smbShareProtocol) Unexport(ctx context.Context, server *resources.Host, share *propsv1.HostShare) error {
+	bashLibrary, err := system.GetBashLibrary()
+	if err != nil {
+		return err
+	}
+	scriptData := smbScriptData{
+		BashLibrary: bashLibrary,
+		Name:        share.Name,
+	}
+	err = exec(ctx, "smb_server_unexport.sh", scriptData, server.ID, p.service)
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("failed to stop the export of share '%s' by Samba", share.Name))
+	}
+	return nil
+}
+
+// Mount ...
+func (p *smbShareProtocol) Mount(
+	ctx context.Context,
+	target *resources.Host, share *propsv1.HostShare, address, mountPath string,
+	withCache bool,
+) (string, error) {
+
+	bashLibrary, err := system.GetBashLibrary()
+	if err != nil {
+		return "", err
+	}
+	mode := share.SecurityModes[0]
+	credentials := smbCredentials(share)
+	var options string
+	switch mode {
+	case "none":
+		options = "guest,vers=3.0"
+	case "sys":
+		options = "credentials=" + credentials + ",vers=3.0"
+	case "krb5p":
+		options = "sec=krb5i,seal,vers=3.0"
+	default:
+		options = "sec=" + mode + ",vers=3.0"
+	}
+	export := "//" + address + "/" + share.Name
+	scriptData := smbScriptData{
+		BashLibrary:  bashLibrary,
+		SecurityMode: mode,
+		User:         smbShareUser,
+		Password:     share.Password,
+		Credentials:  credentials,
+		Export:       export,
+		MountPoint:   mountPath,
+		Options:      options,
+		WithCache:    withCache,
+	}
+	err = exec(ctx, "smb_client_mount.sh", scriptData, target.ID, p.service)
+	if err != nil {
+		return "", scerr.Wrap(err, fmt.Sprintf("failed to mount share '%s' on host '%s'", share.Name, target.Name))
+	}
+	return export, nil
+}
+
+// Unmount ...
+func (p *smbShareProtocol) Unmount(ctx context.Context, target *resources.Host, share *propsv1.HostShare, export string) error {
+	scriptData := smbScriptData{
+		Credentials: smbCredentials(share),
+		Export:      export,
+	}
+	err := exec(ctx, "smb_client_unmount.sh", scriptData, target.ID, p.service)
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("failed to unmount share '%s' from host '%s'", share.Name, target.Name))
+	}
+	return nil
+}
+
+// FileSystem ...
+func (p *smbShareProtocol) FileSystem() string {
+	return "cifs"
+}
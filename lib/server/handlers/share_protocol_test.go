@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareExportOptions(t *testing.T) {
+	assert.Equal(t, "", shareExportOptions{}.String())
+	assert.Equal(t, shareExportOptions{}, parseShareExportOptions(""))
+
+	options := shareExportOptions{ReadOnly: true, Async: true, CrossMount: true}
+	assert.Equal(t, "ro,async,crossmnt", options.String())
+	assert.Equal(t, options, parseShareExportOptions(options.String()))
+
+	all := shareExportOptions{true, true, true, true, true, true, true}
+	assert.Equal(t, all, parseShareExportOptions(all.String()))
+}
+
+func TestShareProtocolSecurityModes(t *testing.T) {
+	nfsProtocol, err := getShareProtocol(nil, "")
+	require.Nil(t, err)
+	modes, err := nfsProtocol.SecurityModes(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, modes)
+	modes, err = nfsProtocol.SecurityModes([]string{"sys", "krb5p"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sys", "krb5p"}, modes)
+	_, err = nfsProtocol.SecurityModes([]string{"none"})
+	assert.NotNil(t, err)
+
+	krb5Protocol, err := getShareProtocol(nil, "NFS4-KRB5")
+	require.Nil(t, err)
+	modes, err = krb5Protocol.SecurityModes(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"krb5"}, modes)
+	_, err = krb5Protocol.SecurityModes([]string{"krb5", "sys"})
+	assert.NotNil(t, err)
+
+	smbProtocol, err := getShareProtocol(nil, "smb")
+	require.Nil(t, err)
+	modes, err = smbProtocol.SecurityModes(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sys"}, modes)
+	modes, err = smbProtocol.SecurityModes([]string{"none"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"none"}, modes)
+	_, err = smbProtocol.SecurityModes([]string{"krb5", "krb5i"})
+	assert.NotNil(t, err)
+
+	_, err = getShareProtocol(nil, "glusterfs")
+	assert.NotNil(t, err)
+}
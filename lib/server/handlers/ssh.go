@@ -37,6 +37,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/retry/enums/verdict"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
 )
 
 const protocolSeparator = ":"
@@ -99,6 +100,9 @@ func (handler *SSHHandler) GetConfig(ctx context.Context, hostParam interface{})
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%s)", hostRef), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "SSHHandler.GetConfig")
+	defer tracing.EndSpan(span, &err)
+	handler = &SSHHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	cfg, err := handler.service.GetConfigurationOptions()
 	if err != nil {
@@ -157,6 +161,9 @@ func (handler *SSHHandler) WaitServerReady(ctx context.Context, hostParam interf
 	tracer := concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "SSHHandler.WaitServerReady")
+	defer tracing.EndSpan(span, &err)
+	handler = &SSHHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	sshSvc := NewSSHHandler(handler.service)
 	ssh, err := sshSvc.GetConfig(ctx, hostParam)
@@ -177,6 +184,9 @@ func (handler *SSHHandler) Run(ctx context.Context, hostName, cmd string, outs o
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', <command>)", hostName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "SSHHandler.Run")
+	defer tracing.EndSpan(span, &err)
+	handler = &SSHHandler{service: iaas.WithContext(ctx, handler.service)}
 	tracer.Trace(fmt.Sprintf("<command>=[%s]", cmd))
 
 	hostSvc := NewHostHandler(handler.service)
@@ -193,7 +203,7 @@ func (handler *SSHHandler) Run(ctx context.Context, hostName, cmd string, outs o
 
 	retryErr := retry.WhileUnsuccessfulDelay1SecondWithNotify(
 		func() error {
-			retCode, stdOut, stdErr, err = handler.runWithTimeout(ssh, cmd, outs, temporal.GetHostTimeout())
+			retCode, stdOut, stdErr, err = handler.runWithTimeout(ctx, ssh, cmd, outs, temporal.GetHostTimeout())
 			return err
 		},
 		temporal.GetHostTimeout(),
@@ -220,6 +230,9 @@ func (handler *SSHHandler) RunWithTimeout(ctx context.Context, hostName, cmd str
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', <command>)", hostName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "SSHHandler.RunWithTimeout")
+	defer tracing.EndSpan(span, &err)
+	handler = &SSHHandler{service: iaas.WithContext(ctx, handler.service)}
 	tracer.Trace(fmt.Sprintf("<command>=[%s]", cmd))
 
 	hostSvc := NewHostHandler(handler.service)
@@ -236,7 +249,7 @@ func (handler *SSHHandler) RunWithTimeout(ctx context.Context, hostName, cmd str
 
 	retryErr := retry.WhileUnsuccessfulDelay1SecondWithNotify(
 		func() error {
-			retCode, stdOut, stdErr, err = handler.runWithTimeout(ssh, cmd, outs, timeout)
+			retCode, stdOut, stdErr, err = handler.runWithTimeout(ctx, ssh, cmd, outs, timeout)
 			return err
 		},
 		2*timeout,
@@ -264,13 +277,18 @@ func (handler *SSHHandler) RunWithTimeout(ctx context.Context, hostName, cmd str
 // }
 
 // run executes command on the host
-func (handler *SSHHandler) runWithTimeout(ssh *system.SSHConfig, cmd string, outs outputs.Enum, duration time.Duration) (int, string, string, error) {
+func (handler *SSHHandler) runWithTimeout(ctx context.Context, ssh *system.SSHConfig, cmd string, outs outputs.Enum, duration time.Duration) (int, string, string, error) {
 	// Create the command
 	sshCmd, err := ssh.Command(cmd)
 	if err != nil {
 		return 0, "", "", err
 	}
-	return sshCmd.RunWithTimeout(nil, outs, duration)
+	// Runs the command in a task bound to ctx, to attach its trace to the span of the request
+	task, err := concurrency.NewTaskWithContext(ctx)
+	if err != nil {
+		return 0, "", "", err
+	}
+	return sshCmd.RunWithTimeout(task, outs, duration)
 }
 
 func extracthostName(in string) (string, error) {
@@ -317,6 +335,9 @@ func (handler *SSHHandler) Copy(ctx context.Context, from, to string) (retCode i
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", from, to), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "SSHHandler.Copy")
+	defer tracing.EndSpan(span, &err)
+	handler = &SSHHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	hostName := ""
 	var upload bool
@@ -24,6 +24,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
 )
 
 //go:generate mockgen -destination=../mocks/mock_templateapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers TemplateAPI
@@ -33,6 +34,8 @@ import (
 // TemplateAPI defines API to manipulate hosts
 type TemplateAPI interface {
 	List(ctx context.Context, all bool) ([]resources.HostTemplate, error)
+	Scans(ctx context.Context) (map[string]*resources.StoredCPUInfo, error)
+	Presets(ctx context.Context) ([]iaas.Preset, error)
 }
 
 // TemplateHandler template service
@@ -52,7 +55,42 @@ func (handler *TemplateHandler) List(ctx context.Context, all bool) (tlist []res
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "TemplateHandler.List")
+	defer tracing.EndSpan(span, &err)
+	handler = &TemplateHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	tlist, err = handler.service.ListTemplates(all)
 	return tlist, err
 }
+
+// Scans returns the measurements done by the scanner on the templates, indexed by template ID
+func (handler *TemplateHandler) Scans(ctx context.Context) (scans map[string]*resources.StoredCPUInfo, err error) {
+	tracer := concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "TemplateHandler.Scans")
+	defer tracing.EndSpan(span, &err)
+	handler = &TemplateHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	list, err := handler.service.ListTemplateScans()
+	if err != nil {
+		return nil, err
+	}
+	scans = map[string]*resources.StoredCPUInfo{}
+	for i := range list {
+		scans[list[i].TemplateID] = &list[i]
+	}
+	return scans, nil
+}
+
+// Presets returns the presets defined in the configuration of the tenant
+func (handler *TemplateHandler) Presets(ctx context.Context) (presets []iaas.Preset, err error) {
+	tracer := concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "TemplateHandler.Presets")
+	defer tracing.EndSpan(span, &err)
+	handler = &TemplateHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	return handler.service.ListPresets(), nil
+}
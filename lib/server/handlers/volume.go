@@ -18,10 +18,12 @@ package handlers
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	mapset "github.com/deckarep/golang-set"
 	"github.com/sirupsen/logrus"
@@ -37,11 +39,13 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/retry"
 	"github.com/CS-SI/SafeScale/lib/utils/retry/enums/verdict"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
 )
 
 //go:generate mockgen -destination=../mocks/mock_volumeapi.go -package=mocks github.com/CS-SI/SafeScale/lib/server/handlers VolumeAPI
@@ -51,11 +55,13 @@ type VolumeAPI interface {
 	Delete(ctx context.Context, ref string) error
 	List(ctx context.Context, all bool) ([]resources.Volume, error)
 	Inspect(ctx context.Context, ref string) (*resources.Volume, map[string]*propsv1.HostLocalMount, error)
-	Create(ctx context.Context, name string, size int, speed volumespeed.Enum) (*resources.Volume, error)
-	Attach(ctx context.Context, volume string, host string, path string, format string, doNotFormat bool) (string, error)
+	Create(ctx context.Context, name string, size int, speed volumespeed.Enum, raid string, count int) (*resources.Volume, error)
+	Attach(ctx context.Context, volume string, host string, path string, format string, doNotFormat bool, encrypt bool) (string, error)
 	Detach(ctx context.Context, volume string, host string) error
+	Migrate(ctx context.Context, volume string, host string) error
 	Expand(ctx context.Context, volume string, host string, increment uint32, incrementType string) error
 	Shrink(ctx context.Context, volume string, host string, increment uint32, incrementType string) error
+	GetRAIDHealth(ctx context.Context, volume *resources.Volume) (string, error)
 }
 
 // VolumeHandler volume service
@@ -80,6 +86,9 @@ func (handler *VolumeHandler) List(ctx context.Context, all bool) (volumes []res
 	tracer := concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "VolumeHandler.List")
+	defer tracing.EndSpan(span, &err)
+	handler = &VolumeHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	if all {
 		volumes, err := handler.service.ListVolumes()
@@ -112,6 +121,9 @@ func (handler *VolumeHandler) Delete(ctx context.Context, ref string) (err error
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%s)", ref), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "VolumeHandler.Delete")
+	defer tracing.EndSpan(span, &err)
+	handler = &VolumeHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	mv, err := metadata.LoadVolume(handler.service, ref)
 	if err != nil {
@@ -144,7 +156,15 @@ func (handler *VolumeHandler) Delete(ctx context.Context, ref string) (err error
 		return err
 	}
 
-	err = handler.service.DeleteVolume(volume.ID)
+	raid, members, err := getRAIDDescription(volume)
+	if err != nil {
+		return err
+	}
+	if raid != "" {
+		err = handler.deleteRAIDMembers(members)
+	} else {
+		err = handler.service.DeleteVolume(volume.ID)
+	}
 	if err != nil {
 		switch err.(type) {
 		case scerr.ErrNotFound:
@@ -163,7 +183,12 @@ func (handler *VolumeHandler) Delete(ctx context.Context, ref string) (err error
 	select {
 	case <-ctx.Done():
 		logrus.Warnf("Volume deletion cancelled by user")
-		volumeBis, err := handler.Create(context.Background(), volume.Name, volume.Size, volume.Speed)
+		// A RAID volume is recreated with new (empty) members of the same size
+		size := volume.Size
+		if raid != "" {
+			size = volume.Size / raidDataMembers(raid, len(members))
+		}
+		volumeBis, err := handler.Create(context.Background(), volume.Name, size, volume.Speed, raid, len(members))
 		if err != nil {
 			return fmt.Errorf("failed to stop volume deletion")
 		}
@@ -192,6 +217,9 @@ func (handler *VolumeHandler) Inspect(
 	tracer := concurrency.NewTracer(nil, "('"+ref+"')", true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "VolumeHandler.Inspect")
+	defer tracing.EndSpan(span, &err)
+	handler = &VolumeHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	mv, err := metadata.LoadVolume(handler.service, ref)
 	if err != nil {
@@ -247,17 +275,25 @@ func (handler *VolumeHandler) Inspect(
 	return volume, mounts, nil
 }
 
-// Create a volume
-func (handler *VolumeHandler) Create(ctx context.Context, name string, size int, speed volumespeed.Enum) (volume *resources.Volume, err error) {
+// Create a volume; if raid is set, the volume is a software RAID array of this level made of count volumes of size GB
+func (handler *VolumeHandler) Create(ctx context.Context, name string, size int, speed volumespeed.Enum, raid string, count int) (volume *resources.Volume, err error) {
 	if handler == nil {
 		return nil, scerr.InvalidInstanceError()
 	}
 	// FIXME: validate parameters
 
-	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d, %s)", name, size, speed.String()), true).WithStopwatch().GoingIn()
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d, %s, '%s', %d)", name, size, speed.String(), raid, count), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "VolumeHandler.Create")
+	defer tracing.EndSpan(span, &err)
+	handler = &VolumeHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	return handler.create(ctx, name, size, speed, raid, count, "")
+}
 
+// create creates a volume like Create does, in the availability zone zone if not empty
+func (handler *VolumeHandler) create(ctx context.Context, name string, size int, speed volumespeed.Enum, raid string, count int, zone string) (volume *resources.Volume, err error) {
 	_, err = metadata.LoadVolume(handler.service, name)
 	if err != nil {
 		if _, ok := err.(scerr.ErrNotFound); !ok {
@@ -267,10 +303,15 @@ func (handler *VolumeHandler) Create(ctx context.Context, name string, size int,
 		return nil, scerr.DuplicateError(fmt.Sprintf("volume '%s' already exists", name))
 	}
 
+	if raid != "" {
+		return handler.createRAID(ctx, name, size, speed, raid, count, zone)
+	}
+
 	volume, err = handler.service.CreateVolume(resources.VolumeRequest{
-		Name:  name,
-		Size:  size,
-		Speed: speed,
+		Name:             name,
+		Size:             size,
+		Speed:            speed,
+		AvailabilityZone: zone,
 	})
 	if err != nil {
 		switch err.(type) {
@@ -363,14 +404,17 @@ func (handler *VolumeHandler) isAlreadyMounted(ctx context.Context, hostName str
 }
 
 // Attach a volume to an host
-func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName, path, format string, doNotFormat bool) (_ string, err error) {
+func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName, path, format string, doNotFormat, encrypt bool) (_ string, err error) {
 	if handler == nil {
 		return "", scerr.InvalidInstanceError()
 	}
 	// FIXME: validate parameters
-	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', '%s', '%s', %v)", volumeName, hostName, path, format, doNotFormat), true)
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', '%s', '%s', %v, %v)", volumeName, hostName, path, format, doNotFormat, encrypt), true)
 	defer tracer.WithStopwatch().GoingIn().OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "VolumeHandler.Attach")
+	defer tracing.EndSpan(span, &err)
+	handler = &VolumeHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	// Get volume data
 	volume, _, err := handler.Inspect(ctx, volumeName)
@@ -381,6 +425,27 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 		return "", err
 	}
 
+	// A volume encrypted once stays encrypted, whatever the host it is attached to
+	encrypted, err := isVolumeEncrypted(volume)
+	if err != nil {
+		return "", err
+	}
+	encrypt = encrypt || encrypted
+	if encrypt && volume.ManagedByLVM && len(volume.PVM) != 0 {
+		return "", scerr.NotImplementedError("encryption of a volume managed by LVM")
+	}
+
+	raid, _, err := getRAIDDescription(volume)
+	if err != nil {
+		return "", err
+	}
+	if raid != "" {
+		if encrypt {
+			return "", scerr.NotImplementedError("encryption of a RAID volume")
+		}
+		return "", handler.attachRAID(ctx, volume, hostName, path, format, doNotFormat)
+	}
+
 	// FIXME Handle volume.Formatted
 	if volume.ManagedByLVM {
 		if len(volume.PVM) != 0 {
@@ -406,8 +471,22 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 		mountPoint string
 		vaID       string
 		server     *nfs.Server
+		key        string
 	)
 
+	if encrypt {
+		key, err = handler.getEncryptionKey(volume)
+		if err != nil {
+			return "", err
+		}
+	}
+	unmount := func(server *nfs.Server, volumeUUID string) error {
+		if encrypt {
+			return server.UnmountEncryptedBlockDevice(volumeUUID)
+		}
+		return server.UnmountBlockDevice(volumeUUID)
+	}
+
 	err = volume.Properties.LockForWrite(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
 		volumeAttachedV1 := clonable.(*propsv1.VolumeAttachments)
 
@@ -532,7 +611,11 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 				if err != nil {
 					return err
 				}
-				volumeUUID, err = server.MountBlockDevice(deviceName, mountPoint, format, doNotFormat)
+				if encrypt {
+					volumeUUID, err = server.MountEncryptedBlockDevice(deviceName, mountPoint, format, doNotFormat, key)
+				} else {
+					volumeUUID, err = server.MountBlockDevice(deviceName, mountPoint, format, doNotFormat)
+				}
 				if err != nil {
 					return err
 				}
@@ -549,7 +632,7 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 				// Starting from here, unmount block device if exiting with error
 				defer func() {
 					if err != nil {
-						derr := server.UnmountBlockDevice(volumeUUID)
+						derr := unmount(server, volumeUUID)
 						if derr != nil {
 							logrus.Errorf("failed to unmount volume '%s' from host '%s': %v", volume.Name, host.Name, derr)
 							err = scerr.AddConsequence(err, derr)
@@ -563,6 +646,9 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 					Path:       mountPoint,
 					FileSystem: "nfs",
 				}
+				if encrypt {
+					hostMountsV1.LocalMountsByPath[mountPoint].Options = "luks"
+				}
 				hostMountsV1.LocalMountsByDevice[volumeUUID] = mountPoint
 
 				return nil
@@ -575,7 +661,7 @@ func (handler *VolumeHandler) Attach(ctx context.Context, volumeName, hostName,
 
 	defer func() {
 		if err != nil {
-			derr := server.UnmountBlockDevice(volumeUUID)
+			derr := unmount(server, volumeUUID)
 			if derr != nil {
 				logrus.Errorf("failed to unmount volume '%s' from host '%s': %v", volume.Name, host.Name, derr)
 				err = scerr.AddConsequence(err, derr)
@@ -734,7 +820,7 @@ func (handler *VolumeHandler) attachLVM(ctx context.Context, volumeName, hostNam
 
 	var deviceNames []string
 	for _, volumeSlice := range volume.PVM {
-		devName, err := handler.Attach(ctx, volumeSlice.Name, hostName, path, format, doNotFormat)
+		devName, err := handler.Attach(ctx, volumeSlice.Name, hostName, path, format, doNotFormat, false)
 		if err != nil {
 			return err
 		}
@@ -1165,6 +1251,10 @@ func getHostLocalMount(ctx context.Context, handler *VolumeHandler, volumeName,
 
 // Detach detach the volume identified by ref, ref can be the name or the id
 func (handler *VolumeHandler) Expand(ctx context.Context, volumeName, hostName string, increment uint32, incrementType string) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "VolumeHandler.Expand")
+	defer tracing.EndSpan(span, &err)
+	handler = &VolumeHandler{service: iaas.WithContext(ctx, handler.service)}
+
 	// Load volume data
 	volume, _, err := handler.Inspect(ctx, volumeName)
 	if err != nil {
@@ -1265,7 +1355,7 @@ func (handler *VolumeHandler) Expand(ctx context.Context, volumeName, hostName s
 			return scerr.Wrap(err, fmt.Sprintf("Error creating volume '%s' saving its volume metadata", newVolume.Name))
 		}
 
-		devName, err := handler.Attach(ctx, newVolume.Name, hostName, mountInfo.Path+"_lvm_"+strconv.Itoa(len(volume.PVM)+tba), mountInfo.FileSystem, false)
+		devName, err := handler.Attach(ctx, newVolume.Name, hostName, mountInfo.Path+"_lvm_"+strconv.Itoa(len(volume.PVM)+tba), mountInfo.FileSystem, false, false)
 		if err != nil {
 			logrus.Debugf("Error attaching volume: %v", err.Error())
 			return err
@@ -1306,6 +1396,9 @@ func (handler *VolumeHandler) Shrink(ctx context.Context, volumeName, hostName s
 		return scerr.InvalidInstanceError()
 	}
 	// FIXME: validate parameters
+	ctx, span := tracing.StartSpan(ctx, "VolumeHandler.Shrink")
+	defer tracing.EndSpan(span, &err)
+	handler = &VolumeHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	// Load volume data
 	volume, _, err := handler.Inspect(ctx, volumeName)
@@ -1476,6 +1569,9 @@ func (handler *VolumeHandler) Detach(ctx context.Context, volumeName, hostName s
 	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", volumeName, hostName), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "VolumeHandler.Detach")
+	defer tracing.EndSpan(span, &err)
+	handler = &VolumeHandler{service: iaas.WithContext(ctx, handler.service)}
 
 	// Load volume data
 	volume, _, err := handler.Inspect(ctx, volumeName)
@@ -1552,7 +1648,18 @@ func (handler *VolumeHandler) Detach(ctx context.Context, volumeName, hostName s
 				if err != nil {
 					return err
 				}
-				err = nfsServer.UnmountBlockDevice(attachment.Device)
+				raid, members, err := getRAIDDescription(volume)
+				if err != nil {
+					return err
+				}
+				switch {
+				case raid != "":
+					err = nfsServer.UnmountRAIDDevice(volume.Name, attachment.Device)
+				case mount.Options == "luks":
+					err = nfsServer.UnmountEncryptedBlockDevice(attachment.Device)
+				default:
+					err = nfsServer.UnmountBlockDevice(attachment.Device)
+				}
 				if err != nil {
 					// FIXME Think about this
 					logrus.Error(err)
@@ -1560,7 +1667,11 @@ func (handler *VolumeHandler) Detach(ctx context.Context, volumeName, hostName s
 				}
 
 				// ... then detach volume
-				err = handler.service.DeleteVolumeAttachment(host.ID, attachment.AttachID)
+				if raid != "" {
+					err = handler.detachRAIDMembers(host, members)
+				} else {
+					err = handler.service.DeleteVolumeAttachment(host.ID, attachment.AttachID)
+				}
 				if err != nil {
 					switch err.(type) {
 					case scerr.ErrNotFound, scerr.ErrInvalidRequest, scerr.ErrTimeout:
@@ -1607,7 +1718,8 @@ func (handler *VolumeHandler) Detach(ctx context.Context, volumeName, hostName s
 	case <-ctx.Done():
 		logrus.Warnf("Volume detachment cancelled by user")
 		// Currently format is not registered anywhere so we use ext4 the most common format (but as we mount the volume the format parameter is ignored anyway)
-		_, err = handler.Attach(context.Background(), volumeName, hostName, mountPath, "ext4", true)
+		// An encrypted volume is unlocked again with its escrowed key, there is no need to ask for encryption
+		_, err = handler.Attach(context.Background(), volumeName, hostName, mountPath, "ext4", true, false)
 		if err != nil {
 			return fmt.Errorf("failed to stop volume detachment")
 		}
@@ -1617,3 +1729,65 @@ func (handler *VolumeHandler) Detach(ctx context.Context, volumeName, hostName s
 
 	return nil
 }
+
+// isVolumeEncrypted tells if a key has been escrowed in the metadata of the volume
+func isVolumeEncrypted(volume *resources.Volume) (bool, error) {
+	encrypted := false
+	err := volume.Properties.LockForRead(volumeproperty.EncryptionV1).ThenUse(func(clonable data.Clonable) error {
+		encrypted = clonable.(*propsv1.VolumeEncryption).IsEncrypted()
+		return nil
+	})
+	return encrypted, err
+}
+
+// getEncryptionKey returns the LUKS key of the volume, deciphered with the metadata key of the tenant;
+// if the volume is not encrypted yet, a new key is generated and escrowed in the metadata of the volume
+func (handler *VolumeHandler) getEncryptionKey(volume *resources.Volume) (key string, err error) {
+	metadataKey := handler.service.GetMetadataKey()
+	if metadataKey == nil {
+		return "", scerr.InvalidRequestError("encryption of volumes needs the metadata of the tenant to be encrypted ('CryptKey' in section 'metadata' of tenant configuration)")
+	}
+
+	generated := false
+	err = volume.Properties.LockForWrite(volumeproperty.EncryptionV1).ThenUse(func(clonable data.Clonable) error {
+		volumeEncryptionV1 := clonable.(*propsv1.VolumeEncryption)
+		if volumeEncryptionV1.IsEncrypted() {
+			ciphered, err := base64.StdEncoding.DecodeString(volumeEncryptionV1.Key)
+			if err != nil {
+				return err
+			}
+			deciphered, err := crypt.Decrypt(ciphered, metadataKey)
+			if err != nil {
+				return fmt.Errorf("failed to decipher the key of volume '%s': %v", volume.Name, err)
+			}
+			key = string(deciphered)
+			return nil
+		}
+
+		random, err := crypt.NewEncryptionKey(nil)
+		if err != nil {
+			return err
+		}
+		key = base64.StdEncoding.EncodeToString(random[:])
+		ciphered, err := crypt.Encrypt([]byte(key), metadataKey)
+		if err != nil {
+			return err
+		}
+		volumeEncryptionV1.Key = base64.StdEncoding.EncodeToString(ciphered)
+		volumeEncryptionV1.Created = time.Now()
+		generated = true
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// The key is saved before being used, the device would not be usable anymore without it
+	if generated {
+		_, err = metadata.SaveVolume(handler.service, volume)
+		if err != nil {
+			return "", err
+		}
+	}
+	return key, nil
+}
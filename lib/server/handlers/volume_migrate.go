@@ -0,0 +1,253 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
+)
+
+// Migrate moves a volume from the host it is attached to to another host, keeping its mount path; if the provider
+// cannot attach the volume to the new host (another availability zone, ...), a new volume of the same size and
+// speed is created in the availability zone of the new host and attached to it, and the data are copied in it,
+// streamed through the daemon, before the old volume is deleted
+func (handler *VolumeHandler) Migrate(ctx context.Context, volumeName, hostName string) (err error) {
+	if handler == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if volumeName == "" {
+		return scerr.InvalidParameterError("volumeName", "cannot be empty string")
+	}
+	if hostName == "" {
+		return scerr.InvalidParameterError("hostName", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", volumeName, hostName), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+	ctx, span := tracing.StartSpan(ctx, "VolumeHandler.Migrate")
+	defer tracing.EndSpan(span, &err)
+	handler = &VolumeHandler{service: iaas.WithContext(ctx, handler.service)}
+
+	volume, mounts, err := handler.Inspect(ctx, volumeName)
+	if err != nil {
+		return err
+	}
+	var (
+		sourceName string
+		mount      *propsv1.HostLocalMount
+	)
+	for k, v := range mounts {
+		sourceName, mount = k, v
+		break
+	}
+	if mount == nil {
+		return scerr.InvalidRequestError(fmt.Sprintf("cannot migrate volume '%s': not attached to any host", volume.Name))
+	}
+	if mount.Path == "" {
+		return fmt.Errorf("metadata inconsistency: no mount corresponding to the attachment of volume '%s' to host '%s'", volume.Name, sourceName)
+	}
+
+	target, err := NewHostHandler(handler.service).ForceInspect(ctx, hostName)
+	if err != nil {
+		return err
+	}
+	if target.Name == sourceName {
+		return scerr.InvalidRequestError(fmt.Sprintf("volume '%s' is already attached to host '%s'", volume.Name, target.Name))
+	}
+
+	// Currently format is not registered for plain volumes, so we use ext4 the most common format
+	format := mount.FileSystem
+	if format == "" || format == "nfs" {
+		format = "ext4"
+	}
+	encrypted, err := isVolumeEncrypted(volume)
+	if err != nil {
+		return err
+	}
+
+	// First tries to move the volume itself
+	err = handler.Detach(ctx, volume.Name, sourceName)
+	if err != nil {
+		return err
+	}
+	_, err = handler.Attach(ctx, volume.Name, target.Name, mount.Path, format, true, false)
+	if err == nil {
+		return nil
+	}
+	logrus.Warnf("Failed to attach volume '%s' to host '%s' (%v), copying its data in a new volume", volume.Name, target.Name, err)
+	_, derr := handler.Attach(context.Background(), volume.Name, sourceName, mount.Path, format, true, false)
+	if derr != nil {
+		return scerr.AddConsequence(err, derr)
+	}
+
+	// Reloads the volume, its metadata have been updated by detach and attach
+	volume, _, err = handler.Inspect(ctx, volume.Name)
+	if err != nil {
+		return err
+	}
+	return handler.migrateByCopy(ctx, volume, sourceName, target, mount.Path, format, encrypted)
+}
+
+// migrateByCopy attaches to target a new volume like volume, copies the data of volume from host sourceName in it,
+// then replaces volume with it
+func (handler *VolumeHandler) migrateByCopy(
+	ctx context.Context, volume *resources.Volume, sourceName string, target *resources.Host, mountPath, format string, encrypt bool,
+) (err error) {
+
+	raid, members, err := getRAIDDescription(volume)
+	if err != nil {
+		return err
+	}
+	size := volume.Size
+	if raid != "" {
+		size = volume.Size / raidDataMembers(raid, len(members))
+	}
+
+	// The new volume is created in the availability zone of the target, and takes the name of the old one once the
+	// data are copied
+	copyName := volume.Name + "-migration"
+	var zone string
+	err = target.Properties.LockForRead(hostproperty.DescriptionV1).ThenUse(func(clonable data.Clonable) error {
+		zone = clonable.(*propsv1.HostDescription).AvailabilityZone
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if zone == "" {
+		logrus.Warnf("Availability zone of host '%s' unknown, volume '%s' created in the default one", target.Name, copyName)
+	}
+	newVolume, err := handler.create(ctx, copyName, size, volume.Speed, raid, len(members), zone)
+	if err != nil {
+		return err
+	}
+	attached, oldDetached := false, false
+	defer func() {
+		// Once the old volume is detached, the copy holds the only usable data and is kept
+		if err != nil && !oldDetached {
+			if attached {
+				derr := handler.Detach(context.Background(), copyName, target.Name)
+				if derr != nil {
+					logrus.Errorf("Cleaning up on failure, failed to detach volume '%s' from host '%s': %v", copyName, target.Name, derr)
+					err = scerr.AddConsequence(err, derr)
+					return
+				}
+			}
+			derr := handler.Delete(context.Background(), copyName)
+			if derr != nil {
+				logrus.Errorf("Cleaning up on failure, failed to delete volume '%s': %v", copyName, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	_, err = handler.Attach(ctx, copyName, target.Name, mountPath, format, false, encrypt)
+	if err != nil {
+		return err
+	}
+	attached = true
+
+	sourceServer, err := getServerByID(ctx, handler, sourceName)
+	if err != nil {
+		return err
+	}
+	targetServer, err := getServerByID(ctx, handler, target.ID)
+	if err != nil {
+		return err
+	}
+	// Writes done in the old volume during the copy would be lost, so it stays read-only until it is deleted
+	err = sourceServer.RemountBlockDevice(mountPath, true)
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("failed to make volume '%s' read-only on host '%s', files may be open for writing", volume.Name, sourceName))
+	}
+	defer func() {
+		if err != nil && !oldDetached {
+			derr := sourceServer.RemountBlockDevice(mountPath, false)
+			if derr != nil {
+				logrus.Errorf("Cleaning up on failure, failed to make volume '%s' writable again on host '%s': %v", volume.Name, sourceName, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+	err = targetServer.CopyFromHost(ctx, sourceServer.SSHConfig, mountPath, mountPath)
+	if err != nil {
+		return err
+	}
+
+	// The data are copied, the old volume can go away
+	err = handler.Detach(ctx, volume.Name, sourceName)
+	if err != nil {
+		return err
+	}
+	oldDetached = true
+	err = handler.Delete(ctx, volume.Name)
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("data of volume '%s' copied in volume '%s', but failed to delete the old volume", volume.Name, copyName))
+	}
+	return handler.renameVolume(ctx, newVolume.ID, volume.Name, target.ID)
+}
+
+// renameVolume gives a new name to a volume in metadata, and updates the host the volume is attached to
+func (handler *VolumeHandler) renameVolume(ctx context.Context, volumeID, name, hostID string) error {
+	mv, err := metadata.LoadVolume(handler.service, volumeID)
+	if err != nil {
+		return err
+	}
+	volume, err := mv.Get()
+	if err != nil {
+		return err
+	}
+	oldName := volume.Name
+	err = mv.Delete()
+	if err != nil {
+		return err
+	}
+	volume.Name = name
+	_, err = metadata.SaveVolume(handler.service, volume)
+	if err != nil {
+		return err
+	}
+
+	host, err := NewHostHandler(handler.service).ForceInspect(ctx, hostID)
+	if err != nil {
+		return err
+	}
+	err = host.Properties.LockForWrite(hostproperty.VolumesV1).ThenUse(func(clonable data.Clonable) error {
+		hostVolumesV1 := clonable.(*propsv1.HostVolumes)
+		delete(hostVolumesV1.VolumesByName, oldName)
+		hostVolumesV1.VolumesByName[name] = volume.ID
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = metadata.SaveHost(handler.service, host)
+	return err
+}
@@ -0,0 +1,447 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+	uuid "github.com/satori/go.uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumeproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumestate"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// A RAID volume is a volume known only by SafeScale, made of several provider volumes (its members) assembled
+// by mdadm in a software RAID array on the host the volume is attached to. The superblocks written by mdadm on
+// the members allow to assemble the array again on any host.
+
+// validateRAID checks the RAID level is supported and the number of members is suitable for it
+func validateRAID(level string, count int) error {
+	switch level {
+	case "0", "1":
+		if count < 2 {
+			return scerr.InvalidParameterError("count", fmt.Sprintf("RAID %s needs at least 2 volumes", level))
+		}
+	case "10":
+		if count < 4 || count%2 != 0 {
+			return scerr.InvalidParameterError("count", "RAID 10 needs an even number of volumes, at least 4")
+		}
+	default:
+		return scerr.InvalidParameterError("raid", fmt.Sprintf("unsupported RAID level '%s', can be 0, 1 or 10", level))
+	}
+	return nil
+}
+
+// raidDataMembers returns how many members of a RAID array hold distinct data, the others holding mirrors;
+// the usable size of the array is this number times the size of a member
+func raidDataMembers(level string, count int) int {
+	switch level {
+	case "0":
+		return count
+	case "10":
+		return count / 2
+	default:
+		return 1
+	}
+}
+
+// getRAIDDescription returns the RAID level and the members of a RAID volume; level is empty if the volume
+// is not a RAID volume
+func getRAIDDescription(volume *resources.Volume) (level string, members []string, err error) {
+	err = volume.Properties.LockForRead(volumeproperty.DescriptionV1).ThenUse(func(clonable data.Clonable) error {
+		volumeDescriptionV1 := clonable.(*propsv1.VolumeDescription)
+		level = volumeDescriptionV1.RAID
+		members = volumeDescriptionV1.Members
+		return nil
+	})
+	return level, members, err
+}
+
+// createRAID creates the members of a RAID volume, each of size GB (in the availability zone zone if not empty), then
+// saves the metadata of the volume
+func (handler *VolumeHandler) createRAID(ctx context.Context, name string, size int, speed volumespeed.Enum, level string, count int, zone string) (volume *resources.Volume, err error) {
+	err = validateRAID(level, count)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []string
+	defer func() {
+		if err != nil {
+			derr := handler.deleteRAIDMembers(members)
+			if derr != nil {
+				logrus.Errorf("Cleaning up on failure, failed to delete members of volume '%s': %v", name, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+	for i := 1; i <= count; i++ {
+		member, err := handler.service.CreateVolume(resources.VolumeRequest{
+			Name:             fmt.Sprintf("%s-raid-%d", name, i),
+			Size:             size,
+			Speed:            speed,
+			AvailabilityZone: zone,
+		})
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member.ID)
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, scerr.Wrap(err, "Error creating UUID for volume")
+	}
+	volume = resources.NewVolume()
+	volume.ID = id.String()
+	volume.Name = name
+	volume.Size = raidDataMembers(level, count) * size
+	volume.Speed = speed
+	volume.State = volumestate.AVAILABLE
+	err = volume.Properties.LockForWrite(volumeproperty.DescriptionV1).ThenUse(func(clonable data.Clonable) error {
+		volumeDescriptionV1 := clonable.(*propsv1.VolumeDescription)
+		volumeDescriptionV1.Created = time.Now()
+		volumeDescriptionV1.RAID = level
+		volumeDescriptionV1.Members = members
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := metadata.SaveVolume(handler.service, volume)
+	if err != nil {
+		logrus.Debugf("Error creating volume: saving volume metadata: %+v", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			derr := md.Delete()
+			if derr != nil {
+				logrus.Warnf("failed to delete metadata of volume '%s'", name)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		logrus.Warnf("Volume creation cancelled by user")
+		err = fmt.Errorf("volume creation cancelled by user")
+		return nil, err
+	default:
+	}
+
+	return volume, nil
+}
+
+// deleteRAIDMembers deletes the provider volumes making up a RAID volume
+func (handler *VolumeHandler) deleteRAIDMembers(members []string) error {
+	var errs []error
+	for _, id := range members {
+		err := handler.service.DeleteVolume(id)
+		if err != nil {
+			if _, ok := err.(scerr.ErrNotFound); ok {
+				logrus.Warnf("Unable to find the volume '%s' on provider side, considered as deleted", id)
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return scerr.ErrListError(errs)
+	}
+	return nil
+}
+
+// attachProviderVolume attaches a provider volume to a host and returns the ID of the attachment and the device
+// of the volume on the host
+func (handler *VolumeHandler) attachProviderVolume(ctx context.Context, host *resources.Host, volumeID, name string) (_ string, _ string, err error) {
+	// Note: most providers are not able to tell the real device name the volume
+	//       will have on the host, so we have to use a way that can work everywhere
+	oldDiskSet, err := handler.listAttachedDevices(ctx, host)
+	if err != nil {
+		return "", "", err
+	}
+	vaID, err := handler.service.CreateVolumeAttachment(resources.VolumeAttachmentRequest{
+		Name:     name,
+		HostID:   host.ID,
+		VolumeID: volumeID,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	defer func() {
+		if err != nil {
+			derr := handler.service.DeleteVolumeAttachment(host.ID, vaID)
+			if derr != nil {
+				logrus.Errorf("Cleaning up on failure, failed to detach volume '%s' from host '%s': %v", volumeID, host.Name, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	var newDisk mapset.Set
+	retryErr := retry.WhileUnsuccessfulDelay1Second(
+		func() error {
+			newDiskSet, err := handler.listAttachedDevices(ctx, host)
+			if err != nil {
+				return err
+			}
+			newDisk = newDiskSet.Difference(oldDiskSet)
+			if newDisk.Cardinality() == 0 {
+				return fmt.Errorf("disk not yet attached, retrying")
+			}
+			return nil
+		},
+		temporal.GetExecutionTimeout(),
+	)
+	if retryErr != nil {
+		err = fmt.Errorf("failed to confirm the disk attachment after %s", temporal.GetExecutionTimeout())
+		return "", "", err
+	}
+	return vaID, "/dev/" + newDisk.ToSlice()[0].(string), nil
+}
+
+// attachRAID attaches the members of a RAID volume to a host, then assembles and mounts the array
+func (handler *VolumeHandler) attachRAID(ctx context.Context, volume *resources.Volume, hostName, path, format string, doNotFormat bool) (err error) {
+	level, members, err := getRAIDDescription(volume)
+	if err != nil {
+		return err
+	}
+
+	err = handler.isAlreadyMounted(ctx, hostName, volume)
+	if err != nil {
+		return err
+	}
+
+	hostSvc := NewHostHandler(handler.service)
+	host, err := hostSvc.ForceInspect(ctx, hostName)
+	if err != nil {
+		return err
+	}
+
+	mountPoint := path
+	if path == resources.DefaultVolumeMountPoint {
+		mountPoint = resources.DefaultVolumeMountPoint + volume.Name
+	}
+
+	// Check if there is no other device mounted in the path (or in subpath)
+	err = host.Properties.LockForRead(hostproperty.MountsV1).ThenUse(func(clonable data.Clonable) error {
+		hostMountsV1 := clonable.(*propsv1.HostMounts)
+		for _, i := range hostMountsV1.LocalMountsByPath {
+			if strings.Index(i.Path, mountPoint) == 0 {
+				return fmt.Errorf("cannot attach volume '%s' to '%s:%s': there is already a volume mounted in '%s:%s'", volume.Name, host.Name, mountPoint, host.Name, i.Path)
+			}
+		}
+		for _, i := range hostMountsV1.RemoteMountsByPath {
+			if strings.Index(i.Path, mountPoint) == 0 {
+				return fmt.Errorf("cannot attach volume '%s' to '%s:%s': there is a share mounted in path '%s:%s[/...]'", volume.Name, host.Name, mountPoint, host.Name, i.Path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Members are attached one after the other, to identify the device of each of them
+	var attachments, devices []string
+	defer func() {
+		if err != nil {
+			for _, vaID := range attachments {
+				derr := handler.service.DeleteVolumeAttachment(host.ID, vaID)
+				if derr != nil {
+					logrus.Errorf("Cleaning up on failure, failed to detach member of volume '%s' from host '%s': %v", volume.Name, host.Name, derr)
+					err = scerr.AddConsequence(err, derr)
+				}
+			}
+		}
+	}()
+	for i, memberID := range members {
+		vaID, device, err := handler.attachProviderVolume(ctx, host, memberID, fmt.Sprintf("%s-raid-%d-%s", volume.Name, i+1, host.Name))
+		if err != nil {
+			return err
+		}
+		attachments = append(attachments, vaID)
+		devices = append(devices, device)
+	}
+
+	server, err := getServerByID(ctx, handler, host.ID)
+	if err != nil {
+		return err
+	}
+	fsUUID, err := server.MountRAIDDevice(volume.Name, level, devices, mountPoint, format, doNotFormat)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			derr := server.UnmountRAIDDevice(volume.Name, fsUUID)
+			if derr != nil {
+				logrus.Errorf("failed to unmount volume '%s' from host '%s': %v", volume.Name, host.Name, derr)
+				err = scerr.AddConsequence(err, derr)
+			}
+		}
+	}()
+
+	// Updates host properties; there is no attachment ID, the attachments of the members are retrieved
+	// from the provider on detach
+	err = host.Properties.LockForWrite(hostproperty.VolumesV1).ThenUse(func(clonable data.Clonable) error {
+		hostVolumesV1 := clonable.(*propsv1.HostVolumes)
+		hostVolumesV1.VolumesByID[volume.ID] = &propsv1.HostVolume{Device: fsUUID}
+		hostVolumesV1.VolumesByName[volume.Name] = volume.ID
+		hostVolumesV1.VolumesByDevice[fsUUID] = volume.ID
+		hostVolumesV1.DevicesByID[volume.ID] = fsUUID
+		return host.Properties.LockForWrite(hostproperty.MountsV1).ThenUse(func(clonable data.Clonable) error {
+			hostMountsV1 := clonable.(*propsv1.HostMounts)
+			hostMountsV1.LocalMountsByPath[mountPoint] = &propsv1.HostLocalMount{
+				Device:     fsUUID,
+				Path:       mountPoint,
+				FileSystem: format,
+				Options:    "raid" + level,
+			}
+			hostMountsV1.LocalMountsByDevice[fsUUID] = mountPoint
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+	err = volume.Properties.LockForWrite(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
+		volumeAttachedV1 := clonable.(*propsv1.VolumeAttachments)
+		volumeAttachedV1.Hosts[host.ID] = host.Name
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = metadata.SaveHost(handler.service, host)
+	if err != nil {
+		return err
+	}
+	_, err = metadata.SaveVolume(handler.service, volume)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		logrus.Warnf("Volume attachment cancelled by user")
+		err = fmt.Errorf("volume attachment cancelled by user")
+		return err
+	default:
+	}
+
+	return nil
+}
+
+// detachRAIDMembers deletes the attachments to a host of the members of a RAID volume
+func (handler *VolumeHandler) detachRAIDMembers(host *resources.Host, members []string) error {
+	attachments, err := handler.service.ListVolumeAttachments(host.ID)
+	if err != nil {
+		return err
+	}
+	memberSet := mapset.NewThreadUnsafeSet()
+	for _, id := range members {
+		memberSet.Add(id)
+	}
+	var errs []error
+	for _, va := range attachments {
+		if !memberSet.Contains(va.VolumeID) {
+			continue
+		}
+		err = handler.service.DeleteVolumeAttachment(host.ID, va.ID)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return scerr.ErrListError(errs)
+	}
+	return nil
+}
+
+// GetRAIDHealth returns the health of the array of a RAID volume, as seen by mdadm on the host the volume is
+// attached to; returns an empty string if the volume is not a RAID volume or is not attached
+func (handler *VolumeHandler) GetRAIDHealth(ctx context.Context, volume *resources.Volume) (health string, err error) {
+	if handler == nil {
+		return "", scerr.InvalidInstanceError()
+	}
+	if volume == nil {
+		return "", scerr.InvalidParameterError("volume", "cannot be nil")
+	}
+
+	level, _, err := getRAIDDescription(volume)
+	if err != nil || level == "" {
+		return "", err
+	}
+
+	hostID := ""
+	err = volume.Properties.LockForRead(volumeproperty.AttachedV1).ThenUse(func(clonable data.Clonable) error {
+		for id := range clonable.(*propsv1.VolumeAttachments).Hosts {
+			hostID = id
+			break
+		}
+		return nil
+	})
+	if err != nil || hostID == "" {
+		return "", err
+	}
+
+	server, err := getServerByID(ctx, handler, hostID)
+	if err != nil {
+		return "", err
+	}
+	detail, err := server.GetRAIDDeviceDetail(volume.Name)
+	if err != nil {
+		return "", err
+	}
+	return parseRAIDHealth(detail), nil
+}
+
+// parseRAIDHealth summarizes the output of 'mdadm --detail' in a line, like "clean (2/2 devices active, 0 failed)"
+func parseRAIDHealth(detail string) string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(detail, "\n") {
+		parts := strings.SplitN(line, " : ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fields[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	state, ok := fields["State"]
+	if !ok {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s (%s/%s devices active, %s failed)", state, fields["Active Devices"], fields["Raid Devices"], fields["Failed Devices"])
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRAID(t *testing.T) {
+	assert.Nil(t, validateRAID("0", 2))
+	assert.Nil(t, validateRAID("1", 3))
+	assert.Nil(t, validateRAID("10", 4))
+	assert.NotNil(t, validateRAID("0", 1))
+	assert.NotNil(t, validateRAID("1", 1))
+	assert.NotNil(t, validateRAID("10", 2))
+	assert.NotNil(t, validateRAID("10", 5))
+	assert.NotNil(t, validateRAID("5", 3))
+	assert.NotNil(t, validateRAID("", 2))
+}
+
+func TestRAIDDataMembers(t *testing.T) {
+	assert.Equal(t, 3, raidDataMembers("0", 3))
+	assert.Equal(t, 1, raidDataMembers("1", 3))
+	assert.Equal(t, 3, raidDataMembers("10", 6))
+}
+
+func TestParseRAIDHealth(t *testing.T) {
+	detail := `/dev/md/data:
+           Version : 1.2
+        Raid Level : raid1
+      Raid Devices : 2
+     Total Devices : 1
+             State : clean, degraded
+    Active Devices : 1
+   Working Devices : 1
+    Failed Devices : 0
+     Spare Devices : 0
+`
+	assert.Equal(t, "clean, degraded (1/2 devices active, 0 failed)", parseRAIDHealth(detail))
+	assert.Equal(t, "unknown", parseRAIDHealth(""))
+}
@@ -150,6 +150,8 @@ func UseService(tenantName string) (newService Service, err error) {
 		if err != nil {
 			return nil, fmt.Errorf("error creating tenant '%s' on provider '%s': %s", tenantName, provider, err.Error())
 		}
+		// Collects metrics about every call made to the provider
+		providerInstance = api.NewMetricsProvider(providerInstance, provider)
 		serviceCfg, err := providerInstance.GetConfigurationOptions()
 		if err != nil {
 			return nil, err
@@ -234,7 +236,15 @@ func UseService(tenantName string) (newService Service, err error) {
 			metadataBucket: metadataBucket,
 			metadataKey:    metadataCryptKey,
 		}
-		return newS, validateRegexps(newS /*tenantClient*/, tenant)
+		err = validateRegexps(newS /*tenantClient*/, tenant)
+		if err != nil {
+			return newS, err
+		}
+		err = loadTenantPrices(newS, tenant)
+		if err != nil {
+			return newS, err
+		}
+		return newS, loadTenantPresets(newS, tenant)
 	}
 
 	if !tenantInCfg {
@@ -285,6 +295,38 @@ func validateRegexps(svc *service, tenant map[string]interface{}) error {
 	return nil
 }
 
+// loadTenantPrices loads the price file of the tenant, if any
+func loadTenantPrices(svc *service, tenant map[string]interface{}) error {
+	compute, ok := tenant["compute"].(map[string]interface{})
+	if !ok {
+		return scerr.InvalidParameterError("tenant['compute']", "is not a map")
+	}
+
+	if path, ok := compute["PriceFile"].(string); ok && path != "" {
+		prices, err := loadPrices(path)
+		if err != nil {
+			return err
+		}
+		svc.prices = prices
+	}
+	return nil
+}
+
+// loadTenantPresets loads the presets of the tenant, if any
+func loadTenantPresets(svc *service, tenant map[string]interface{}) error {
+	compute, ok := tenant["compute"].(map[string]interface{})
+	if !ok {
+		return scerr.InvalidParameterError("tenant['compute']", "is not a map")
+	}
+
+	presets, err := loadPresets(compute)
+	if err != nil {
+		return err
+	}
+	svc.presets = presets
+	return nil
+}
+
 // initObjectStorageLocationConfig initializes objectstorage.Config struct with map
 func initObjectStorageLocationConfig(authOpts providers.Config, tenant map[string]interface{}) (objectstorage.Config, error) {
 	var (
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iaas
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// PresetPrefix is the prefix marking a preset name where a sizing or an image is expected (ex: '@gpu-large')
+const PresetPrefix = "@"
+
+// Preset is a named set of sizing and image constraints, defined in the section 'Presets' of the section 'compute'
+// of a tenant
+type Preset struct {
+	Name string
+	// Sizing is a sizing expression, with the syntax of the flag --sizing (ex: "cpu>=8, gpu>=1")
+	Sizing string
+	// Image is the name of an image
+	Image string
+}
+
+// loadPresets reads the presets from the section 'compute' of a tenant
+func loadPresets(compute map[string]interface{}) (map[string]Preset, error) {
+	anon, ok := compute["Presets"]
+	if !ok {
+		return nil, nil
+	}
+	section, ok := anon.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid value for field 'Presets': must be a map of presets indexed by name")
+	}
+
+	presets := map[string]Preset{}
+	for name, v := range section {
+		if strings.HasPrefix(name, PresetPrefix) {
+			return nil, fmt.Errorf("invalid name '%s' for preset: must not start with '%s'", name, PresetPrefix)
+		}
+		fields, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid content of preset '%s': must be a map", name)
+		}
+		preset := Preset{Name: name}
+		if preset.Sizing, ok = fields["Sizing"].(string); ok && preset.Sizing != "" {
+			if err := validateSizing(preset.Sizing); err != nil {
+				return nil, fmt.Errorf("invalid value '%s' for field 'Sizing' of preset '%s': %s", preset.Sizing, name, err.Error())
+			}
+		}
+		preset.Image, _ = fields["Image"].(string)
+		if preset.Sizing == "" && preset.Image == "" {
+			return nil, fmt.Errorf("invalid content of preset '%s': at least one of the fields 'Sizing' and 'Image' must be set", name)
+		}
+		presets[name] = preset
+	}
+	return presets, nil
+}
+
+// validateSizing checks the syntax of a sizing expression
+func validateSizing(sizing string) error {
+	tokens, err := clitools.ParseParameter(sizing)
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		if _, _, err := t.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListPresets returns the presets of the tenant, sorted by name
+func (svc *service) ListPresets() []Preset {
+	var list []Preset
+	for _, p := range svc.presets {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// GetPreset returns the preset named name; the prefix PresetPrefix is ignored
+func (svc *service) GetPreset(name string) (*Preset, error) {
+	name = strings.TrimPrefix(name, PresetPrefix)
+	p, ok := svc.presets[name]
+	if !ok {
+		return nil, scerr.NotFoundError(fmt.Sprintf("preset '%s' not found", name))
+	}
+	return &p, nil
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iaas
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPresets(t *testing.T) {
+	compute := map[string]interface{}{
+		"Presets": map[string]interface{}{
+			"gpu-large":  map[string]interface{}{"Sizing": "cpu >= 16, ram >= 64, gpu >= 1"},
+			"ubuntu-lts": map[string]interface{}{"Image": "Ubuntu 18.04"},
+		},
+	}
+	presets, err := loadPresets(compute)
+	require.Nil(t, err)
+	svc := &service{presets: presets}
+
+	list := svc.ListPresets()
+	require.Len(t, list, 2)
+	assert.Equal(t, "gpu-large", list[0].Name)
+	assert.Equal(t, "ubuntu-lts", list[1].Name)
+
+	preset, err := svc.GetPreset("@ubuntu-lts")
+	require.Nil(t, err)
+	assert.Equal(t, "Ubuntu 18.04", preset.Image)
+	assert.Equal(t, "", preset.Sizing)
+
+	_, err = svc.GetPreset("@unknown")
+	assert.NotNil(t, err)
+
+	presets, err = loadPresets(map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Empty(t, presets)
+
+	_, err = loadPresets(map[string]interface{}{"Presets": map[string]interface{}{"empty": map[string]interface{}{}}})
+	assert.NotNil(t, err)
+	_, err = loadPresets(map[string]interface{}{"Presets": map[string]interface{}{"bad": map[string]interface{}{"Sizing": "cpu >= ["}}})
+	assert.NotNil(t, err)
+}
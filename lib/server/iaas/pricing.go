@@ -0,0 +1,114 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iaas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils"
+)
+
+// DefaultCurrency is the currency of the prices given by the providers
+const DefaultCurrency = "USD"
+
+// Prices contains the prices of a tenant, read from the file set by the field 'PriceFile' of the section 'compute'
+type Prices struct {
+	// Currency of the prices (default: DefaultCurrency)
+	Currency string `json:"currency,omitempty"`
+	// Templates contains the price per hour of the host templates, indexed by template name or ID
+	Templates map[string]float64 `json:"templates,omitempty"`
+	// Volumes contains the price per GB and per hour of the volumes, indexed by volume speed (COLD, HDD, SSD)
+	Volumes map[string]float64 `json:"volumes,omitempty"`
+}
+
+// loadPrices reads the price file at path
+func loadPrices(path string) (*Prices, error) {
+	content, err := ioutil.ReadFile(utils.AbsPathify(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price file '%s': %s", path, err.Error())
+	}
+	prices := &Prices{}
+	err = json.Unmarshal(content, prices)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content of price file '%s': %s", path, err.Error())
+	}
+	if prices.Currency == "" {
+		prices.Currency = DefaultCurrency
+	}
+	return prices, nil
+}
+
+// priceTemplates sets the price of the templates found in the price file, replacing the price given by the provider;
+// the prices of the provider being in DefaultCurrency, they are dropped for the other templates if the price file
+// uses another currency, so that prices in different currencies are never compared
+func (p *Prices) priceTemplates(tpls []resources.HostTemplate) {
+	if p == nil {
+		return
+	}
+	for i := range tpls {
+		if price, ok := p.Templates[tpls[i].Name]; ok {
+			tpls[i].PricePerHour = price
+		} else if price, ok := p.Templates[tpls[i].ID]; ok {
+			tpls[i].PricePerHour = price
+		} else if p.Currency != DefaultCurrency {
+			tpls[i].PricePerHour = 0
+		}
+	}
+}
+
+// GetCurrency returns the currency of the prices of templates and volumes
+func (svc *service) GetCurrency() string {
+	if svc.prices != nil {
+		return svc.prices.Currency
+	}
+	return DefaultCurrency
+}
+
+// GetVolumePrice returns the price per hour of a volume, and false if there is no price for its speed
+func (svc *service) GetVolumePrice(volume *resources.Volume) (float64, bool) {
+	if svc.prices == nil || volume == nil {
+		return 0, false
+	}
+	price, ok := svc.prices.Volumes[volume.Speed.String()]
+	if !ok {
+		return 0, false
+	}
+	return price * float64(volume.Size), true
+}
+
+// ByPrice implements sort.Interface for []HostTemplate based on the price per hour; templates without price come last,
+// templates with the same price are ordered by Dominant Resource Fairness
+type ByPrice []*resources.HostTemplate
+
+func (a ByPrice) Len() int      { return len(a) }
+func (a ByPrice) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a ByPrice) Less(i, j int) bool {
+	pi, pj := a[i].PricePerHour, a[j].PricePerHour
+	switch {
+	case pi == pj:
+		return RankDRF(a[i]) < RankDRF(a[j])
+	case pi <= 0:
+		return false
+	case pj <= 0:
+		return true
+	default:
+		return pi < pj
+	}
+}
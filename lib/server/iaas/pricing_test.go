@@ -0,0 +1,89 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iaas
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumespeed"
+)
+
+func TestByPrice(t *testing.T) {
+	tpls := []*resources.HostTemplate{
+		{Name: "unpriced", Cores: 1},
+		{Name: "expensive", Cores: 2, PricePerHour: 0.2},
+		{Name: "cheap-big", Cores: 8, PricePerHour: 0.1},
+		{Name: "cheap-small", Cores: 4, PricePerHour: 0.1},
+	}
+	sort.Sort(ByPrice(tpls))
+
+	var names []string
+	for _, tpl := range tpls {
+		names = append(names, tpl.Name)
+	}
+	assert.Equal(t, []string{"cheap-small", "cheap-big", "expensive", "unpriced"}, names)
+}
+
+func TestLoadPrices(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prices")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "prices.json")
+	err = ioutil.WriteFile(path, []byte(`{"templates":{"s1-2":0.01,"id-4":0.04},"volumes":{"SSD":0.001}}`), 0600)
+	require.Nil(t, err)
+
+	prices, err := loadPrices(path)
+	require.Nil(t, err)
+	assert.Equal(t, DefaultCurrency, prices.Currency)
+
+	tpls := []resources.HostTemplate{
+		{ID: "id-2", Name: "s1-2", PricePerHour: 0.5},
+		{ID: "id-4", Name: "s1-4"},
+		{ID: "id-8", Name: "s1-8", PricePerHour: 0.08},
+	}
+	prices.priceTemplates(tpls)
+	assert.Equal(t, 0.01, tpls[0].PricePerHour)
+	assert.Equal(t, 0.04, tpls[1].PricePerHour)
+	assert.Equal(t, 0.08, tpls[2].PricePerHour)
+
+	// Prices of the provider, in DefaultCurrency, are not mixed with prices in another currency
+	prices.Currency = "EUR"
+	tpls[2].PricePerHour = 0.08
+	prices.priceTemplates(tpls)
+	assert.Equal(t, 0.01, tpls[0].PricePerHour)
+	assert.Equal(t, float64(0), tpls[2].PricePerHour)
+	prices.Currency = DefaultCurrency
+
+	svc := &service{prices: prices}
+	price, ok := svc.GetVolumePrice(&resources.Volume{Size: 100, Speed: volumespeed.SSD})
+	assert.True(t, ok)
+	assert.InDelta(t, 0.1, price, 1e-9)
+	_, ok = svc.GetVolumePrice(&resources.Volume{Size: 100, Speed: volumespeed.HDD})
+	assert.False(t, ok)
+
+	_, err = loadPrices(filepath.Join(dir, "missing.json"))
+	assert.NotNil(t, err)
+}
@@ -197,6 +197,36 @@ func (w LoggedProvider) DeleteVIP(vip *resources.VirtualIP) error {
 	return w.InnerProvider.DeleteVIP(vip)
 }
 
+// AllocatePublicIP reserves a new public IP address
+func (w LoggedProvider) AllocatePublicIP(name string) (*resources.PublicIP, error) {
+	defer w.prepare(w.trace("AllocatePublicIP"))
+	return w.InnerProvider.AllocatePublicIP(name)
+}
+
+// ListPublicIPs lists the public IP addresses reserved on the tenant
+func (w LoggedProvider) ListPublicIPs() ([]*resources.PublicIP, error) {
+	defer w.prepare(w.trace("ListPublicIPs"))
+	return w.InnerProvider.ListPublicIPs()
+}
+
+// AttachPublicIP binds the public IP address to a host
+func (w LoggedProvider) AttachPublicIP(ip *resources.PublicIP, hostID string) error {
+	defer w.prepare(w.trace("AttachPublicIP"))
+	return w.InnerProvider.AttachPublicIP(ip, hostID)
+}
+
+// DetachPublicIP unbinds the public IP address from its host
+func (w LoggedProvider) DetachPublicIP(ip *resources.PublicIP) error {
+	defer w.prepare(w.trace("DetachPublicIP"))
+	return w.InnerProvider.DetachPublicIP(ip)
+}
+
+// ReleasePublicIP gives back the public IP address to the provider
+func (w LoggedProvider) ReleasePublicIP(ip *resources.PublicIP) error {
+	defer w.prepare(w.trace("ReleasePublicIP"))
+	return w.InnerProvider.ReleasePublicIP(ip)
+}
+
 // CreateHost ...
 func (w LoggedProvider) CreateHost(request resources.HostRequest) (*resources.Host, *userdata.Content, error) {
 	defer w.prepare(w.trace("CreateHost"))
@@ -257,6 +287,18 @@ func (w LoggedProvider) ResizeHost(id string, request resources.SizingRequiremen
 	return w.InnerProvider.ResizeHost(id, request)
 }
 
+// GetHostConsoleOutput ...
+func (w LoggedProvider) GetHostConsoleOutput(id string) (string, error) {
+	defer w.prepare(w.trace("GetHostConsoleOutput"))
+	return w.InnerProvider.GetHostConsoleOutput(id)
+}
+
+// IsHostReclaimed ...
+func (w LoggedProvider) IsHostReclaimed(id string) (bool, error) {
+	defer w.prepare(w.trace("IsHostReclaimed"))
+	return w.InnerProvider.IsHostReclaimed(id)
+}
+
 // CreateVolume ...
 func (w LoggedProvider) CreateVolume(request resources.VolumeRequest) (*resources.Volume, error) {
 	defer w.prepare(w.trace("CreateVolume"))
@@ -0,0 +1,338 @@
+package api
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
+)
+
+// MetricsProvider counts and times the calls made to the inner provider
+type MetricsProvider WrappedProvider
+
+// Provider specific functions
+
+// Build ...
+func (w MetricsProvider) Build(something map[string]interface{}) (p Provider, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "Build", time.Now(), &err)
+	return w.InnerProvider.Build(something)
+}
+
+// ListImages ...
+func (w MetricsProvider) ListImages(all bool) (images []resources.Image, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "ListImages", time.Now(), &err)
+	return w.InnerProvider.ListImages(all)
+}
+
+// ListTemplates ...
+func (w MetricsProvider) ListTemplates(all bool) (templates []resources.HostTemplate, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "ListTemplates", time.Now(), &err)
+	return w.InnerProvider.ListTemplates(all)
+}
+
+// GetAuthenticationOptions ...
+func (w MetricsProvider) GetAuthenticationOptions() (cfg providers.Config, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "GetAuthenticationOptions", time.Now(), &err)
+	return w.InnerProvider.GetAuthenticationOptions()
+}
+
+// GetConfigurationOptions ...
+func (w MetricsProvider) GetConfigurationOptions() (cfg providers.Config, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "GetConfigurationOptions", time.Now(), &err)
+	return w.InnerProvider.GetConfigurationOptions()
+}
+
+// GetName ...
+func (w MetricsProvider) GetName() string {
+	return w.InnerProvider.GetName()
+}
+
+// GetTenantParameters ...
+func (w MetricsProvider) GetTenantParameters() map[string]interface{} {
+	return w.InnerProvider.GetTenantParameters()
+}
+
+// Stack specific functions
+
+// NewMetricsProvider wraps innerProvider to collect metrics about its calls
+func NewMetricsProvider(innerProvider Provider, name string) *MetricsProvider {
+	return &MetricsProvider{InnerProvider: innerProvider, Name: name}
+}
+
+// ListAvailabilityZones ...
+func (w MetricsProvider) ListAvailabilityZones() (zones map[string]bool, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "ListAvailabilityZones", time.Now(), &err)
+	return w.InnerProvider.ListAvailabilityZones()
+}
+
+// ListRegions ...
+func (w MetricsProvider) ListRegions() (regions []string, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "ListRegions", time.Now(), &err)
+	return w.InnerProvider.ListRegions()
+}
+
+// GetImage ...
+func (w MetricsProvider) GetImage(id string) (images *resources.Image, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "GetImage", time.Now(), &err)
+	return w.InnerProvider.GetImage(id)
+}
+
+// GetTemplate ...
+func (w MetricsProvider) GetTemplate(id string) (templates *resources.HostTemplate, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "GetTemplate", time.Now(), &err)
+	return w.InnerProvider.GetTemplate(id)
+}
+
+// CreateKeyPair ...
+func (w MetricsProvider) CreateKeyPair(name string) (pairs *resources.KeyPair, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "CreateKeyPair", time.Now(), &err)
+	return w.InnerProvider.CreateKeyPair(name)
+}
+
+// GetKeyPair ...
+func (w MetricsProvider) GetKeyPair(id string) (pairs *resources.KeyPair, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "GetKeyPair", time.Now(), &err)
+	return w.InnerProvider.GetKeyPair(id)
+}
+
+// ListKeyPairs ...
+func (w MetricsProvider) ListKeyPairs() (pairs []resources.KeyPair, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "ListKeyPairs", time.Now(), &err)
+	return w.InnerProvider.ListKeyPairs()
+}
+
+// DeleteKeyPair ...
+func (w MetricsProvider) DeleteKeyPair(id string) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "DeleteKeyPair", time.Now(), &err)
+	return w.InnerProvider.DeleteKeyPair(id)
+}
+
+// CreateNetwork ...
+func (w MetricsProvider) CreateNetwork(req resources.NetworkRequest) (net *resources.Network, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "CreateNetwork", time.Now(), &err)
+	return w.InnerProvider.CreateNetwork(req)
+}
+
+// GetNetwork ...
+func (w MetricsProvider) GetNetwork(id string) (net *resources.Network, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "GetNetwork", time.Now(), &err)
+	return w.InnerProvider.GetNetwork(id)
+}
+
+// GetNetworkByName ...
+func (w MetricsProvider) GetNetworkByName(name string) (net *resources.Network, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "GetNetworkByName", time.Now(), &err)
+	return w.InnerProvider.GetNetworkByName(name)
+}
+
+// ListNetworks ...
+func (w MetricsProvider) ListNetworks() (net []*resources.Network, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "ListNetworks", time.Now(), &err)
+	return w.InnerProvider.ListNetworks()
+}
+
+// DeleteNetwork ...
+func (w MetricsProvider) DeleteNetwork(id string) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "DeleteNetwork", time.Now(), &err)
+	return w.InnerProvider.DeleteNetwork(id)
+}
+
+// CreateGateway ...
+func (w MetricsProvider) CreateGateway(req resources.GatewayRequest, sizing *resources.SizingRequirements) (host *resources.Host, content *userdata.Content, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "CreateGateway", time.Now(), &err)
+	return w.InnerProvider.CreateGateway(req, sizing)
+}
+
+// DeleteGateway ...
+func (w MetricsProvider) DeleteGateway(networkID string) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "DeleteGateway", time.Now(), &err)
+	return w.InnerProvider.DeleteGateway(networkID)
+}
+
+// CreateVIP ...
+func (w MetricsProvider) CreateVIP(networkID string, description string) (_ *resources.VirtualIP, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "CreateVIP", time.Now(), &err)
+	return w.InnerProvider.CreateVIP(networkID, description)
+}
+
+// AddPublicIPToVIP adds a public IP to VIP
+func (w MetricsProvider) AddPublicIPToVIP(vip *resources.VirtualIP) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "AddPublicIPToVIP", time.Now(), &err)
+	return w.InnerProvider.AddPublicIPToVIP(vip)
+}
+
+// BindHostToVIP makes the host passed as parameter an allowed "target" of the VIP
+func (w MetricsProvider) BindHostToVIP(vip *resources.VirtualIP, hostID string) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "BindHostToVIP", time.Now(), &err)
+	return w.InnerProvider.BindHostToVIP(vip, hostID)
+}
+
+// UnbindHostFromVIP removes the bind between the VIP and a host
+func (w MetricsProvider) UnbindHostFromVIP(vip *resources.VirtualIP, hostID string) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "UnbindHostFromVIP", time.Now(), &err)
+	return w.InnerProvider.UnbindHostFromVIP(vip, hostID)
+}
+
+// DeleteVIP deletes the port corresponding to the VIP
+func (w MetricsProvider) DeleteVIP(vip *resources.VirtualIP) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "DeleteVIP", time.Now(), &err)
+	return w.InnerProvider.DeleteVIP(vip)
+}
+
+// AllocatePublicIP reserves a new public IP address
+func (w MetricsProvider) AllocatePublicIP(name string) (_ *resources.PublicIP, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "AllocatePublicIP", time.Now(), &err)
+	return w.InnerProvider.AllocatePublicIP(name)
+}
+
+// ListPublicIPs lists the public IP addresses reserved on the tenant
+func (w MetricsProvider) ListPublicIPs() (_ []*resources.PublicIP, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "ListPublicIPs", time.Now(), &err)
+	return w.InnerProvider.ListPublicIPs()
+}
+
+// AttachPublicIP binds the public IP address to a host
+func (w MetricsProvider) AttachPublicIP(ip *resources.PublicIP, hostID string) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "AttachPublicIP", time.Now(), &err)
+	return w.InnerProvider.AttachPublicIP(ip, hostID)
+}
+
+// DetachPublicIP unbinds the public IP address from its host
+func (w MetricsProvider) DetachPublicIP(ip *resources.PublicIP) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "DetachPublicIP", time.Now(), &err)
+	return w.InnerProvider.DetachPublicIP(ip)
+}
+
+// ReleasePublicIP gives back the public IP address to the provider
+func (w MetricsProvider) ReleasePublicIP(ip *resources.PublicIP) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "ReleasePublicIP", time.Now(), &err)
+	return w.InnerProvider.ReleasePublicIP(ip)
+}
+
+// CreateHost ...
+func (w MetricsProvider) CreateHost(request resources.HostRequest) (_ *resources.Host, _ *userdata.Content, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "CreateHost", time.Now(), &err)
+	return w.InnerProvider.CreateHost(request)
+}
+
+// InspectHost ...
+func (w MetricsProvider) InspectHost(something interface{}) (_ *resources.Host, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "InspectHost", time.Now(), &err)
+	return w.InnerProvider.InspectHost(something)
+}
+
+// GetHostByName ...
+func (w MetricsProvider) GetHostByName(name string) (_ *resources.Host, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "GetHostByName", time.Now(), &err)
+	return w.InnerProvider.GetHostByName(name)
+}
+
+// GetHostState ...
+func (w MetricsProvider) GetHostState(something interface{}) (_ hoststate.Enum, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "GetHostState", time.Now(), &err)
+	return w.InnerProvider.GetHostState(something)
+}
+
+// ListHosts ...
+func (w MetricsProvider) ListHosts() (_ []*resources.Host, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "ListHosts", time.Now(), &err)
+	return w.InnerProvider.ListHosts()
+}
+
+// DeleteHost ...
+func (w MetricsProvider) DeleteHost(id string) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "DeleteHost", time.Now(), &err)
+	return w.InnerProvider.DeleteHost(id)
+}
+
+// StopHost ...
+func (w MetricsProvider) StopHost(id string) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "StopHost", time.Now(), &err)
+	return w.InnerProvider.StopHost(id)
+}
+
+// StartHost ...
+func (w MetricsProvider) StartHost(id string) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "StartHost", time.Now(), &err)
+	return w.InnerProvider.StartHost(id)
+}
+
+// RebootHost ...
+func (w MetricsProvider) RebootHost(id string) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "RebootHost", time.Now(), &err)
+	return w.InnerProvider.RebootHost(id)
+}
+
+// ResizeHost ...
+func (w MetricsProvider) ResizeHost(id string, request resources.SizingRequirements) (_ *resources.Host, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "ResizeHost", time.Now(), &err)
+	return w.InnerProvider.ResizeHost(id, request)
+}
+
+// GetHostConsoleOutput ...
+func (w MetricsProvider) GetHostConsoleOutput(id string) (_ string, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "GetHostConsoleOutput", time.Now(), &err)
+	return w.InnerProvider.GetHostConsoleOutput(id)
+}
+
+// IsHostReclaimed ...
+func (w MetricsProvider) IsHostReclaimed(id string) (_ bool, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "IsHostReclaimed", time.Now(), &err)
+	return w.InnerProvider.IsHostReclaimed(id)
+}
+
+// CreateVolume ...
+func (w MetricsProvider) CreateVolume(request resources.VolumeRequest) (_ *resources.Volume, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "CreateVolume", time.Now(), &err)
+	return w.InnerProvider.CreateVolume(request)
+}
+
+// GetVolume ...
+func (w MetricsProvider) GetVolume(id string) (_ *resources.Volume, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "GetVolume", time.Now(), &err)
+	return w.InnerProvider.GetVolume(id)
+}
+
+// ListVolumes ...
+func (w MetricsProvider) ListVolumes() (_ []resources.Volume, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "ListVolumes", time.Now(), &err)
+	return w.InnerProvider.ListVolumes()
+}
+
+// DeleteVolume ...
+func (w MetricsProvider) DeleteVolume(id string) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "DeleteVolume", time.Now(), &err)
+	return w.InnerProvider.DeleteVolume(id)
+}
+
+// CreateVolumeAttachment ...
+func (w MetricsProvider) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (_ string, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "CreateVolumeAttachment", time.Now(), &err)
+	return w.InnerProvider.CreateVolumeAttachment(request)
+}
+
+// GetVolumeAttachment ...
+func (w MetricsProvider) GetVolumeAttachment(serverID, id string) (_ *resources.VolumeAttachment, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "GetVolumeAttachment", time.Now(), &err)
+	return w.InnerProvider.GetVolumeAttachment(serverID, id)
+}
+
+// ListVolumeAttachments ...
+func (w MetricsProvider) ListVolumeAttachments(serverID string) (_ []resources.VolumeAttachment, err error) {
+	defer metrics.ObserveProviderCall(w.Name, "ListVolumeAttachments", time.Now(), &err)
+	return w.InnerProvider.ListVolumeAttachments(serverID)
+}
+
+// DeleteVolumeAttachment ...
+func (w MetricsProvider) DeleteVolumeAttachment(serverID, id string) (err error) {
+	defer metrics.ObserveProviderCall(w.Name, "DeleteVolumeAttachment", time.Now(), &err)
+	return w.InnerProvider.DeleteVolumeAttachment(serverID, id)
+}
+
+// GetCapabilities ...
+func (w MetricsProvider) GetCapabilities() providers.Capabilities {
+	return w.InnerProvider.GetCapabilities()
+}
@@ -283,6 +283,56 @@ func (w ErrorTraceProvider) DeleteVIP(vip *resources.VirtualIP) (err error) {
 	return w.InnerProvider.DeleteVIP(vip)
 }
 
+// AllocatePublicIP reserves a new public IP address
+func (w ErrorTraceProvider) AllocatePublicIP(name string) (_ *resources.PublicIP, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:AllocatePublicIP", w.Name))
+	return w.InnerProvider.AllocatePublicIP(name)
+}
+
+// ListPublicIPs lists the public IP addresses reserved on the tenant
+func (w ErrorTraceProvider) ListPublicIPs() (_ []*resources.PublicIP, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:ListPublicIPs", w.Name))
+	return w.InnerProvider.ListPublicIPs()
+}
+
+// AttachPublicIP binds the public IP address to a host
+func (w ErrorTraceProvider) AttachPublicIP(ip *resources.PublicIP, hostID string) (err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:AttachPublicIP", w.Name))
+	return w.InnerProvider.AttachPublicIP(ip, hostID)
+}
+
+// DetachPublicIP unbinds the public IP address from its host
+func (w ErrorTraceProvider) DetachPublicIP(ip *resources.PublicIP) (err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:DetachPublicIP", w.Name))
+	return w.InnerProvider.DetachPublicIP(ip)
+}
+
+// ReleasePublicIP gives back the public IP address to the provider
+func (w ErrorTraceProvider) ReleasePublicIP(ip *resources.PublicIP) (err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:ReleasePublicIP", w.Name))
+	return w.InnerProvider.ReleasePublicIP(ip)
+}
+
 // CreateHost ...
 func (w ErrorTraceProvider) CreateHost(request resources.HostRequest) (_ *resources.Host, _ *userdata.Content, err error) {
 	defer func(prefix string) {
@@ -383,6 +433,26 @@ func (w ErrorTraceProvider) ResizeHost(id string, request resources.SizingRequir
 	return w.InnerProvider.ResizeHost(id, request)
 }
 
+// GetHostConsoleOutput ...
+func (w ErrorTraceProvider) GetHostConsoleOutput(id string) (_ string, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:GetHostConsoleOutput", w.Name))
+	return w.InnerProvider.GetHostConsoleOutput(id)
+}
+
+// IsHostReclaimed ...
+func (w ErrorTraceProvider) IsHostReclaimed(id string) (_ bool, err error) {
+	defer func(prefix string) {
+		if err != nil {
+			logrus.Warnf("%s : Intercepted error: %v", prefix, err)
+		}
+	}(fmt.Sprintf("%s:IsHostReclaimed", w.Name))
+	return w.InnerProvider.IsHostReclaimed(id)
+}
+
 // CreateVolume ...
 func (w ErrorTraceProvider) CreateVolume(request resources.VolumeRequest) (_ *resources.Volume, err error) {
 	defer func(prefix string) {
@@ -0,0 +1,350 @@
+package api
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
+)
+
+// TracingProvider creates a span for each call made to the inner provider, as child of the span carried by ctx
+type TracingProvider struct {
+	InnerProvider Provider
+	Name          string
+	ctx           context.Context
+}
+
+// startSpan starts the span of the call to 'method'
+func (w TracingProvider) startSpan(method string) trace.Span {
+	_, span := tracing.StartSpan(w.ctx, w.Name+":"+method)
+	return span
+}
+
+// Provider specific functions
+
+// Build ...
+func (w TracingProvider) Build(something map[string]interface{}) (p Provider, err error) {
+	defer tracing.EndSpan(w.startSpan("Build"), &err)
+	return w.InnerProvider.Build(something)
+}
+
+// ListImages ...
+func (w TracingProvider) ListImages(all bool) (images []resources.Image, err error) {
+	defer tracing.EndSpan(w.startSpan("ListImages"), &err)
+	return w.InnerProvider.ListImages(all)
+}
+
+// ListTemplates ...
+func (w TracingProvider) ListTemplates(all bool) (templates []resources.HostTemplate, err error) {
+	defer tracing.EndSpan(w.startSpan("ListTemplates"), &err)
+	return w.InnerProvider.ListTemplates(all)
+}
+
+// GetAuthenticationOptions ...
+func (w TracingProvider) GetAuthenticationOptions() (cfg providers.Config, err error) {
+	defer tracing.EndSpan(w.startSpan("GetAuthenticationOptions"), &err)
+	return w.InnerProvider.GetAuthenticationOptions()
+}
+
+// GetConfigurationOptions ...
+func (w TracingProvider) GetConfigurationOptions() (cfg providers.Config, err error) {
+	defer tracing.EndSpan(w.startSpan("GetConfigurationOptions"), &err)
+	return w.InnerProvider.GetConfigurationOptions()
+}
+
+// GetName ...
+func (w TracingProvider) GetName() string {
+	return w.InnerProvider.GetName()
+}
+
+// GetTenantParameters ...
+func (w TracingProvider) GetTenantParameters() map[string]interface{} {
+	return w.InnerProvider.GetTenantParameters()
+}
+
+// Stack specific functions
+
+// NewTracingProvider wraps innerProvider to trace its calls in the context ctx
+func NewTracingProvider(ctx context.Context, innerProvider Provider, name string) *TracingProvider {
+	return &TracingProvider{InnerProvider: innerProvider, Name: name, ctx: ctx}
+}
+
+// ListAvailabilityZones ...
+func (w TracingProvider) ListAvailabilityZones() (zones map[string]bool, err error) {
+	defer tracing.EndSpan(w.startSpan("ListAvailabilityZones"), &err)
+	return w.InnerProvider.ListAvailabilityZones()
+}
+
+// ListRegions ...
+func (w TracingProvider) ListRegions() (regions []string, err error) {
+	defer tracing.EndSpan(w.startSpan("ListRegions"), &err)
+	return w.InnerProvider.ListRegions()
+}
+
+// GetImage ...
+func (w TracingProvider) GetImage(id string) (images *resources.Image, err error) {
+	defer tracing.EndSpan(w.startSpan("GetImage"), &err)
+	return w.InnerProvider.GetImage(id)
+}
+
+// GetTemplate ...
+func (w TracingProvider) GetTemplate(id string) (templates *resources.HostTemplate, err error) {
+	defer tracing.EndSpan(w.startSpan("GetTemplate"), &err)
+	return w.InnerProvider.GetTemplate(id)
+}
+
+// CreateKeyPair ...
+func (w TracingProvider) CreateKeyPair(name string) (pairs *resources.KeyPair, err error) {
+	defer tracing.EndSpan(w.startSpan("CreateKeyPair"), &err)
+	return w.InnerProvider.CreateKeyPair(name)
+}
+
+// GetKeyPair ...
+func (w TracingProvider) GetKeyPair(id string) (pairs *resources.KeyPair, err error) {
+	defer tracing.EndSpan(w.startSpan("GetKeyPair"), &err)
+	return w.InnerProvider.GetKeyPair(id)
+}
+
+// ListKeyPairs ...
+func (w TracingProvider) ListKeyPairs() (pairs []resources.KeyPair, err error) {
+	defer tracing.EndSpan(w.startSpan("ListKeyPairs"), &err)
+	return w.InnerProvider.ListKeyPairs()
+}
+
+// DeleteKeyPair ...
+func (w TracingProvider) DeleteKeyPair(id string) (err error) {
+	defer tracing.EndSpan(w.startSpan("DeleteKeyPair"), &err)
+	return w.InnerProvider.DeleteKeyPair(id)
+}
+
+// CreateNetwork ...
+func (w TracingProvider) CreateNetwork(req resources.NetworkRequest) (net *resources.Network, err error) {
+	defer tracing.EndSpan(w.startSpan("CreateNetwork"), &err)
+	return w.InnerProvider.CreateNetwork(req)
+}
+
+// GetNetwork ...
+func (w TracingProvider) GetNetwork(id string) (net *resources.Network, err error) {
+	defer tracing.EndSpan(w.startSpan("GetNetwork"), &err)
+	return w.InnerProvider.GetNetwork(id)
+}
+
+// GetNetworkByName ...
+func (w TracingProvider) GetNetworkByName(name string) (net *resources.Network, err error) {
+	defer tracing.EndSpan(w.startSpan("GetNetworkByName"), &err)
+	return w.InnerProvider.GetNetworkByName(name)
+}
+
+// ListNetworks ...
+func (w TracingProvider) ListNetworks() (net []*resources.Network, err error) {
+	defer tracing.EndSpan(w.startSpan("ListNetworks"), &err)
+	return w.InnerProvider.ListNetworks()
+}
+
+// DeleteNetwork ...
+func (w TracingProvider) DeleteNetwork(id string) (err error) {
+	defer tracing.EndSpan(w.startSpan("DeleteNetwork"), &err)
+	return w.InnerProvider.DeleteNetwork(id)
+}
+
+// CreateGateway ...
+func (w TracingProvider) CreateGateway(req resources.GatewayRequest, sizing *resources.SizingRequirements) (host *resources.Host, content *userdata.Content, err error) {
+	defer tracing.EndSpan(w.startSpan("CreateGateway"), &err)
+	return w.InnerProvider.CreateGateway(req, sizing)
+}
+
+// DeleteGateway ...
+func (w TracingProvider) DeleteGateway(networkID string) (err error) {
+	defer tracing.EndSpan(w.startSpan("DeleteGateway"), &err)
+	return w.InnerProvider.DeleteGateway(networkID)
+}
+
+// CreateVIP ...
+func (w TracingProvider) CreateVIP(networkID string, description string) (_ *resources.VirtualIP, err error) {
+	defer tracing.EndSpan(w.startSpan("CreateVIP"), &err)
+	return w.InnerProvider.CreateVIP(networkID, description)
+}
+
+// AddPublicIPToVIP adds a public IP to VIP
+func (w TracingProvider) AddPublicIPToVIP(vip *resources.VirtualIP) (err error) {
+	defer tracing.EndSpan(w.startSpan("AddPublicIPToVIP"), &err)
+	return w.InnerProvider.AddPublicIPToVIP(vip)
+}
+
+// BindHostToVIP makes the host passed as parameter an allowed "target" of the VIP
+func (w TracingProvider) BindHostToVIP(vip *resources.VirtualIP, hostID string) (err error) {
+	defer tracing.EndSpan(w.startSpan("BindHostToVIP"), &err)
+	return w.InnerProvider.BindHostToVIP(vip, hostID)
+}
+
+// UnbindHostFromVIP removes the bind between the VIP and a host
+func (w TracingProvider) UnbindHostFromVIP(vip *resources.VirtualIP, hostID string) (err error) {
+	defer tracing.EndSpan(w.startSpan("UnbindHostFromVIP"), &err)
+	return w.InnerProvider.UnbindHostFromVIP(vip, hostID)
+}
+
+// DeleteVIP deletes the port corresponding to the VIP
+func (w TracingProvider) DeleteVIP(vip *resources.VirtualIP) (err error) {
+	defer tracing.EndSpan(w.startSpan("DeleteVIP"), &err)
+	return w.InnerProvider.DeleteVIP(vip)
+}
+
+// AllocatePublicIP reserves a new public IP address
+func (w TracingProvider) AllocatePublicIP(name string) (_ *resources.PublicIP, err error) {
+	defer tracing.EndSpan(w.startSpan("AllocatePublicIP"), &err)
+	return w.InnerProvider.AllocatePublicIP(name)
+}
+
+// ListPublicIPs lists the public IP addresses reserved on the tenant
+func (w TracingProvider) ListPublicIPs() (_ []*resources.PublicIP, err error) {
+	defer tracing.EndSpan(w.startSpan("ListPublicIPs"), &err)
+	return w.InnerProvider.ListPublicIPs()
+}
+
+// AttachPublicIP binds the public IP address to a host
+func (w TracingProvider) AttachPublicIP(ip *resources.PublicIP, hostID string) (err error) {
+	defer tracing.EndSpan(w.startSpan("AttachPublicIP"), &err)
+	return w.InnerProvider.AttachPublicIP(ip, hostID)
+}
+
+// DetachPublicIP unbinds the public IP address from its host
+func (w TracingProvider) DetachPublicIP(ip *resources.PublicIP) (err error) {
+	defer tracing.EndSpan(w.startSpan("DetachPublicIP"), &err)
+	return w.InnerProvider.DetachPublicIP(ip)
+}
+
+// ReleasePublicIP gives back the public IP address to the provider
+func (w TracingProvider) ReleasePublicIP(ip *resources.PublicIP) (err error) {
+	defer tracing.EndSpan(w.startSpan("ReleasePublicIP"), &err)
+	return w.InnerProvider.ReleasePublicIP(ip)
+}
+
+// CreateHost ...
+func (w TracingProvider) CreateHost(request resources.HostRequest) (_ *resources.Host, _ *userdata.Content, err error) {
+	defer tracing.EndSpan(w.startSpan("CreateHost"), &err)
+	return w.InnerProvider.CreateHost(request)
+}
+
+// InspectHost ...
+func (w TracingProvider) InspectHost(something interface{}) (_ *resources.Host, err error) {
+	defer tracing.EndSpan(w.startSpan("InspectHost"), &err)
+	return w.InnerProvider.InspectHost(something)
+}
+
+// GetHostByName ...
+func (w TracingProvider) GetHostByName(name string) (_ *resources.Host, err error) {
+	defer tracing.EndSpan(w.startSpan("GetHostByName"), &err)
+	return w.InnerProvider.GetHostByName(name)
+}
+
+// GetHostState ...
+func (w TracingProvider) GetHostState(something interface{}) (_ hoststate.Enum, err error) {
+	defer tracing.EndSpan(w.startSpan("GetHostState"), &err)
+	return w.InnerProvider.GetHostState(something)
+}
+
+// ListHosts ...
+func (w TracingProvider) ListHosts() (_ []*resources.Host, err error) {
+	defer tracing.EndSpan(w.startSpan("ListHosts"), &err)
+	return w.InnerProvider.ListHosts()
+}
+
+// DeleteHost ...
+func (w TracingProvider) DeleteHost(id string) (err error) {
+	defer tracing.EndSpan(w.startSpan("DeleteHost"), &err)
+	return w.InnerProvider.DeleteHost(id)
+}
+
+// StopHost ...
+func (w TracingProvider) StopHost(id string) (err error) {
+	defer tracing.EndSpan(w.startSpan("StopHost"), &err)
+	return w.InnerProvider.StopHost(id)
+}
+
+// StartHost ...
+func (w TracingProvider) StartHost(id string) (err error) {
+	defer tracing.EndSpan(w.startSpan("StartHost"), &err)
+	return w.InnerProvider.StartHost(id)
+}
+
+// RebootHost ...
+func (w TracingProvider) RebootHost(id string) (err error) {
+	defer tracing.EndSpan(w.startSpan("RebootHost"), &err)
+	return w.InnerProvider.RebootHost(id)
+}
+
+// ResizeHost ...
+func (w TracingProvider) ResizeHost(id string, request resources.SizingRequirements) (_ *resources.Host, err error) {
+	defer tracing.EndSpan(w.startSpan("ResizeHost"), &err)
+	return w.InnerProvider.ResizeHost(id, request)
+}
+
+// GetHostConsoleOutput ...
+func (w TracingProvider) GetHostConsoleOutput(id string) (_ string, err error) {
+	defer tracing.EndSpan(w.startSpan("GetHostConsoleOutput"), &err)
+	return w.InnerProvider.GetHostConsoleOutput(id)
+}
+
+// IsHostReclaimed ...
+func (w TracingProvider) IsHostReclaimed(id string) (_ bool, err error) {
+	defer tracing.EndSpan(w.startSpan("IsHostReclaimed"), &err)
+	return w.InnerProvider.IsHostReclaimed(id)
+}
+
+// CreateVolume ...
+func (w TracingProvider) CreateVolume(request resources.VolumeRequest) (_ *resources.Volume, err error) {
+	defer tracing.EndSpan(w.startSpan("CreateVolume"), &err)
+	return w.InnerProvider.CreateVolume(request)
+}
+
+// GetVolume ...
+func (w TracingProvider) GetVolume(id string) (_ *resources.Volume, err error) {
+	defer tracing.EndSpan(w.startSpan("GetVolume"), &err)
+	return w.InnerProvider.GetVolume(id)
+}
+
+// ListVolumes ...
+func (w TracingProvider) ListVolumes() (_ []resources.Volume, err error) {
+	defer tracing.EndSpan(w.startSpan("ListVolumes"), &err)
+	return w.InnerProvider.ListVolumes()
+}
+
+// DeleteVolume ...
+func (w TracingProvider) DeleteVolume(id string) (err error) {
+	defer tracing.EndSpan(w.startSpan("DeleteVolume"), &err)
+	return w.InnerProvider.DeleteVolume(id)
+}
+
+// CreateVolumeAttachment ...
+func (w TracingProvider) CreateVolumeAttachment(request resources.VolumeAttachmentRequest) (_ string, err error) {
+	defer tracing.EndSpan(w.startSpan("CreateVolumeAttachment"), &err)
+	return w.InnerProvider.CreateVolumeAttachment(request)
+}
+
+// GetVolumeAttachment ...
+func (w TracingProvider) GetVolumeAttachment(serverID, id string) (_ *resources.VolumeAttachment, err error) {
+	defer tracing.EndSpan(w.startSpan("GetVolumeAttachment"), &err)
+	return w.InnerProvider.GetVolumeAttachment(serverID, id)
+}
+
+// ListVolumeAttachments ...
+func (w TracingProvider) ListVolumeAttachments(serverID string) (_ []resources.VolumeAttachment, err error) {
+	defer tracing.EndSpan(w.startSpan("ListVolumeAttachments"), &err)
+	return w.InnerProvider.ListVolumeAttachments(serverID)
+}
+
+// DeleteVolumeAttachment ...
+func (w TracingProvider) DeleteVolumeAttachment(serverID, id string) (err error) {
+	defer tracing.EndSpan(w.startSpan("DeleteVolumeAttachment"), &err)
+	return w.InnerProvider.DeleteVolumeAttachment(serverID, id)
+}
+
+// GetCapabilities ...
+func (w TracingProvider) GetCapabilities() providers.Capabilities {
+	return w.InnerProvider.GetCapabilities()
+}
@@ -32,6 +32,46 @@ func (w ValidatedProvider) DeleteVIP(*resources.VirtualIP) error {
 	return scerr.NotImplementedError("DeleteVIP() not implemented yet") // FIXME Technical debt
 }
 
+// AllocatePublicIP ...
+func (w ValidatedProvider) AllocatePublicIP(name string) (*resources.PublicIP, error) {
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+	return w.InnerProvider.AllocatePublicIP(name)
+}
+
+// ListPublicIPs ...
+func (w ValidatedProvider) ListPublicIPs() ([]*resources.PublicIP, error) {
+	return w.InnerProvider.ListPublicIPs()
+}
+
+// AttachPublicIP ...
+func (w ValidatedProvider) AttachPublicIP(ip *resources.PublicIP, hostID string) error {
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+	return w.InnerProvider.AttachPublicIP(ip, hostID)
+}
+
+// DetachPublicIP ...
+func (w ValidatedProvider) DetachPublicIP(ip *resources.PublicIP) error {
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+	return w.InnerProvider.DetachPublicIP(ip)
+}
+
+// ReleasePublicIP ...
+func (w ValidatedProvider) ReleasePublicIP(ip *resources.PublicIP) error {
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+	return w.InnerProvider.ReleasePublicIP(ip)
+}
+
 func (w ValidatedProvider) GetCapabilities() providers.Capabilities {
 	return w.InnerProvider.GetCapabilities()
 }
@@ -337,6 +377,16 @@ func (w ValidatedProvider) ResizeHost(id string, request resources.SizingRequire
 	return res, err
 }
 
+// GetHostConsoleOutput ...
+func (w ValidatedProvider) GetHostConsoleOutput(id string) (string, error) {
+	return w.InnerProvider.GetHostConsoleOutput(id)
+}
+
+// IsHostReclaimed ...
+func (w ValidatedProvider) IsHostReclaimed(id string) (bool, error) {
+	return w.InnerProvider.IsHostReclaimed(id)
+}
+
 // CreateVolume ...
 func (w ValidatedProvider) CreateVolume(request resources.VolumeRequest) (res *resources.Volume, err error) {
 	res, err = w.InnerProvider.CreateVolume(request)
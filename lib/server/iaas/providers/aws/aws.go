@@ -244,6 +244,8 @@ func (p *provider) ListTemplates(all bool) ([]resources.HostTemplate, error) {
 func (p *provider) GetCapabilities() providers.Capabilities {
 	return providers.Capabilities{
 		PrivateVirtualIP: true,
+		IPv6:             true,
+		SpotHosts:        true,
 	}
 }
 
@@ -24,4 +24,8 @@ type Capabilities struct {
 	PrivateVirtualIP bool
 	// Layer3Networking indicates if the provider uses Layer3 networking
 	Layer3Networking bool
+	// IPv6 indicates if the provider can create IPv6 or dual-stack networks
+	IPv6 bool
+	// SpotHosts indicates if the provider can create hosts that may be reclaimed without notice (spot, preemptible, ...)
+	SpotHosts bool
 }
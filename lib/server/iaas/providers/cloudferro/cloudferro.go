@@ -243,6 +243,7 @@ func (p *provider) GetTenantParameters() map[string]interface{} {
 func (p *provider) GetCapabilities() providers.Capabilities {
 	return providers.Capabilities{
 		PrivateVirtualIP: true,
+		IPv6:             true,
 	}
 }
 
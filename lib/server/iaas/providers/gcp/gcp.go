@@ -198,7 +198,10 @@ func (p *provider) GetTenantParameters() map[string]interface{} {
 
 // GetCapabilities returns the capabilities of the provider
 func (p *provider) GetCapabilities() providers.Capabilities {
-	return providers.Capabilities{}
+	return providers.Capabilities{
+		IPv6:      true,
+		SpotHosts: true,
+	}
 }
 
 func init() {
@@ -127,6 +127,21 @@ func (provider *provider) UnbindHostFromVIP(vip *resources.VirtualIP, hostID str
 func (provider *provider) DeleteVIP(vip *resources.VirtualIP) error {
 	return fmt.Errorf(errorStr)
 }
+func (provider *provider) AllocatePublicIP(name string) (*resources.PublicIP, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) ListPublicIPs() ([]*resources.PublicIP, error) {
+	return nil, fmt.Errorf(errorStr)
+}
+func (provider *provider) AttachPublicIP(ip *resources.PublicIP, hostID string) error {
+	return fmt.Errorf(errorStr)
+}
+func (provider *provider) DetachPublicIP(ip *resources.PublicIP) error {
+	return fmt.Errorf(errorStr)
+}
+func (provider *provider) ReleasePublicIP(ip *resources.PublicIP) error {
+	return fmt.Errorf(errorStr)
+}
 
 func (provider *provider) CreateHost(request resources.HostRequest) (*resources.Host, *userdata.Content, error) {
 	return nil, nil, fmt.Errorf(errorStr)
@@ -158,6 +173,12 @@ func (provider *provider) StopHost(id string) error {
 func (provider *provider) RebootHost(id string) error {
 	return fmt.Errorf(errorStr)
 }
+func (provider *provider) GetHostConsoleOutput(id string) (string, error) {
+	return "", fmt.Errorf(errorStr)
+}
+func (provider *provider) IsHostReclaimed(id string) (bool, error) {
+	return false, fmt.Errorf(errorStr)
+}
 
 func (provider *provider) CreateVolume(request resources.VolumeRequest) (*resources.Volume, error) {
 	return nil, fmt.Errorf(errorStr)
@@ -191,6 +191,8 @@ func (p *provider) GetCapabilities() providers.Capabilities {
 		PublicVirtualIP:  false,
 		PrivateVirtualIP: true,
 		Layer3Networking: false,
+		// Outscale API has no spot or preemptible VMs
+		SpotHosts: false,
 	}
 }
 
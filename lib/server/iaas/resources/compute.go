@@ -34,14 +34,17 @@ type KeyPair struct {
 
 // SizingRequirements represents host sizing requirements to fulfil
 type SizingRequirements struct {
-	MinCores    int     `json:"min_cores,omitempty"`
-	MaxCores    int     `json:"max_cores,omitempty"`
-	MinRAMSize  float32 `json:"min_ram_size,omitempty"`
-	MaxRAMSize  float32 `json:"max_ram_size,omitempty"`
-	MinDiskSize int     `json:"min_disk_size,omitempty"`
-	MinGPU      int     `json:"min_gpu,omitempty"`
-	MinFreq     float32 `json:"min_freq,omitempty"`
-	Replaceable bool    `json:"replaceable,omitempty"` // Tells if we accept server that could be removed without notice (AWS proposes such kind of server with SPOT
+	MinCores     int     `json:"min_cores,omitempty"`
+	MaxCores     int     `json:"max_cores,omitempty"`
+	MinRAMSize   float32 `json:"min_ram_size,omitempty"`
+	MaxRAMSize   float32 `json:"max_ram_size,omitempty"`
+	MinDiskSize  int     `json:"min_disk_size,omitempty"`
+	MinGPU       int     `json:"min_gpu,omitempty"`
+	MinFreq      float32 `json:"min_freq,omitempty"`
+	MinDiskSpeed float32 `json:"min_disk_speed,omitempty"` // in MB/s, as measured by the scanner
+	MinNetSpeed  float32 `json:"min_net_speed,omitempty"`  // in KB/s, as measured by the scanner
+	Replaceable  bool    `json:"replaceable,omitempty"`    // Tells if we accept server that could be removed without notice (AWS proposes such kind of server with SPOT
+	Cheapest     bool    `json:"cheapest,omitempty"`       // Tells to select the cheapest template fulfilling the requirements instead of the smallest one
 }
 
 // StoredCPUInfo ...
@@ -94,6 +97,10 @@ type HostRequest struct {
 	DefaultRouteIP string
 	// DefaultGateway is the host used as default gateway
 	DefaultGateway *Host
+	// DNSServers lists the DNS servers to use before the ones of the tenant (the internal DNS of the network for example)
+	DNSServers []string
+	// DNSDomain is the domain searched by the resolver of the host
+	DNSDomain string
 	// PublicIP a flag telling if the host must have a public IP
 	PublicIP bool
 	// TemplateID is the UUID of the template used to size the host (see SelectTemplates)
@@ -108,19 +115,20 @@ type HostRequest struct {
 	DiskSize int
 	// Use spot instance
 	Spot bool
+	// UserData contains a script supplied by the user, run in phase3 once the host is configured by SafeScale
+	UserData string
 }
 
 // HostDefinition ...
 type HostDefinition struct {
-	Cores     int     `json:"cores,omitempty"`
-	RAMSize   float32 `json:"ram_size,omitempty"`
-	DiskSize  int     `json:"disk_size,omitempty"`
-	GPUNumber int     `json:"gpu_number,omitempty"`
-	GPUType   string  `json:"gpu_type,omitempty"`
-	CPUFreq   float32 `json:"cpu_freq,omitempty"`
-	ImageID   string  `json:"image_id,omitempty"`
-	// TODO: implement the handling of this field (will need to introduce provider capabilities to know if a specific provider allows this kind of host)
-	Replaceable bool `json:"replaceable,omitempty"` // Tells if we accept server that could be removed without notice (AWS proposes such kind of server with SPOT
+	Cores       int     `json:"cores,omitempty"`
+	RAMSize     float32 `json:"ram_size,omitempty"`
+	DiskSize    int     `json:"disk_size,omitempty"`
+	GPUNumber   int     `json:"gpu_number,omitempty"`
+	GPUType     string  `json:"gpu_type,omitempty"`
+	CPUFreq     float32 `json:"cpu_freq,omitempty"`
+	ImageID     string  `json:"image_id,omitempty"`
+	Replaceable bool    `json:"replaceable,omitempty"` // Tells if we accept server that could be removed without notice (AWS proposes such kind of server with SPOT
 }
 
 // HostTemplate ...
@@ -133,6 +141,8 @@ type HostTemplate struct {
 	CPUFreq   float32 `json:"cpu_freq,omitempty"`
 	ID        string  `json:"id,omitempty"`
 	Name      string  `json:"name,omitempty"`
+	// PricePerHour is the price of a host of this template per hour (0 if unknown), given by the provider or by the price file of the tenant
+	PricePerHour float64 `json:"price_per_hour,omitempty"`
 }
 
 // Host contains the information about a host
@@ -214,17 +224,15 @@ func (h *Host) GetPrivateIP() string {
 	var ip string
 	err := h.Properties.LockForRead(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
 		hostNetworkV1 := clonable.(*propsv1.HostNetwork)
-		if len(hostNetworkV1.IPv4Addresses) > 0 {
-			ip = hostNetworkV1.IPv4Addresses[hostNetworkV1.DefaultNetworkID]
-			if ip == "" {
-				ip = hostNetworkV1.IPv6Addresses[hostNetworkV1.DefaultNetworkID]
-			}
-			if ip == "" { // FIXME AWS Fix for subnetworks
-				for _, value := range hostNetworkV1.IPv4Addresses {
-					if value != "" {
-						ip = value
-						break
-					}
+		ip = hostNetworkV1.IPv4Addresses[hostNetworkV1.DefaultNetworkID]
+		if ip == "" {
+			ip = hostNetworkV1.IPv6Addresses[hostNetworkV1.DefaultNetworkID]
+		}
+		if ip == "" { // FIXME AWS Fix for subnetworks
+			for _, value := range hostNetworkV1.IPv4Addresses {
+				if value != "" {
+					ip = value
+					break
 				}
 			}
 		}
@@ -31,4 +31,6 @@ const (
 	SharesV1 = "6"
 	// MountsV1 contains optional additional info about mounted devices (locally attached or remote filesystem)
 	MountsV1 = "7"
+	// LifecycleV1 contains optional lifecycle policies of the host (expiration, stop when idle)
+	LifecycleV1 = "8"
 )
@@ -31,8 +31,20 @@ const (
 	IPv4 Enum = 4
 	// IPv6 is IP v6 version
 	IPv6 Enum = 6
+	// DualStack is IP v4 and IP v6 versions together
+	DualStack Enum = 46
 )
 
+// HasIPv4 tells if the version includes IP v4
+func (i Enum) HasIPv4() bool {
+	return i == IPv4 || i == DualStack
+}
+
+// HasIPv6 tells if the version includes IP v6
+func (i Enum) HasIPv6() bool {
+	return i == IPv6 || i == DualStack
+}
+
 // Is checks the version of a IP address in string representaiton
 func (i Enum) Is(str string) bool {
 	ip := net.ParseIP(str)
@@ -42,6 +54,8 @@ func (i Enum) Is(str string) bool {
 		return !isV6
 	case IPv6:
 		return isV6
+	case DualStack:
+		return ip != nil
 	default:
 		return false
 	}
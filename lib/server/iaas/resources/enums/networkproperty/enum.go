@@ -21,4 +21,10 @@ const (
 	DescriptionV1 = "1"
 	// HostsV1 contains list of hosts attached to the network
 	HostsV1 = "2"
+	// PeeringsV1 contains the list of networks peered with the network
+	PeeringsV1 = "3"
+	// VPNV1 contains the site-to-site VPN configuration of the gateway(s) of the network
+	VPNV1 = "4"
+	// DNSV1 contains the records served by the internal DNS of the network
+	DNSV1 = "5"
 )
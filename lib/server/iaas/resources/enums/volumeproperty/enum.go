@@ -21,4 +21,6 @@ const (
 	DescriptionV1 = "1"
 	// AttachedV1 contains additional information about hosts attaching the volume
 	AttachedV1 = "2"
+	// EncryptionV1 contains the key of an encrypted volume
+	EncryptionV1 = "3"
 )
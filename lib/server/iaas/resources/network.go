@@ -19,6 +19,7 @@ package resources
 import (
 	"github.com/sirupsen/logrus"
 
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ipversion"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/serialize"
@@ -41,10 +42,12 @@ type GatewayRequest struct {
 // like "192.0.2.0/24" or "2001:db8::/32", as defined in RFC 4632 and RFC 4291.
 type NetworkRequest struct {
 	Name string
-	// IPVersion must be IPv4 or IPv6 (see IPVersion)
+	// IPVersion must be IPv4, IPv6 or DualStack (see IPVersion)
 	IPVersion ipversion.Enum
 	// CIDR mask
 	CIDR string
+	// IPv6CIDR is the IPv6 prefix of a dual-stack network; if empty, the provider chooses one
+	IPv6CIDR string
 	// DNSServers
 	DNSServers []string
 	// Domain contains the domain used to define host FQDN attached to the network
@@ -63,11 +66,13 @@ type Network struct {
 	ID                 string                    `json:"id,omitempty"`                   // ID for the network (from provider)
 	Name               string                    `json:"name,omitempty"`                 // Name of the network
 	CIDR               string                    `json:"mask,omitempty"`                 // network in CIDR notation
+	IPv6CIDR           string                    `json:"ipv6_cidr,omitempty"`            // IPv6 prefix of the network in CIDR notation, if any
 	Domain             string                    `json:"domain,omitempty"`               // contains the domain used to define host FQDN
 	GatewayID          string                    `json:"gateway_id,omitempty"`           // contains the id of the host acting as primary gateway for the network
 	SecondaryGatewayID string                    `json:"secondary_gateway_id,omitempty"` // contains the id of the host acting as secondary gateway for the network
+	GatewayImageID     string                    `json:"gateway_image_id,omitempty"`     // contains the id of the image used to create the gateway(s), reused to recreate them
 	VIP                *VirtualIP                `json:"vip,omitempty"`                  // contains the VIP of the network if created with HA
-	IPVersion          ipversion.Enum            `json:"ip_version,omitempty"`           // IPVersion is IPv4 or IPv6 (see IPVersion)
+	IPVersion          ipversion.Enum            `json:"ip_version,omitempty"`           // IPVersion is IPv4, IPv6 or DualStack (see IPVersion)
 	Properties         *serialize.JSONProperties `json:"properties,omitempty"`           // contains optional supplemental information
 
 	Subnetworks []SubNetwork `json:"subnetworks,omitempty"` // FIXME: comment!
@@ -131,6 +136,28 @@ func (n *Network) Deserialize(buf []byte) error {
 	return nil
 }
 
+// GatewayHealth describes the health of a gateway of a network, as checked by SafeScale
+type GatewayHealth struct {
+	ID        string         // ID of the gateway
+	Name      string         // Name of the gateway
+	Primary   bool           // Tells if the gateway is the primary gateway of the network
+	State     hoststate.Enum // State of the gateway reported by the provider
+	Probed    bool           // Tells if the gateway has been probed by SSH; if not, Reachable and HoldsVIP are unknown
+	Reachable bool           // Tells if the gateway answers by SSH
+	HoldsVIP  bool           // Tells if the gateway currently holds the VIP of the network (elected by keepalived)
+	Error     string         // Contains the reason why the gateway is not healthy, if any
+}
+
+// Healthy tells if the gateway is started and, if probed, reachable
+func (gh *GatewayHealth) Healthy() bool {
+	return gh.State == hoststate.STARTED && (gh.Reachable || !gh.Probed)
+}
+
+// Dead tells if the gateway can be recreated: it's not started at the provider and did not answer the SSH probes
+func (gh *GatewayHealth) Dead() bool {
+	return gh.Probed && !gh.Reachable && gh.State != hoststate.STARTED
+}
+
 // VirtualIP is a structure containing information needed to manage VIP (virtual IP)
 type VirtualIP struct {
 	ID        string
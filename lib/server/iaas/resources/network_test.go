@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/magiconair/properties/assert"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hoststate"
 )
 
 func TestVirtualIP_Clone(t *testing.T) {
@@ -29,3 +31,31 @@ func TestVirtualIP_Clone(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestGatewayHealth_Healthy(t *testing.T) {
+	gh := &GatewayHealth{State: hoststate.STARTED, Probed: true, Reachable: true}
+	assert.Equal(t, gh.Healthy(), true)
+
+	gh.Reachable = false
+	assert.Equal(t, gh.Healthy(), false)
+
+	gh = &GatewayHealth{State: hoststate.STOPPED, Probed: true, Reachable: true}
+	assert.Equal(t, gh.Healthy(), false)
+
+	gh = &GatewayHealth{State: hoststate.STARTED}
+	assert.Equal(t, gh.Healthy(), true)
+}
+
+func TestGatewayHealth_Dead(t *testing.T) {
+	gh := &GatewayHealth{State: hoststate.STARTED, Probed: true}
+	assert.Equal(t, gh.Dead(), false)
+
+	gh = &GatewayHealth{State: hoststate.ERROR, Probed: true, Reachable: true}
+	assert.Equal(t, gh.Dead(), false)
+
+	gh = &GatewayHealth{State: hoststate.TERMINATED}
+	assert.Equal(t, gh.Dead(), false)
+
+	gh.Probed = true
+	assert.Equal(t, gh.Dead(), true)
+}
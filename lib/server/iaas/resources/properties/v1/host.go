@@ -35,7 +35,10 @@ type HostDescription struct {
 	Updated time.Time `json:"modified,omitempty"` // tells the last time the host has been modified
 	Purpose string    `json:"purpose,omitempty"`  // contains a description of the use of a host
 	Tenant  string    `json:"tenant"`             // contains the tenant name used to create the host
-	Domain  string    `json:"domain,omitempty"` // contains the domain used to define host FQDN
+	Domain  string    `json:"domain,omitempty"`   // contains the domain used to define host FQDN
+	Spot    bool      `json:"spot,omitempty"`     // tells if the host may be reclaimed by the provider without notice
+	// AvailabilityZone contains the availability zone the host has been created in, if the provider has several
+	AvailabilityZone string `json:"availability_zone,omitempty"`
 }
 
 // NewHostDescription ...
@@ -515,15 +518,29 @@ func (hm *HostMounts) Replace(p data.Clonable) data.Clonable {
 // Note: if tagged as FROZEN, must not be changed ever.
 //       Create a new version instead with updated/additional fields
 type HostShare struct {
-	ID            string            `json:"id"`                        // ID ...
-	Name          string            `json:"name"`                      // the name of the share
-	Path          string            `json:"path"`                      // the path on the host filesystem that is shared
-	PathAcls      string            `json:"path_acls,omitempty"`       // filesystem acls to set on the exported folder
-	Type          string            `json:"type,omitempty"`            // export type is lowercase (ie. nfs, glusterfs, ...)
-	ShareAcls     string            `json:"share_acls,omitempty"`      // the acls to set on the share
-	ShareOptions  string            `json:"share_options,omitempty"`   // the options (other than acls) to set on the share
-	ClientsByID   map[string]string `json:"clients_by_id,omitempty"`   // contains the name of the hosts mounting the export, indexed by ID
-	ClientsByName map[string]string `json:"clients_by_name,omitempty"` // contains the ID of the hosts mounting the export, indexed by Name
+	ID            string                   `json:"id"`                        // ID ...
+	Name          string                   `json:"name"`                      // the name of the share
+	Path          string                   `json:"path"`                      // the path on the host filesystem that is shared
+	PathAcls      string                   `json:"path_acls,omitempty"`       // filesystem acls to set on the exported folder
+	Type          string                   `json:"type,omitempty"`            // export type is lowercase (ie. nfs, nfs4-krb5, smb, ...)
+	ShareAcls     string                   `json:"share_acls,omitempty"`      // the acls to set on the share
+	ShareOptions  string                   `json:"share_options,omitempty"`   // the options (other than acls) to set on the share
+	SecurityModes []string                 `json:"security_modes,omitempty"`  // the security modes of the share (ie. sys, krb5, krb5i, krb5p)
+	Password      string                   `json:"password,omitempty"`        // the password of the user allowed to mount the share (type smb, security mode sys)
+	ACLs          map[string]*HostShareACL `json:"acls,omitempty"`            // the hosts allowed to mount the share, indexed by host ID (every host if empty)
+	ClientsByID   map[string]string        `json:"clients_by_id,omitempty"`   // contains the name of the hosts mounting the export, indexed by ID
+	ClientsByName map[string]string        `json:"clients_by_name,omitempty"` // contains the ID of the hosts mounting the export, indexed by Name
+}
+
+// HostShareACL describes the access to a share given to a host
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with updated/additional fields
+type HostShareACL struct {
+	HostID   string `json:"host_id"`             // the ID of the host allowed
+	HostName string `json:"host_name"`           // the name of the host allowed
+	IP       string `json:"ip"`                  // the IP address the host is allowed from
+	ReadOnly bool   `json:"read_only,omitempty"` // tells if the host can only read the share
 }
 
 // NewHostShare creates a new struct HostShare
@@ -559,6 +576,16 @@ func (hs *HostShare) Clone() data.Clonable {
 func (hs *HostShare) Replace(p data.Clonable) data.Clonable {
 	src := p.(*HostShare)
 	*hs = *src
+	if src.SecurityModes != nil {
+		hs.SecurityModes = append([]string{}, src.SecurityModes...)
+	}
+	if src.ACLs != nil {
+		hs.ACLs = make(map[string]*HostShareACL, len(src.ACLs))
+		for k, v := range src.ACLs {
+			newV := *v
+			hs.ACLs[k] = &newV
+		}
+	}
 	hs.ClientsByID = make(map[string]string, len(src.ClientsByID))
 	for k, v := range src.ClientsByID {
 		hs.ClientsByID[k] = v
@@ -710,6 +737,51 @@ func (hf *HostFeatures) Replace(p data.Clonable) data.Clonable {
 	return hf
 }
 
+const (
+	// HostExpiryStop tells that an expired host is stopped
+	HostExpiryStop = "stop"
+	// HostExpiryDelete tells that an expired host is deleted
+	HostExpiryDelete = "delete"
+)
+
+// HostLifecycle contains the lifecycle policies of the host, enforced periodically by safescaled
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with updated/additional fields
+type HostLifecycle struct {
+	ExpiresAt    time.Time     `json:"expires_at,omitempty"`    // tells when the host expires; zero value if it never expires
+	OnExpiry     string        `json:"on_expiry,omitempty"`     // tells what is done with the host once expired (HostExpiryStop or HostExpiryDelete)
+	WarnedAt     time.Time     `json:"warned_at,omitempty"`     // tells when the users of the host have been warned of its expiration
+	IdleStop     time.Duration `json:"idle_stop,omitempty"`     // delay of inactivity after which the host is stopped; 0 if the host is never stopped
+	LastActivity time.Time     `json:"last_activity,omitempty"` // tells the last time the host has been seen active since it is started
+}
+
+// NewHostLifecycle ...
+func NewHostLifecycle() *HostLifecycle {
+	return &HostLifecycle{}
+}
+
+// Reset ...
+func (hl *HostLifecycle) Reset() {
+	*hl = HostLifecycle{}
+}
+
+// Content ...
+func (hl *HostLifecycle) Content() data.Clonable {
+	return hl
+}
+
+// Clone ...
+func (hl *HostLifecycle) Clone() data.Clonable {
+	return NewHostLifecycle().Replace(hl)
+}
+
+// Replace ...
+func (hl *HostLifecycle) Replace(p data.Clonable) data.Clonable {
+	*hl = *p.(*HostLifecycle)
+	return hl
+}
+
 func init() {
 	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.DescriptionV1, NewHostDescription())
 	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.NetworkV1, NewHostNetwork())
@@ -718,4 +790,5 @@ func init() {
 	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.VolumesV1, NewHostVolumes())
 	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.MountsV1, NewHostMounts())
 	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.FeaturesV1, NewHostFeatures())
+	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.LifecycleV1, NewHostLifecycle())
 }
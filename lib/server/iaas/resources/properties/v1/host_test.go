@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestHostDescription_Clone(t *testing.T) {
@@ -187,3 +188,23 @@ func TestHostFeatures_Clone(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestHostLifecycle_Clone(t *testing.T) {
+	ct := NewHostLifecycle()
+	ct.OnExpiry = HostExpiryStop
+	ct.IdleStop = 2 * time.Hour
+
+	clonedCt, ok := ct.Clone().(*HostLifecycle)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	clonedCt.OnExpiry = HostExpiryDelete
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
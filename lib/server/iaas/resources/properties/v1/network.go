@@ -111,7 +111,179 @@ func (nh *NetworkHosts) Replace(p data.Clonable) data.Clonable {
 	return nh
 }
 
+// NetworkPeering contains information about the peering of the network with another network
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental/overriding fields
+type NetworkPeering struct {
+	NetworkID     string    `json:"network_id"`                // ID of the peer network
+	NetworkName   string    `json:"network_name"`              // Name of the peer network
+	CIDR          string    `json:"cidr"`                      // CIDR of the peer network, routed through the peering
+	Mode          string    `json:"mode"`                      // Contains how the peering is done ("gateway" when routed by the gateways)
+	Interface     string    `json:"interface,omitempty"`       // Name of the tunnel interface on the gateway(s)
+	Port          int       `json:"port,omitempty"`            // UDP port listened by the tunnel on the gateway(s)
+	PrivateKey    string    `json:"private_key,omitempty"`     // Private key of the local end of the tunnel
+	PublicKey     string    `json:"public_key,omitempty"`      // Public key of the local end of the tunnel
+	PeerPublicKey string    `json:"peer_public_key,omitempty"` // Public key of the remote end of the tunnel
+	PeerEndpoint  string    `json:"peer_endpoint,omitempty"`   // Public address and port of the remote end of the tunnel
+	Created       time.Time `json:"created,omitempty"`         // Date of creation of the peering
+}
+
+// NetworkPeerings contains the peerings of the network
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental/overriding fields
+type NetworkPeerings struct {
+	ByID   map[string]*NetworkPeering `json:"by_id"`   // peerings indexed by peer network id
+	ByName map[string]string          `json:"by_name"` // peer network IDs, indexed by peer network name
+}
+
+// NewNetworkPeerings ...
+func NewNetworkPeerings() *NetworkPeerings {
+	return &NetworkPeerings{
+		ByID:   map[string]*NetworkPeering{},
+		ByName: map[string]string{},
+	}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (np *NetworkPeerings) Content() data.Clonable {
+	return np
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (np *NetworkPeerings) Clone() data.Clonable {
+	return NewNetworkPeerings().Replace(np)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (np *NetworkPeerings) Replace(p data.Clonable) data.Clonable {
+	src := p.(*NetworkPeerings)
+	np.ByID = make(map[string]*NetworkPeering, len(src.ByID))
+	for k, v := range src.ByID {
+		peering := *v
+		np.ByID[k] = &peering
+	}
+	np.ByName = make(map[string]string, len(src.ByName))
+	for k, v := range src.ByName {
+		np.ByName[k] = v
+	}
+	return np
+}
+
+// NetworkVPNPeer describes a remote site connected to the VPN of the network
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental/overriding fields
+type NetworkVPNPeer struct {
+	Name       string    `json:"name"`               // Name of the remote site
+	PublicKey  string    `json:"public_key"`         // WireGuard public key of the remote site
+	Endpoint   string    `json:"endpoint,omitempty"` // Public address and port of the remote site, if reachable
+	AllowedIPs []string  `json:"allowed_ips"`        // CIDRs of the remote site, routed through the VPN
+	Created    time.Time `json:"created,omitempty"`  // Date of creation of the peer
+}
+
+// NetworkVPN contains the site-to-site VPN configuration of the gateway(s) of the network
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental/overriding fields
+type NetworkVPN struct {
+	Interface  string                     `json:"interface,omitempty"`   // Name of the WireGuard interface on the gateway(s)
+	Port       int                        `json:"port,omitempty"`        // UDP port listened by the VPN on the gateway(s)
+	PrivateKey string                     `json:"private_key,omitempty"` // Private key of the VPN, shared by the gateways
+	PublicKey  string                     `json:"public_key,omitempty"`  // Public key of the VPN, to give to the remote sites
+	Endpoint   string                     `json:"endpoint,omitempty"`    // Public address and port of the VPN on gateway side
+	Peers      map[string]*NetworkVPNPeer `json:"peers"`                 // Remote sites, indexed by name
+}
+
+// NewNetworkVPN ...
+func NewNetworkVPN() *NetworkVPN {
+	return &NetworkVPN{
+		Peers: map[string]*NetworkVPNPeer{},
+	}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (nv *NetworkVPN) Content() data.Clonable {
+	return nv
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (nv *NetworkVPN) Clone() data.Clonable {
+	return NewNetworkVPN().Replace(nv)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (nv *NetworkVPN) Replace(p data.Clonable) data.Clonable {
+	src := p.(*NetworkVPN)
+	*nv = *src
+	nv.Peers = make(map[string]*NetworkVPNPeer, len(src.Peers))
+	for k, v := range src.Peers {
+		peer := *v
+		peer.AllowedIPs = make([]string, len(v.AllowedIPs))
+		copy(peer.AllowedIPs, v.AllowedIPs)
+		nv.Peers[k] = &peer
+	}
+	return nv
+}
+
+// NetworkDNS contains the records served by the internal DNS running on the gateway(s) of the network
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental/overriding fields
+type NetworkDNS struct {
+	Enabled bool              `json:"enabled,omitempty"` // Tells if the gateway(s) of the network run the DNS service
+	Domain  string            `json:"domain,omitempty"`  // Domain of the records, if any
+	Records map[string]string `json:"records"`           // IPv4 addresses of the hosts of the network, indexed by host name
+	Aliases map[string]string `json:"aliases"`           // Targets of the CNAME records, indexed by alias
+}
+
+// NewNetworkDNS ...
+func NewNetworkDNS() *NetworkDNS {
+	return &NetworkDNS{
+		Records: map[string]string{},
+		Aliases: map[string]string{},
+	}
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (nd *NetworkDNS) Content() data.Clonable {
+	return nd
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (nd *NetworkDNS) Clone() data.Clonable {
+	return NewNetworkDNS().Replace(nd)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (nd *NetworkDNS) Replace(p data.Clonable) data.Clonable {
+	src := p.(*NetworkDNS)
+	*nd = *src
+	nd.Records = make(map[string]string, len(src.Records))
+	for k, v := range src.Records {
+		nd.Records[k] = v
+	}
+	nd.Aliases = make(map[string]string, len(src.Aliases))
+	for k, v := range src.Aliases {
+		nd.Aliases[k] = v
+	}
+	return nd
+}
+
 func init() {
 	serialize.PropertyTypeRegistry.Register("resources.network", networkproperty.HostsV1, NewNetworkHosts())
 	serialize.PropertyTypeRegistry.Register("resources.network", networkproperty.DescriptionV1, NewNetworkDescription())
+	serialize.PropertyTypeRegistry.Register("resources.network", networkproperty.PeeringsV1, NewNetworkPeerings())
+	serialize.PropertyTypeRegistry.Register("resources.network", networkproperty.VPNV1, NewNetworkVPN())
+	serialize.PropertyTypeRegistry.Register("resources.network", networkproperty.DNSV1, NewNetworkDNS())
 }
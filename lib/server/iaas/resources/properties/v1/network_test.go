@@ -43,3 +43,64 @@ func TestNetworkHosts_Clone(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestNetworkPeerings_Clone(t *testing.T) {
+	ct := NewNetworkPeerings()
+	ct.ByID["id"] = &NetworkPeering{NetworkID: "id", NetworkName: "Never", CIDR: "192.168.1.0/24"}
+	ct.ByName["Never"] = "id"
+
+	clonedCt, ok := ct.Clone().(*NetworkPeerings)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	clonedCt.ByID["id"].CIDR = "192.168.2.0/24"
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
+
+func TestNetworkVPN_Clone(t *testing.T) {
+	ct := NewNetworkVPN()
+	ct.Port = 51800
+	ct.Peers["lab"] = &NetworkVPNPeer{Name: "lab", AllowedIPs: []string{"10.0.0.0/16"}}
+
+	clonedCt, ok := ct.Clone().(*NetworkVPN)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	clonedCt.Peers["lab"].AllowedIPs[0] = "10.1.0.0/16"
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
+
+func TestNetworkDNS_Clone(t *testing.T) {
+	ct := NewNetworkDNS()
+	ct.Enabled = true
+	ct.Records["myhost"] = "192.168.0.10"
+	ct.Aliases["db"] = "myhost"
+
+	clonedCt, ok := ct.Clone().(*NetworkDNS)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	clonedCt.Aliases["db"] = "otherhost"
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
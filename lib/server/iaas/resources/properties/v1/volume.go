@@ -33,6 +33,10 @@ type VolumeDescription struct {
 	Purpose string
 	// Created contains the time of creation of the volume
 	Created time.Time
+	// RAID contains the RAID level of a volume made of several provider volumes ("0", "1" or "10"), empty otherwise
+	RAID string `json:"raid,omitempty"`
+	// Members contains the IDs of the provider volumes making up a RAID volume, in the order of the array
+	Members []string `json:"members,omitempty"`
 }
 
 // NewVolumeDescription ...
@@ -55,7 +59,12 @@ func (vd *VolumeDescription) Clone() data.Clonable {
 // Replace ...
 // satisfies interface data.Clonable
 func (vd *VolumeDescription) Replace(p data.Clonable) data.Clonable {
-	*vd = *p.(*VolumeDescription)
+	src := p.(*VolumeDescription)
+	*vd = *src
+	if src.Members != nil {
+		vd.Members = make([]string, len(src.Members))
+		copy(vd.Members, src.Members)
+	}
 	return vd
 }
 
@@ -106,7 +115,47 @@ func (va *VolumeAttachments) Replace(p data.Clonable) data.Clonable {
 	return va
 }
 
+// VolumeEncryption contains the key of a volume encrypted with LUKS, escrowed in metadata to be able to
+// attach the volume to any host
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental/overriding fields
+type VolumeEncryption struct {
+	Key     string    `json:"key,omitempty"`     // contains the LUKS key, ciphered with the metadata key of the tenant and base64-encoded
+	Created time.Time `json:"created,omitempty"` // tells when the key has been generated
+}
+
+// NewVolumeEncryption ...
+func NewVolumeEncryption() *VolumeEncryption {
+	return &VolumeEncryption{}
+}
+
+// IsEncrypted tells if the volume has been encrypted
+func (ve *VolumeEncryption) IsEncrypted() bool {
+	return ve.Key != ""
+}
+
+// Content ...
+// satisfies interface data.Clonable
+func (ve *VolumeEncryption) Content() data.Clonable {
+	return ve
+}
+
+// Clone ...
+// satisfies interface data.Clonable
+func (ve *VolumeEncryption) Clone() data.Clonable {
+	return NewVolumeEncryption().Replace(ve)
+}
+
+// Replace ...
+// satisfies interface data.Clonable
+func (ve *VolumeEncryption) Replace(p data.Clonable) data.Clonable {
+	*ve = *p.(*VolumeEncryption)
+	return ve
+}
+
 func init() {
 	serialize.PropertyTypeRegistry.Register("resources.volume", volumeproperty.DescriptionV1, NewVolumeDescription())
 	serialize.PropertyTypeRegistry.Register("resources.volume", volumeproperty.AttachedV1, NewVolumeAttachments())
+	serialize.PropertyTypeRegistry.Register("resources.volume", volumeproperty.EncryptionV1, NewVolumeEncryption())
 }
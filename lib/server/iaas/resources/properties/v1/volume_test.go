@@ -9,6 +9,8 @@ import (
 func TestVolumeDescription_Clone(t *testing.T) {
 	ct := NewVolumeDescription()
 	ct.Purpose = "Never"
+	ct.RAID = "1"
+	ct.Members = []string{"Never", "Change"}
 
 	clonedCt, ok := ct.Clone().(*VolumeDescription)
 	if !ok {
@@ -23,6 +25,15 @@ func TestVolumeDescription_Clone(t *testing.T) {
 		t.Error("It's a shallow clone !")
 		t.Fail()
 	}
+
+	clonedCt.Purpose = ct.Purpose
+	clonedCt.Members[0] = "Other"
+
+	areEqual = reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
 }
 
 func TestVolumeAttachments_Clone(t *testing.T) {
@@ -43,3 +54,23 @@ func TestVolumeAttachments_Clone(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestVolumeEncryption_Clone(t *testing.T) {
+	ct := NewVolumeEncryption()
+	ct.Key = "Never"
+
+	clonedCt, ok := ct.Clone().(*VolumeEncryption)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	assert.True(t, clonedCt.IsEncrypted())
+	clonedCt.Key = "Other"
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
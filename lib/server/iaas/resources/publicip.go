@@ -0,0 +1,53 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resources
+
+import (
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+)
+
+// PublicIP represents a public IP address allocated on the provider, that can be moved between hosts
+type PublicIP struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address,omitempty"`
+	HostID  string `json:"host_id,omitempty"`
+}
+
+// NewPublicIP ...
+func NewPublicIP() *PublicIP {
+	return &PublicIP{}
+}
+
+// OK ...
+func (ip *PublicIP) OK() bool {
+	result := true
+	result = result && ip.ID != ""
+	result = result && ip.Name != ""
+	result = result && ip.Address != ""
+	return result
+}
+
+// Serialize serializes PublicIP instance into bytes (output json code)
+func (ip *PublicIP) Serialize() ([]byte, error) {
+	return serialize.ToJSON(ip)
+}
+
+// Deserialize reads json code and restores a PublicIP
+func (ip *PublicIP) Deserialize(buf []byte) error {
+	return serialize.FromJSON(buf, ip)
+}
@@ -0,0 +1,28 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicIP_Serialize(t *testing.T) {
+	ip := &PublicIP{
+		ID:      "eipalloc-0b1d2a3c4e5f60718",
+		Name:    "service_ip",
+		Address: "52.47.100.12",
+		HostID:  "i-0a1b2c3d4e5f60718",
+	}
+	assert.True(t, ip.OK())
+
+	buf, err := ip.Serialize()
+	require.Nil(t, err)
+
+	restored := NewPublicIP()
+	err = restored.Deserialize(buf)
+	require.Nil(t, err)
+	assert.Equal(t, ip, restored)
+
+	assert.False(t, NewPublicIP().OK())
+}
@@ -20,6 +20,7 @@ package userdata
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 	"io/ioutil"
@@ -66,8 +67,14 @@ type Content struct {
 	// DNSServers contains the list of DNS servers to use
 	// Used only if IsGateway is true
 	DNSServers []string
+	// DNSDomain contains the domain searched by the resolver of the host
+	DNSDomain string
 	// CIDR contains the cidr of the network
 	CIDR string
+	// IPv6CIDR contains the IPv6 prefix of the network, if any
+	IPv6CIDR string
+	// AdvertiseIPv6, if set to true, makes the gateway send the router advertisements of IPv6CIDR (no router does)
+	AdvertiseIPv6 bool
 	// DefaultRouteIP is the IP of the gateway or the VIP if gateway HA is enabled
 	DefaultRouteIP string
 	// PrimaryGatewayPrivateIP is the private IP of the primary gateway
@@ -93,6 +100,8 @@ type Content struct {
 
 	ProviderName     string
 	BuildSubnetworks bool
+	// UserScript contains the script supplied by the user, base64-encoded, run in phase3
+	UserScript string
 	// Dashboard bool // Add kubernetes dashboard
 
 	// Template parameters
@@ -105,6 +114,7 @@ type Content struct {
 var (
 	userdataPhase1Template atomic.Value // *template.Template
 	userdataPhase2Template atomic.Value // *template.Template
+	userdataPhase3Template atomic.Value // *template.Template
 )
 
 // NewContent ...
@@ -153,7 +163,7 @@ func (ud *Content) Prepare(
 	useLayer3Networking = options.UseLayer3Networking
 	useNATService = options.UseNATService
 	operatorUsername = options.OperatorUsername
-	dnsList = options.DNSList
+	dnsList = append(append([]string{}, request.DNSServers...), options.DNSList...)
 
 	bashLibrary, err := system.GetBashLibrary()
 	if err != nil {
@@ -180,7 +190,11 @@ func (ud *Content) Prepare(
 	ud.IsGateway = request.DefaultRouteIP == "" && len(request.Networks) != 0 && request.Networks[0].Name != resources.SingleHostNetworkName && !useLayer3Networking
 	ud.AddGateway = !request.PublicIP && !useLayer3Networking && ip != "" && !useNATService
 	ud.DNSServers = dnsList
+	ud.DNSDomain = request.DNSDomain
 	ud.CIDR = cidr
+	if len(request.Networks) > 0 && request.Networks[0].IPVersion.HasIPv6() {
+		ud.IPv6CIDR = request.Networks[0].IPv6CIDR
+	}
 	ud.DefaultRouteIP = ip
 	ud.Password = request.Password
 	ud.EmulatedPublicNet = defaultNetworkCIDR
@@ -190,6 +204,9 @@ func (ud *Content) Prepare(
 	ud.TemplateOperationTimeout = (temporal.GetHostTimeout() / 2).String()
 	ud.TemplateLongOperationTimeout = temporal.GetHostTimeout().String()
 	ud.TemplatePullImagesTimeout = (2 * temporal.GetHostTimeout()).String()
+	if request.UserData != "" {
+		ud.UserScript = base64.StdEncoding.EncodeToString([]byte(request.UserData))
+	}
 
 	if request.HostName != "" {
 		ud.HostName = request.HostName
@@ -294,6 +311,37 @@ func (ud *Content) Generate(phase string) ([]byte, error) {
 			}
 		}
 
+	case "phase3":
+		if ud.UserScript == "" {
+			return nil, fmt.Errorf("no user script to run in phase3")
+		}
+		anon := userdataPhase3Template.Load()
+		if anon == nil {
+			box, err = rice.FindBox("../userdata/scripts")
+			if err != nil {
+				return nil, err
+			}
+
+			// There is no flavor of phase3, the script of the user is the same whatever the provider
+			tmplString, err := box.String("userdata.phase3.sh")
+			if err != nil {
+				return nil, fmt.Errorf("error loading script template for phase3 : %s", err.Error())
+			}
+			tmpl, err := template.New("userdata.phase3").Parse(tmplString)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing script template for phase3 : %s", err.Error())
+			}
+			userdataPhase3Template.Store(tmpl)
+			anon = userdataPhase3Template.Load()
+		}
+		tmpl := anon.(*template.Template)
+		buf := bytes.NewBufferString("")
+		err = tmpl.Execute(buf, ud)
+		if err != nil {
+			return nil, err
+		}
+		result = buf.Bytes()
+
 	default:
 		return nil, fmt.Errorf("phase '%s' not managed", phase)
 	}
@@ -310,6 +358,11 @@ func (ud *Content) Generate(phase string) ([]byte, error) {
 	return result, nil
 }
 
+// HasPhase3 tells if a script supplied by the user has to be run in phase3
+func (ud Content) HasPhase3() bool {
+	return ud.UserScript != ""
+}
+
 // AddInTag adds some useful code on the end of userdata.phase2.sh just before the end (on the label #insert_tag)
 func (ud Content) AddInTag(phase string, tagname string, content string) {
 	if _, ok := ud.Tags[phase]; !ok {
@@ -405,6 +405,9 @@ configure_network() {
   {{- if .IsGateway }}
   configure_as_gateway || fail 194
   install_keepalived || fail 195
+  {{- if .AdvertiseIPv6 }}
+  install_radvd || fail 223
+  {{- end }}
   {{- end }}
 
   update_fqdn
@@ -469,6 +472,35 @@ EOF
   echo done
 }
 
+# Converts the IPv4 address $1 to an integer
+ipv4_to_int() {
+  local IFS=.
+  local a b c d
+  read -r a b c d <<<"$1"
+  echo $(((a << 24) + (b << 16) + (c << 8) + d))
+}
+
+# Tells if the IPv4 address $1 belongs to the CIDR $2
+ipv4_in_cidr() {
+  local mask=$(((0xFFFFFFFF << (32 - ${2#*/})) & 0xFFFFFFFF))
+  [[ $(($(ipv4_to_int $1) & mask)) -eq $(($(ipv4_to_int ${2%/*}) & mask)) ]]
+}
+
+# Prints the private interface with an address in the default network of the host ({{ .CIDR }}), which holds
+# the default route; prints nothing if none found
+default_route_interface() {
+  local IF ADDR
+  for IF in ${NICS}; do
+    [[ "$IF" == "$PU_IF" ]] && continue
+    for ADDR in $(ip -4 -o addr show dev $IF 2>/dev/null | awk '{print $4}' | cut -d/ -f1); do
+      if ipv4_in_cidr $ADDR {{ .CIDR }}; then
+        echo $IF
+        return 0
+      fi
+    done
+  done
+}
+
 # Configure network using systemd-networkd
 configure_network_systemd_networkd() {
   echo "Configuring network (using netplan and systemd-networkd)..."
@@ -482,7 +514,12 @@ configure_network_systemd_networkd() {
   mkdir -p /etc/netplan
   rm -f /etc/netplan/*
 
+  # The interfaces are not always enumerated in the order of the networks; the default route goes through the
+  # interface in the default network, or the first private interface if its address is not known yet
+  DEFAULT_IF=$(default_route_interface)
+
   # Recreate netplan configuration with last netplan version and more settings
+  DEFAULT_ROUTE_SET=0
   for IF in ${NICS}; do
     if [[ "$IF" == "$PU_IF" ]]; then
       cat <<-EOF >/etc/netplan/10-${IF}-public.yaml
@@ -514,15 +551,23 @@ network:
         use-dns: false
 {{- if .AddGateway }}
         use-routes: false
+{{- else }}
+        use-routes: true
+{{- end}}
+EOF
+{{- if .AddGateway }}
+      # Only the interface in the default network holds the default route
+      if [[ "$IF" == "$DEFAULT_IF" ]] || [[ -z "$DEFAULT_IF" && ${DEFAULT_ROUTE_SET} -eq 0 ]]; then
+        cat <<-EOF >>/etc/netplan/11-${IF}-private.yaml
       routes:
       - to: 0.0.0.0/0
         via: {{ .DefaultRouteIP }}
         scope: global
         on-link: true
-{{- else }}
-        use-routes: true
-{{- end}}
 EOF
+        DEFAULT_ROUTE_SET=1
+      fi
+{{- end}}
     fi
   done
 
@@ -538,6 +583,7 @@ EOF
     if [[ $ISGW -eq 0 ]]; then
       rm -f /etc/netplan/*
       # Recreate netplan configuration with last netplan version and more settings
+      DEFAULT_ROUTE_SET=0
       for IF in ${NICS}; do
         if [[ "$IF" == "$PU_IF" ]]; then
           cat <<-EOF >/etc/netplan/10-$IF-public.yaml
@@ -567,17 +613,20 @@ network:
       critical: true
       dhcp4-overrides:
         use-dns: true
-{{- if .AddGateway }}
         use-routes: true
+EOF
+{{- if .AddGateway }}
+          if [[ "$IF" == "$DEFAULT_IF" ]] || [[ -z "$DEFAULT_IF" && ${DEFAULT_ROUTE_SET} -eq 0 ]]; then
+            cat <<-EOF >>/etc/netplan/11-${IF}-private.yaml
       routes:
       - to: 0.0.0.0/0
         via: {{ .DefaultRouteIP }}
         scope: global
         on-link: true
-{{- else }}
-        use-routes: true
-{{- end}}
 EOF
+            DEFAULT_ROUTE_SET=1
+          fi
+{{- end}}
         fi
       done
     fi
@@ -795,12 +844,18 @@ configure_as_gateway() {
   if [[ ! -z $PR_IFs ]]; then
     # Enable forwarding
     for i in /etc/sysctl.d/* /etc/sysctl.conf; do
-      grep -v "net.ipv4.ip_forward=" ${i} >${i}.new
+      grep -v "net.ipv4.ip_forward=\|net.ipv6.conf.all.forwarding=" ${i} >${i}.new
       mv -f ${i}.new ${i}
     done
     cat >/etc/sysctl.d/21-gateway.conf <<-EOF
 net.ipv4.ip_forward=1
 net.ipv4.ip_nonlocal_bind=1
+{{- if .IPv6CIDR }}
+net.ipv6.conf.all.forwarding=1
+net.ipv6.conf.all.accept_ra=2
+net.ipv6.conf.default.accept_ra=2
+net.ipv6.ip_nonlocal_bind=1
+{{- end }}
 EOF
     case $LINUX_KIND in
     ubuntu) systemctl restart systemd-sysctl ;;
@@ -835,6 +890,48 @@ EOF
   echo done
 }
 
+install_radvd() {
+  case $LINUX_KIND in
+  ubuntu | debian)
+    sfApt update && sfApt -y install radvd || return 1
+    ;;
+
+  redhat | rhel | centos | fedora)
+    if which dnf; then
+      dnf install -q -y radvd || return 1
+    else
+      yum install -q -y radvd || return 1
+    fi
+    ;;
+  *)
+    echo "Unsupported Linux distribution '$LINUX_KIND'!"
+    return 1
+    ;;
+  esac
+
+  # No router advertises the IPv6 prefix of the network, so the gateway does; addresses are still given by DHCPv6
+  echo -n >/etc/radvd.conf
+  for IF in ${PR_IFs}; do
+    cat >>/etc/radvd.conf <<-EOF
+interface ${IF} {
+    AdvSendAdvert on;
+    AdvManagedFlag on;
+    AdvOtherConfigFlag on;
+    prefix {{ .IPv6CIDR }} {
+        AdvOnLink on;
+        AdvAutonomous off;
+    };
+};
+EOF
+    # The gateway receives no router advertisement to trigger DHCPv6, so asks explicitly for its own address
+    dhclient -6 -nw ${IF} || true
+  done
+  chmod 0644 /etc/radvd.conf
+
+  systemctl enable radvd && systemctl restart radvd || return 1
+  return 0
+}
+
 install_keepalived() {
   # Try installing network-scripts if available
   case $LINUX_KIND in
@@ -996,14 +1093,11 @@ nameserver 1.1.1.1
 {{- end }}
 EOF
 
-  # VPL: need to determine if it's a good idea to update resolv.conf with search domain...
-  #      The DNS servers will not be able to resolve hosts from the DNSDOMAIN by themselves, there is a need for an internal DNS server
-  #    DNSDOMAIN="$(hostname -d)"
-  #    if [[ ! -z "$DNSDOMAIN" ]]; then
-  #cat <<-EOF >>/etc/resolv.conf
-  #search $DNSDOMAIN
-  #EOF
-  #    fi
+  {{- if .DNSDomain }}
+  cat <<-'EOF' >>/etc/resolv.conf
+search {{ .DNSDomain }}
+EOF
+  {{- end }}
 
   cp /etc/resolv.conf /etc/resolv.conf.edited
   touch /etc/resolv.conf && sleep 2 || true
@@ -1031,14 +1125,11 @@ nameserver 1.1.1.1
 {{- end }}
 EOF
 
-  # VPL: need to determine if it's a good idea to update resolv.conf with search domain...
-  #      The DNS servers will not be able to resolve hosts from the DNSDOMAIN by themselves, there is a need for an internal DNS server
-  #    DNSDOMAIN="$(hostname -d)"
-  #    if [[ ! -z "$DNSDOMAIN" ]]; then
-  #        cat <<-EOF >>/etc/resolvconf/resolv.conf.d/head
-  #search $DNSDOMAIN
-  #EOF
-  #    fi
+  {{- if .DNSDomain }}
+  cat <<-'EOF' >>/etc/resolvconf/resolv.conf.d/head
+search {{ .DNSDomain }}
+EOF
+  {{- end }}
 
   resolvconf -u
   echo done
@@ -1063,14 +1154,11 @@ Cache=yes
 DNSStubListener=yes
 EOF
 
-  # VPL: need to determine if it's a good idea to update resolv.conf with search domain...
-  #      The DNS servers will not be able to resolve hosts from the DNSDOMAIN by themselves, there is a need for an internal DNS server
-  #    DNSDOMAIN=$(hostname -d)
-  #    if [[ ! -z "$DNSDOMAIN" ]]; then
-  #        cat <<-EOF >>/etc/systemd/resolved.conf
-  #Domains=$DNSDOMAIN
-  #EOF
-  #    fi
+  {{- if .DNSDomain }}
+  cat <<-'EOF' >>/etc/systemd/resolved.conf
+Domains={{ .DNSDomain }}
+EOF
+  {{- end }}
 
   systemctl restart systemd-resolved
   echo done
@@ -0,0 +1,46 @@
+#!/bin/bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Runs the script supplied by the user, once the host has been configured by phases 1 and 2
+
+{{.Header}}
+
+print_error() {
+  read line file <<<$(caller)
+  echo "An error occurred in line $line of file $file:" "{"$(sed "${line}q;d" "$file")"}" >&2
+  {{.ExitOnError}}
+}
+trap print_error ERR
+
+# Redirects outputs to /opt/safescale/var/log/user_data.phase3.log
+exec 1<&-
+exec 2<&-
+exec 1<>/opt/safescale/var/log/user_data.phase3.log
+exec 2>&1
+
+SCRIPT=/opt/safescale/var/tmp/user_data.phase3.user.sh
+umask 077
+echo '{{ .UserScript }}' | base64 -d >${SCRIPT}
+umask 022
+
+# The exit status of the user script is kept instead of stopping here, to be reported
+RC=0
+bash ${SCRIPT} || RC=$?
+if [ ${RC} -ne 0 ]; then
+  echo "PROVISIONING_ERROR: ${RC}"
+fi
+echo -n "${RC},$(hostname),$(date +%Y/%m/%d-%H:%M:%S)" >/opt/safescale/var/state/user_data.phase3.done
+exit ${RC}
@@ -405,6 +405,9 @@ configure_network() {
   {{- if .IsGateway }}
   configure_as_gateway || fail 194
   install_keepalived || fail 195
+  {{- if .AdvertiseIPv6 }}
+  install_radvd || fail 223
+  {{- end }}
   {{- end }}
 
   update_fqdn
@@ -795,12 +798,18 @@ configure_as_gateway() {
   if [[ ! -z $PR_IFs ]]; then
     # Enable forwarding
     for i in /etc/sysctl.d/* /etc/sysctl.conf; do
-      grep -v "net.ipv4.ip_forward=" ${i} >${i}.new
+      grep -v "net.ipv4.ip_forward=\|net.ipv6.conf.all.forwarding=" ${i} >${i}.new
       mv -f ${i}.new ${i}
     done
     cat >/etc/sysctl.d/21-gateway.conf <<-EOF
 net.ipv4.ip_forward=1
 net.ipv4.ip_nonlocal_bind=1
+{{- if .IPv6CIDR }}
+net.ipv6.conf.all.forwarding=1
+net.ipv6.conf.all.accept_ra=2
+net.ipv6.conf.default.accept_ra=2
+net.ipv6.ip_nonlocal_bind=1
+{{- end }}
 EOF
     case $LINUX_KIND in
     ubuntu) systemctl restart systemd-sysctl ;;
@@ -835,6 +844,48 @@ EOF
   echo done
 }
 
+install_radvd() {
+  case $LINUX_KIND in
+  ubuntu | debian)
+    sfApt update && sfApt -y install radvd || return 1
+    ;;
+
+  redhat | rhel | centos | fedora)
+    if which dnf; then
+      dnf install -q -y radvd || return 1
+    else
+      yum install -q -y radvd || return 1
+    fi
+    ;;
+  *)
+    echo "Unsupported Linux distribution '$LINUX_KIND'!"
+    return 1
+    ;;
+  esac
+
+  # No router advertises the IPv6 prefix of the network, so the gateway does; addresses are still given by DHCPv6
+  echo -n >/etc/radvd.conf
+  for IF in ${PR_IFs}; do
+    cat >>/etc/radvd.conf <<-EOF
+interface ${IF} {
+    AdvSendAdvert on;
+    AdvManagedFlag on;
+    AdvOtherConfigFlag on;
+    prefix {{ .IPv6CIDR }} {
+        AdvOnLink on;
+        AdvAutonomous off;
+    };
+};
+EOF
+    # The gateway receives no router advertisement to trigger DHCPv6, so asks explicitly for its own address
+    dhclient -6 -nw ${IF} || true
+  done
+  chmod 0644 /etc/radvd.conf
+
+  systemctl enable radvd && systemctl restart radvd || return 1
+  return 0
+}
+
 install_keepalived() {
   # Try installing network-scripts if available
   case $LINUX_KIND in
@@ -996,14 +1047,11 @@ nameserver 1.1.1.1
 {{- end }}
 EOF
 
-  # VPL: need to determine if it's a good idea to update resolv.conf with search domain...
-  #      The DNS servers will not be able to resolve hosts from the DNSDOMAIN by themselves, there is a need for an internal DNS server
-  #    DNSDOMAIN="$(hostname -d)"
-  #    if [[ ! -z "$DNSDOMAIN" ]]; then
-  #cat <<-EOF >>/etc/resolv.conf
-  #search $DNSDOMAIN
-  #EOF
-  #    fi
+  {{- if .DNSDomain }}
+  cat <<-'EOF' >>/etc/resolv.conf
+search {{ .DNSDomain }}
+EOF
+  {{- end }}
 
   cp /etc/resolv.conf /etc/resolv.conf.edited
   touch /etc/resolv.conf && sleep 2 || true
@@ -1031,14 +1079,11 @@ nameserver 1.1.1.1
 {{- end }}
 EOF
 
-  # VPL: need to determine if it's a good idea to update resolv.conf with search domain...
-  #      The DNS servers will not be able to resolve hosts from the DNSDOMAIN by themselves, there is a need for an internal DNS server
-  #    DNSDOMAIN="$(hostname -d)"
-  #    if [[ ! -z "$DNSDOMAIN" ]]; then
-  #        cat <<-EOF >>/etc/resolvconf/resolv.conf.d/head
-  #search $DNSDOMAIN
-  #EOF
-  #    fi
+  {{- if .DNSDomain }}
+  cat <<-'EOF' >>/etc/resolvconf/resolv.conf.d/head
+search {{ .DNSDomain }}
+EOF
+  {{- end }}
 
   resolvconf -u
   echo done
@@ -1063,14 +1108,11 @@ Cache=yes
 DNSStubListener=yes
 EOF
 
-  # VPL: need to determine if it's a good idea to update resolv.conf with search domain...
-  #      The DNS servers will not be able to resolve hosts from the DNSDOMAIN by themselves, there is a need for an internal DNS server
-  #    DNSDOMAIN=$(hostname -d)
-  #    if [[ ! -z "$DNSDOMAIN" ]]; then
-  #        cat <<-EOF >>/etc/systemd/resolved.conf
-  #Domains=$DNSDOMAIN
-  #EOF
-  #    fi
+  {{- if .DNSDomain }}
+  cat <<-'EOF' >>/etc/systemd/resolved.conf
+Domains={{ .DNSDomain }}
+EOF
+  {{- end }}
 
   systemctl restart systemd-resolved
   echo done
@@ -29,6 +29,8 @@ type VolumeRequest struct {
 	Speed  volumespeed.Enum `json:"speed,omitempty"`
 	InLVM  bool             `json:"lvm,omitempty"`
 	SizeVU int              `json:"sizevu,omitempty"`
+	// AvailabilityZone is the availability zone where to create the volume, the one selected by the provider if empty
+	AvailabilityZone string `json:"availability_zone,omitempty"`
 }
 
 // Volume represents a block volume
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package iaas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// scannerFolderName is the folder of the metadata bucket where the results of the scanner are stored
+const scannerFolderName = "scanner"
+
+// scanFolder returns the path in the metadata bucket of the scan results of the region of the tenant
+func (svc *service) scanFolder() (string, error) {
+	if svc.GetMetadataBucket() == nil {
+		return "", scerr.NotAvailableError("no metadata bucket")
+	}
+	authOpts, err := svc.GetAuthenticationOptions()
+	if err != nil {
+		return "", err
+	}
+	region, ok := authOpts.Get("Region")
+	if !ok {
+		return "", fmt.Errorf("region value unset")
+	}
+	return fmt.Sprintf("%s/%s", scannerFolderName, region), nil
+}
+
+// ListTemplateScans returns the measurements done by the scanner on the templates of the tenant
+func (svc *service) ListTemplateScans() ([]resources.StoredCPUInfo, error) {
+	if svc == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	folder, err := svc.scanFolder()
+	if err != nil {
+		return nil, err
+	}
+
+	bucket := svc.GetMetadataBucket()
+	list, err := bucket.List(folder, objectstorage.NoPrefix)
+	if err != nil {
+		return nil, err
+	}
+	var scans []resources.StoredCPUInfo
+	for _, item := range list {
+		if item == folder || item == folder+"/" {
+			continue
+		}
+		var buffer bytes.Buffer
+		_, err = bucket.ReadObject(item, &buffer, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		data := buffer.Bytes()
+		if key := svc.GetMetadataKey(); key != nil && len(key) > 0 {
+			data, err = crypt.Decrypt(data, key)
+			if err != nil {
+				return nil, err
+			}
+		}
+		scan := resources.StoredCPUInfo{}
+		err = json.Unmarshal(data, &scan)
+		if err != nil {
+			return nil, fmt.Errorf("invalid content of scan result '%s': %s", item, err.Error())
+		}
+		scans = append(scans, scan)
+	}
+	return scans, nil
+}
+
+// StoreTemplateScan stores in the metadata bucket the measurements done by the scanner on a template, replacing
+// the previous ones
+func (svc *service) StoreTemplateScan(scan *resources.StoredCPUInfo) error {
+	if svc == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if scan == nil {
+		return scerr.InvalidParameterError("scan", "cannot be nil")
+	}
+	if scan.TemplateName == "" {
+		return scerr.InvalidParameterError("scan.TemplateName", "cannot be empty string")
+	}
+	folder, err := svc.scanFolder()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(scan)
+	if err != nil {
+		return err
+	}
+	if key := svc.GetMetadataKey(); key != nil && len(key) > 0 {
+		data, err = crypt.Encrypt(data, key)
+		if err != nil {
+			return err
+		}
+	}
+	source := bytes.NewBuffer(data)
+	_, err = svc.GetMetadataBucket().WriteObject(folder+"/"+scan.TemplateName, source, int64(source.Len()), nil)
+	return err
+}
@@ -17,11 +17,10 @@
 package iaas
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	log "github.com/sirupsen/logrus"
 	"math"
-	"os"
 	"regexp"
 	"sort"
 	"strings"
@@ -31,7 +30,6 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 
-	scribble "github.com/nanobox-io/golang-scribble"
 	uuid "github.com/satori/go.uuid"
 	"github.com/xrash/smetrics"
 
@@ -57,12 +55,18 @@ type Service interface {
 
 	CreateHostWithKeyPair(resources.HostRequest) (*resources.Host, *userdata.Content, *resources.KeyPair, error)
 	FilterImages(string) ([]resources.Image, error)
+	GetCurrency() string
 	GetMetadataKey() *crypt.Key
 	GetMetadataBucket() objectstorage.Bucket
+	GetPreset(string) (*Preset, error)
+	GetVolumePrice(*resources.Volume) (float64, bool)
 	ListHostsByName() (map[string]*resources.Host, error)
+	ListPresets() []Preset
+	ListTemplateScans() ([]resources.StoredCPUInfo, error)
 	SearchImage(string) (*resources.Image, error)
 	SelectTemplatesBySize(resources.SizingRequirements, bool) ([]*resources.HostTemplate, error)
 	SelectTemplateByName(string) (*resources.HostTemplate, error)
+	StoreTemplateScan(*resources.StoredCPUInfo) error
 	WaitHostState(string, hoststate.Enum, time.Duration) error
 	WaitVolumeState(string, volumestate.Enum, time.Duration) (*resources.Volume, error)
 
@@ -85,6 +89,9 @@ type service struct {
 	blacklistTemplateRE *regexp.Regexp
 	whitelistImageRE    *regexp.Regexp
 	blacklistImageRE    *regexp.Regexp
+
+	prices  *Prices
+	presets map[string]Preset
 }
 
 const (
@@ -144,6 +151,23 @@ func (svc *service) SetProvider(provider providers.Provider) {
 	svc.Provider = provider
 }
 
+// WithContext returns a copy of the service whose calls to the provider are traced as children of the span carried by ctx
+// Services not created by this package are returned unchanged
+func WithContext(ctx context.Context, svc Service) Service {
+	s, ok := svc.(*service)
+	if !ok || s == nil || ctx == nil {
+		return svc
+	}
+	inner := s.Provider
+	if tp, ok := inner.(*providers.TracingProvider); ok {
+		// Service already traced in another context, traces in ctx instead
+		inner = tp.InnerProvider
+	}
+	traced := *s
+	traced.Provider = providers.NewTracingProvider(ctx, inner, inner.GetName())
+	return &traced
+}
+
 // WaitHostState waits an host achieve state
 // If host in error state, returns utils.ErrNotAvailable
 // If timeout is reached, returns utils.ErrTimeout
@@ -245,6 +269,7 @@ func (svc *service) ListTemplates(all bool) ([]resources.HostTemplate, error) {
 	if err != nil {
 		return nil, err
 	}
+	svc.prices.priceTemplates(allTemplates)
 	if all {
 		return allTemplates, nil
 	}
@@ -257,6 +282,7 @@ func (svc *service) SelectTemplateByName(name string) (*resources.HostTemplate,
 	if err != nil {
 		return nil, err
 	}
+	svc.prices.priceTemplates(allTemplates)
 	for _, i := range allTemplates {
 		if i.Name == name {
 			return &i, nil
@@ -308,86 +334,45 @@ func (svc *service) SelectTemplatesBySize(sizing resources.SizingRequirements, f
 	}
 
 	// FIXME Prevent GPUs when user sends a 0
-	askedForSpecificScannerInfo := sizing.MinGPU >= 0 || sizing.MinFreq != 0
+	askedForSpecificScannerInfo := sizing.MinGPU >= 0 || sizing.MinFreq != 0 || sizing.MinDiskSpeed > 0 || sizing.MinNetSpeed > 0
 	if askedForSpecificScannerInfo {
-		_ = os.MkdirAll(utils.AbsPathify("$HOME/.safescale/scanner"), 0777)
-		db, err := scribble.New(utils.AbsPathify("$HOME/.safescale/scanner/db"), nil)
+		scans, err := svc.ListTemplateScans()
+		if err == nil && len(scans) == 0 {
+			err = fmt.Errorf("no template scanned, the scanner has to be run on the tenant first")
+		}
 		if err != nil {
-			if force {
-				log.Warnf("Problem creating / accessing Scanner database, ignoring GPU and Freq parameters for now...: %v", err)
-			} else {
-				var noHostError string
-				if sizing.MinFreq <= 0 {
-					noHostError = fmt.Sprintf("Unable to create a host with '%d' GPUs, problem accessing Scanner database: %v", sizing.MinGPU, err)
-				} else {
-					noHostError = fmt.Sprintf("Unable to create a host with '%d' GPUs and '%.01f' MHz clock frequency, problem accessing Scanner database: %v", sizing.MinGPU, sizing.MinFreq, err)
-				}
+			if !force {
+				noHostError := fmt.Sprintf("Unable to create a host with %s, problem accessing scanner results: %v", describeScannerSizing(sizing), err)
 				log.Error(noHostError)
 				return nil, fmt.Errorf(noHostError)
 			}
+			log.Warnf("Problem accessing scanner results, ignoring GPU, CPU frequency, disk speed and network speed requirements: %v", err)
+			askedForSpecificScannerInfo = false
 		} else {
-			authOpts, err := svc.GetAuthenticationOptions()
-			if err != nil {
-				return nil, err
-			}
-			region, ok := authOpts.Get("Region")
-			if !ok {
-				return nil, fmt.Errorf("region value unset")
-			}
-			folder := fmt.Sprintf("images/%s/%s", svc.GetName(), region)
-
-			imageList, err := db.ReadAll(folder)
-			if err != nil {
-				if force {
-					log.Warnf("Problem creating / accessing Scanner database, ignoring GPU and Freq parameters for now...: %v", err)
-				} else {
-					var noHostError string
-					if sizing.MinFreq <= 0 {
-						noHostError = fmt.Sprintf("Unable to create a host with '%d' GPUs, problem accessing Scanner database: %v", sizing.MinGPU, err)
-					} else {
-						noHostError = fmt.Sprintf("Unable to create a host with '%d' GPUs and '%.01f' MHz clock frequency, problem accessing Scanner database: %v", sizing.MinGPU, sizing.MinFreq, err)
-					}
-					log.Error(noHostError)
-					return nil, fmt.Errorf(noHostError)
+			for _, scan := range scans {
+				// if the user asked explicitly no gpu
+				if sizing.MinGPU == 0 && scan.GPU != 0 {
+					continue
 				}
-			} else {
-				var images []resources.StoredCPUInfo
-				for _, f := range imageList {
-					imageFound := resources.StoredCPUInfo{}
-					if err := json.Unmarshal([]byte(f), &imageFound); err != nil {
-						log.Error(fmt.Sprintf("error unmarsalling image %s : %v", f, err))
-					}
-
-					// if the user asked explicitly no gpu
-					if sizing.MinGPU == 0 && imageFound.GPU != 0 {
-						continue
-					}
-
-					if imageFound.GPU < sizing.MinGPU {
-						continue
-					}
-
-					if imageFound.CPUFrequency < float64(sizing.MinFreq) {
-						continue
-					}
-
-					images = append(images, imageFound)
+				if scan.GPU < sizing.MinGPU {
+					continue
 				}
-
-				if !force && (len(images) == 0) {
-					var noHostError string
-					if sizing.MinFreq <= 0 {
-						noHostError = fmt.Sprintf("Unable to create a host with '%d' GPUs, no images matching requirements", sizing.MinGPU)
-					} else {
-						noHostError = fmt.Sprintf("Unable to create a host with '%d' GPUs and a CPU clock frequencyof '%.01f MHz', no images matching requirements", sizing.MinGPU, sizing.MinFreq)
-					}
-					log.Error(noHostError)
-					return nil, fmt.Errorf(noHostError)
+				if scan.CPUFrequency < float64(sizing.MinFreq) {
+					continue
 				}
-
-				for _, image := range images {
-					scannerTpls[image.TemplateID] = true
+				if scan.MainDiskSpeed < float64(sizing.MinDiskSpeed) {
+					continue
+				}
+				if scan.SampleNetSpeed < float64(sizing.MinNetSpeed) {
+					continue
 				}
+				scannerTpls[scan.TemplateID] = true
+			}
+
+			if !force && len(scannerTpls) == 0 {
+				noHostError := fmt.Sprintf("Unable to create a host with %s, no scanned template matching requirements", describeScannerSizing(sizing))
+				log.Error(noHostError)
+				return nil, fmt.Errorf(noHostError)
 			}
 		}
 	}
@@ -453,10 +438,35 @@ func (svc *service) SelectTemplatesBySize(sizing resources.SizingRequirements, f
 		}
 	}
 
-	sort.Sort(ByRankDRF(selectedTpls))
+	if sizing.Cheapest {
+		sort.Sort(ByPrice(selectedTpls))
+		if len(selectedTpls) > 0 && selectedTpls[0].PricePerHour <= 0 {
+			log.Warnf("No price known for the templates matching the sizing, selecting the smallest one")
+		}
+	} else {
+		sort.Sort(ByRankDRF(selectedTpls))
+	}
 	return selectedTpls, nil
 }
 
+// describeScannerSizing returns a description of the requirements needing the results of the scanner
+func describeScannerSizing(sizing resources.SizingRequirements) string {
+	var parts []string
+	if sizing.MinGPU >= 0 {
+		parts = append(parts, fmt.Sprintf("%d GPU%s", sizing.MinGPU, utils.Plural(sizing.MinGPU)))
+	}
+	if sizing.MinFreq > 0 {
+		parts = append(parts, fmt.Sprintf("a CPU frequency of at least %.01f GHz", sizing.MinFreq))
+	}
+	if sizing.MinDiskSpeed > 0 {
+		parts = append(parts, fmt.Sprintf("a disk speed of at least %.0f MB/s", sizing.MinDiskSpeed))
+	}
+	if sizing.MinNetSpeed > 0 {
+		parts = append(parts, fmt.Sprintf("a network speed of at least %.0f KB/s", sizing.MinNetSpeed))
+	}
+	return strings.Join(parts, ", ")
+}
+
 type scoredImage struct {
 	resources.Image
 	score float64
@@ -557,6 +567,18 @@ func (svc *service) SearchImage(osname string) (*resources.Image, error) {
 		return nil, scerr.InvalidInstanceError()
 	}
 
+	// An image alias is the image of a preset of the tenant
+	if strings.HasPrefix(osname, PresetPrefix) {
+		preset, err := svc.GetPreset(osname)
+		if err != nil {
+			return nil, err
+		}
+		if preset.Image == "" {
+			return nil, scerr.NotFoundError(fmt.Sprintf("preset '%s' defines no image", preset.Name))
+		}
+		osname = preset.Image
+	}
+
 	imgs, err := svc.ListImages(false)
 	if err != nil {
 		return nil, err
@@ -74,6 +74,17 @@ type Stack interface {
 	// DeleteVIP deletes the port corresponding to the VIP
 	DeleteVIP(*resources.VirtualIP) error
 
+	// AllocatePublicIP reserves a new public IP address named name
+	AllocatePublicIP(name string) (*resources.PublicIP, error)
+	// ListPublicIPs lists the public IP addresses reserved on the tenant
+	ListPublicIPs() ([]*resources.PublicIP, error)
+	// AttachPublicIP binds the public IP address to the host identified by hostID
+	AttachPublicIP(ip *resources.PublicIP, hostID string) error
+	// DetachPublicIP unbinds the public IP address from the host it is attached to
+	DetachPublicIP(ip *resources.PublicIP) error
+	// ReleasePublicIP gives back the public IP address to the provider
+	ReleasePublicIP(ip *resources.PublicIP) error
+
 	// CreateHost creates an host that fulfils the request
 	CreateHost(request resources.HostRequest) (*resources.Host, *userdata.Content, error)
 	// GetHost returns the host identified by id or updates content of a *resources.Host
@@ -94,6 +105,10 @@ type Stack interface {
 	RebootHost(id string) error
 	// Resize host
 	ResizeHost(id string, request resources.SizingRequirements) (*resources.Host, error)
+	// GetHostConsoleOutput returns the output of the console of the host identified by id (serial port, boot log, ...)
+	GetHostConsoleOutput(id string) (string, error)
+	// IsHostReclaimed tells if the spot host identified by id has been reclaimed by the provider
+	IsHostReclaimed(id string) (bool, error)
 
 	// CreateVolume creates a block volume
 	CreateVolume(request resources.VolumeRequest) (*resources.Volume, error)
@@ -121,6 +121,31 @@ func (sp StackProxy) DeleteVIP(ip *resources.VirtualIP) error {
 	return errorTranslator(err)
 }
 
+func (sp StackProxy) AllocatePublicIP(name string) (*resources.PublicIP, error) {
+	rv, err := sp.InnerStack.AllocatePublicIP(name)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) ListPublicIPs() ([]*resources.PublicIP, error) {
+	rv, err := sp.InnerStack.ListPublicIPs()
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) AttachPublicIP(ip *resources.PublicIP, hostID string) error {
+	err := sp.InnerStack.AttachPublicIP(ip, hostID)
+	return errorTranslator(err)
+}
+
+func (sp StackProxy) DetachPublicIP(ip *resources.PublicIP) error {
+	err := sp.InnerStack.DetachPublicIP(ip)
+	return errorTranslator(err)
+}
+
+func (sp StackProxy) ReleasePublicIP(ip *resources.PublicIP) error {
+	err := sp.InnerStack.ReleasePublicIP(ip)
+	return errorTranslator(err)
+}
+
 func (sp StackProxy) CreateHost(request resources.HostRequest) (*resources.Host, *userdata.Content, error) {
 	rv, rv2, err := sp.InnerStack.CreateHost(request)
 	return rv, rv2, errorTranslator(err)
@@ -171,6 +196,16 @@ func (sp StackProxy) ResizeHost(id string, request resources.SizingRequirements)
 	return rv, errorTranslator(err)
 }
 
+func (sp StackProxy) GetHostConsoleOutput(id string) (string, error) {
+	rv, err := sp.InnerStack.GetHostConsoleOutput(id)
+	return rv, errorTranslator(err)
+}
+
+func (sp StackProxy) IsHostReclaimed(id string) (bool, error) {
+	rv, err := sp.InnerStack.IsHostReclaimed(id)
+	return rv, errorTranslator(err)
+}
+
 func (sp StackProxy) CreateVolume(request resources.VolumeRequest) (*resources.Volume, error) {
 	rv, err := sp.InnerStack.CreateVolume(request)
 	return rv, errorTranslator(err)
@@ -116,6 +116,26 @@ type Price struct {
 	Terms           Terms   `json:"terms,omitempty"`
 }
 
+// OnDemandPricePerHour returns the on demand price per hour in USD of a product returned by the pricing API, 0 if not found
+func OnDemandPricePerHour(product map[string]interface{}) float64 {
+	terms, _ := product["terms"].(map[string]interface{})
+	onDemand, _ := terms["OnDemand"].(map[string]interface{})
+	for _, anon := range onDemand {
+		term, _ := anon.(map[string]interface{})
+		dimensions, _ := term["priceDimensions"].(map[string]interface{})
+		for _, anon := range dimensions {
+			dimension, _ := anon.(map[string]interface{})
+			unit, _ := dimension["pricePerUnit"].(map[string]interface{})
+			if usd, ok := unit["USD"].(string); ok {
+				if price, err := strconv.ParseFloat(usd, 64); err == nil && price > 0 {
+					return price
+				}
+			}
+		}
+	}
+	return 0
+}
+
 func ParseNumber(str string, failureValue int) int {
 	result := failureValue
 	if okValue, failure := strconv.Atoi(str); failure == nil {
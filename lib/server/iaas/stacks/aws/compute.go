@@ -230,8 +230,8 @@ func (s *Stack) GetTemplate(id string) (*resources.HostTemplate, error) {
 		return &template, err
 	}
 
-	for _, price := range prods.PriceList {
-		jsonPrice, err := json.Marshal(price)
+	for _, product := range prods.PriceList {
+		jsonPrice, err := json.Marshal(product)
 		if err != nil {
 			continue
 		}
@@ -249,6 +249,7 @@ func (s *Stack) GetTemplate(id string) (*resources.HostTemplate, error) {
 			DiskSize:  int(ParseStorage(price.Product.Attributes.Storage)),
 			RAMSize:   float32(ParseMemory(price.Product.Attributes.Memory)),
 		}
+		tpl.PricePerHour = OnDemandPricePerHour(product)
 
 		template = tpl
 		break
@@ -370,8 +371,8 @@ func (s *Stack) ListTemplates() ([]resources.HostTemplate, error) {
 
 	hostTemplates := make(map[string]resources.HostTemplate)
 
-	for _, price := range prods.PriceList {
-		jsonPrice, err := json.Marshal(price)
+	for _, product := range prods.PriceList {
+		jsonPrice, err := json.Marshal(product)
 		if err != nil {
 			continue
 		}
@@ -389,6 +390,7 @@ func (s *Stack) ListTemplates() ([]resources.HostTemplate, error) {
 			DiskSize:  int(ParseStorage(price.Product.Attributes.Storage)),
 			RAMSize:   float32(ParseMemory(price.Product.Attributes.Memory)),
 		}
+		tpl.PricePerHour = OnDemandPricePerHour(product)
 
 		hostTemplates[price.Product.Attributes.InstanceType] = tpl
 	}
@@ -612,6 +614,13 @@ func (s *Stack) CreateHost(request resources.HostRequest) (host *resources.Host,
 	if err != nil {
 		return nil, userData, err
 	}
+	err = host.Properties.LockForWrite(hostproperty.DescriptionV1).ThenUse(func(v data.Clonable) error {
+		v.(*propertiesv1.HostDescription).AvailabilityZone = s.AwsConfig.Zone
+		return nil
+	})
+	if err != nil {
+		return nil, userData, err
+	}
 
 	// Sets provider parameters to create host
 	userDataPhase1, err := userData.Generate("phase1")
@@ -1116,6 +1125,9 @@ func (s *Stack) InspectHost(hostParam interface{}) (host *resources.Host, err er
 					ID:   aws.StringValue(ni.SubnetId),
 					IP:   aws.StringValue(ni.PrivateIpAddress),
 				}
+				if len(ni.Ipv6Addresses) > 0 {
+					newSubnet.IPv6 = aws.StringValue(ni.Ipv6Addresses[0].Ipv6Address)
+				}
 
 				if ni.Association != nil {
 					if ni.Association.PublicIp != nil {
@@ -1129,12 +1141,16 @@ func (s *Stack) InspectHost(hostParam interface{}) (host *resources.Host, err er
 	}
 
 	ip4bynetid := make(map[string]string)
+	ip6bynetid := make(map[string]string)
 	netnamebyid := make(map[string]string)
 	netidbyname := make(map[string]string)
 
 	ipv4 := ""
 	for _, rn := range subnets {
 		ip4bynetid[rn.ID] = rn.IP
+		if rn.IPv6 != "" {
+			ip6bynetid[rn.ID] = rn.IPv6
+		}
 		netnamebyid[rn.ID] = rn.Name
 		netidbyname[rn.Name] = rn.ID
 		if rn.PublicIP != "" {
@@ -1145,7 +1161,7 @@ func (s *Stack) InspectHost(hostParam interface{}) (host *resources.Host, err er
 	err = host.Properties.LockForWrite(hostproperty.NetworkV1).ThenUse(func(v data.Clonable) error {
 		hostNetworkV1 := v.(*propertiesv1.HostNetwork)
 		hostNetworkV1.IPv4Addresses = ip4bynetid
-		hostNetworkV1.IPv6Addresses = make(map[string]string)
+		hostNetworkV1.IPv6Addresses = ip6bynetid
 		hostNetworkV1.NetworksByID = netnamebyid
 		hostNetworkV1.NetworksByName = netidbyname
 		if hostNetworkV1.PublicIPv4 == "" {
@@ -1524,6 +1540,50 @@ func (s *Stack) RebootHost(id string) error {
 	return err
 }
 
+// GetHostConsoleOutput returns the latest console output of the host identified by id
+func (s *Stack) GetHostConsoleOutput(id string) (string, error) {
+	if s == nil {
+		return "", scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return "", scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	out, err := s.EC2Service.GetConsoleOutput(&ec2.GetConsoleOutputInput{
+		InstanceId: aws.String(id),
+		Latest:     aws.Bool(true),
+	})
+	if err != nil {
+		return "", err
+	}
+	// Output is base64 encoded, and empty until the instance has produced some output
+	content, err := base64.StdEncoding.DecodeString(aws.StringValue(out.Output))
+	if err != nil {
+		return "", scerr.Errorf(fmt.Sprintf("failed to decode console output of host '%s'", id), err)
+	}
+	return string(content), nil
+}
+
+// IsHostReclaimed tells if the spot instance identified by id has been interrupted; interrupted spot instances
+// are terminated, then disappear
+func (s *Stack) IsHostReclaimed(id string) (bool, error) {
+	if s == nil {
+		return false, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return false, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	state, err := s.GetHostState(id)
+	if err != nil {
+		if _, ok := scerr.Cause(err).(scerr.ErrNotFound); ok {
+			return true, nil
+		}
+		return false, err
+	}
+	return state == hoststate.TERMINATED, nil
+}
+
 func (s *Stack) ResizeHost(id string, request resources.SizingRequirements) (*resources.Host, error) {
 	return nil, scerr.NotImplementedError("ResizeHost() not implemented yet") // FIXME Technical debt
 }
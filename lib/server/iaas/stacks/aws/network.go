@@ -63,6 +63,15 @@ func (s *Stack) DeleteVIP(*resources.VirtualIP) error {
 func (s *Stack) CreateNetwork(req resources.NetworkRequest) (res *resources.Network, err error) {
 	logrus.Warnf("CreateNetwork invocation")
 
+	// AWS subnets always have an IPv4 CIDR; IPv6 is available only as dual-stack, with a prefix chosen by AWS
+	if req.IPVersion == ipversion.IPv6 {
+		return nil, scerr.NotImplementedError("IPv6-only networks are not supported by AWS, use dual-stack instead")
+	}
+	if req.IPv6CIDR != "" {
+		return nil, scerr.InvalidRequestError("AWS chooses the IPv6 prefix of the network, it cannot be requested")
+	}
+	dualStack := req.IPVersion == ipversion.DualStack
+
 	var theVpc *ec2.Vpc
 
 	// Check if network already there
@@ -91,7 +100,8 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (res *resources.Netw
 	// if not, create the network
 	if theVpc == nil {
 		vpcOut, err := s.EC2Service.CreateVpc(&ec2.CreateVpcInput{
-			CidrBlock: aws.String(req.CIDR),
+			CidrBlock:                   aws.String(req.CIDR),
+			AmazonProvidedIpv6CidrBlock: aws.Bool(dualStack),
 		})
 		if err != nil {
 			return nil, scerr.Wrap(err, "Error creating VPC")
@@ -157,6 +167,14 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (res *resources.Netw
 		return nil, scerr.Wrap(err, "error parsing requested CIDR")
 	}
 
+	var parentNet6 *net.IPNet
+	if dualStack {
+		parentNet6, err = s.getVpcIPv6CIDR(theVpc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var subnets []*net.IPNet
 	var subnetsResult []*ec2.CreateSubnetOutput
 
@@ -191,17 +209,36 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (res *resources.Netw
 		}
 	}()
 
-	for _, snCidr := range subnets {
-		sn, err := s.EC2Service.CreateSubnet(&ec2.CreateSubnetInput{
+	for i, snCidr := range subnets {
+		input := &ec2.CreateSubnetInput{
 			CidrBlock:        aws.String(snCidr.String()),
 			VpcId:            theVpc.VpcId,
 			AvailabilityZone: aws.String(s.AwsConfig.Zone),
-		})
+		}
+		if parentNet6 != nil {
+			// AWS subnets use /64 IPv6 prefixes, taken in the /56 of the VPC
+			snCidr6, err := cidr.Subnet(parentNet6, 64-56, i)
+			if err != nil {
+				return nil, scerr.Wrap(err, "error preparing an IPv6 subnet")
+			}
+			input.Ipv6CidrBlock = aws.String(snCidr6.String())
+		}
+		sn, err := s.EC2Service.CreateSubnet(input)
 		if err != nil {
 			return nil, scerr.Wrap(err, "error creating a subnet")
 		}
 
 		subnetsResult = append(subnetsResult, sn)
+
+		if parentNet6 != nil {
+			_, err = s.EC2Service.ModifySubnetAttribute(&ec2.ModifySubnetAttributeInput{
+				SubnetId:                    sn.Subnet.SubnetId,
+				AssignIpv6AddressOnCreation: &ec2.AttributeBooleanValue{Value: aws.Bool(true)},
+			})
+			if err != nil {
+				return nil, scerr.Wrap(err, "error enabling IPv6 addresses on a subnet")
+			}
+		}
 	}
 
 	if len(subnetsResult) == 0 {
@@ -313,6 +350,29 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (res *resources.Netw
 		}
 	}()
 
+	if parentNet6 != nil {
+		_, err = s.EC2Service.CreateRoute(&ec2.CreateRouteInput{
+			DestinationIpv6CidrBlock: aws.String("::/0"),
+			GatewayId:                gw.InternetGateway.InternetGatewayId,
+			RouteTableId:             table.RouteTables[0].RouteTableId,
+		})
+		if err != nil {
+			return nil, scerr.Wrap(err, "CreateRoute")
+		}
+
+		defer func() {
+			if err != nil {
+				_, derr := s.EC2Service.DeleteRoute(&ec2.DeleteRouteInput{
+					DestinationIpv6CidrBlock: aws.String("::/0"),
+					RouteTableId:             table.RouteTables[0].RouteTableId,
+				})
+				if derr != nil {
+					err = scerr.AddConsequence(err, derr)
+				}
+			}
+		}()
+	}
+
 	// First result should be the public interface
 	sn := subnetsResult[0]
 	_, err = s.EC2Service.AssociateRouteTable(&ec2.AssociateRouteTableInput{
@@ -340,6 +400,10 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (res *resources.Netw
 	subnet.Name = req.Name
 	subnet.CIDR = req.CIDR // FIXME AWS Storing parent CIDR
 	subnet.IPVersion = ipversion.IPv4
+	if parentNet6 != nil {
+		subnet.IPv6CIDR = parentNet6.String()
+		subnet.IPVersion = ipversion.DualStack
+	}
 
 	for _, sn := range subnetsResult {
 		subnet.Subnetworks = append(subnet.Subnetworks, resources.SubNetwork{
@@ -354,6 +418,51 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (res *resources.Netw
 	return subnet, nil
 }
 
+// getVpcIPv6CIDR returns the IPv6 prefix provided by AWS to the VPC, asking for one if the VPC has none
+func (s *Stack) getVpcIPv6CIDR(vpc *ec2.Vpc) (*net.IPNet, error) {
+	if len(vpc.Ipv6CidrBlockAssociationSet) == 0 {
+		_, err := s.EC2Service.AssociateVpcCidrBlock(&ec2.AssociateVpcCidrBlockInput{
+			VpcId:                       vpc.VpcId,
+			AmazonProvidedIpv6CidrBlock: aws.Bool(true),
+		})
+		if err != nil {
+			return nil, scerr.Wrap(err, "error associating an IPv6 CIDR to VPC")
+		}
+	}
+
+	var ipv6CIDR string
+	retryErr := retry.WhileUnsuccessful(
+		func() error {
+			out, err := s.EC2Service.DescribeVpcs(&ec2.DescribeVpcsInput{
+				VpcIds: []*string{vpc.VpcId},
+			})
+			if err != nil {
+				return err
+			}
+			if len(out.Vpcs) > 0 {
+				for _, assoc := range out.Vpcs[0].Ipv6CidrBlockAssociationSet {
+					if assoc.Ipv6CidrBlockState != nil && aws.StringValue(assoc.Ipv6CidrBlockState.State) == "associated" {
+						ipv6CIDR = aws.StringValue(assoc.Ipv6CidrBlock)
+						return nil
+					}
+				}
+			}
+			return scerr.Errorf(fmt.Sprintf("IPv6 CIDR not associated yet"), nil)
+		},
+		temporal.GetMinDelay(),
+		temporal.GetDefaultDelay(),
+	)
+	if retryErr != nil {
+		return nil, retryErr
+	}
+
+	_, ipnet, err := net.ParseCIDR(ipv6CIDR)
+	if err != nil {
+		return nil, scerr.Wrap(err, "error parsing IPv6 CIDR of VPC")
+	}
+	return ipnet, nil
+}
+
 func (s *Stack) GetNetwork(id string) (*resources.Network, error) {
 	nets, err := s.ListNetworks()
 	if err != nil {
@@ -394,6 +503,11 @@ func (s *Stack) ListNetworks() ([]*resources.Network, error) {
 		vpcnet := resources.Network{}
 		vpcnet.ID = aws.StringValue(vpc.VpcId)
 		vpcnet.CIDR = aws.StringValue(vpc.CidrBlock)
+		vpcnet.IPVersion = ipversion.IPv4
+		if len(vpc.Ipv6CidrBlockAssociationSet) > 0 {
+			vpcnet.IPv6CIDR = aws.StringValue(vpc.Ipv6CidrBlockAssociationSet[0].Ipv6CidrBlock)
+			vpcnet.IPVersion = ipversion.DualStack
+		}
 		for _, tag := range vpc.Tags {
 			if aws.StringValue(tag.Key) == "Name" {
 				if aws.StringValue(tag.Value) != "" {
@@ -413,6 +527,11 @@ func (s *Stack) ListNetworks() ([]*resources.Network, error) {
 		vpcnet := resources.Network{}
 		vpcnet.ID = aws.StringValue(subn.SubnetId)
 		vpcnet.CIDR = aws.StringValue(subn.CidrBlock)
+		vpcnet.IPVersion = ipversion.IPv4
+		if len(subn.Ipv6CidrBlockAssociationSet) > 0 {
+			vpcnet.IPv6CIDR = aws.StringValue(subn.Ipv6CidrBlockAssociationSet[0].Ipv6CidrBlock)
+			vpcnet.IPVersion = ipversion.DualStack
+		}
 		vpcnet.Subnet = true
 		vpcnet.Parent = aws.StringValue(subn.VpcId)
 		for _, tag := range subn.Tags {
@@ -0,0 +1,187 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// AllocatePublicIP allocates an Elastic IP in the VPC domain and tags it with name
+func (s *Stack) AllocatePublicIP(name string) (*resources.PublicIP, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	out, err := s.EC2Service.AllocateAddress(&ec2.AllocateAddressInput{
+		Domain: aws.String("vpc"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.EC2Service.CreateTags(&ec2.CreateTagsInput{
+		Resources: []*string{out.AllocationId},
+		Tags: []*ec2.Tag{
+			{
+				Key:   aws.String("Name"),
+				Value: aws.String(name),
+			},
+		},
+	})
+	if err != nil {
+		_, derr := s.EC2Service.ReleaseAddress(&ec2.ReleaseAddressInput{
+			AllocationId: out.AllocationId,
+		})
+		if derr != nil {
+			err = scerr.AddConsequence(err, derr)
+		}
+		return nil, err
+	}
+
+	return &resources.PublicIP{
+		ID:      aws.StringValue(out.AllocationId),
+		Name:    name,
+		Address: aws.StringValue(out.PublicIp),
+	}, nil
+}
+
+// ListPublicIPs lists the Elastic IPs of the VPC domain
+func (s *Stack) ListPublicIPs() ([]*resources.PublicIP, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	out, err := s.EC2Service.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("domain"),
+				Values: []*string{aws.String("vpc")},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*resources.PublicIP
+	for _, addr := range out.Addresses {
+		list = append(list, toPublicIP(addr))
+	}
+	return list, nil
+}
+
+// AttachPublicIP associates the Elastic IP to the instance identified by hostID
+// An Elastic IP already associated to another instance is moved to hostID
+func (s *Stack) AttachPublicIP(ip *resources.PublicIP, hostID string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+
+	_, err := s.EC2Service.AssociateAddress(&ec2.AssociateAddressInput{
+		AllocationId:       aws.String(ip.ID),
+		InstanceId:         aws.String(hostID),
+		AllowReassociation: aws.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+	ip.HostID = hostID
+	return nil
+}
+
+// DetachPublicIP disassociates the Elastic IP from its instance
+func (s *Stack) DetachPublicIP(ip *resources.PublicIP) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+
+	addr, err := s.getAddress(ip.ID)
+	if err != nil {
+		return err
+	}
+	if addr.AssociationId != nil {
+		_, err = s.EC2Service.DisassociateAddress(&ec2.DisassociateAddressInput{
+			AssociationId: addr.AssociationId,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	ip.HostID = ""
+	return nil
+}
+
+// ReleasePublicIP releases the Elastic IP
+func (s *Stack) ReleasePublicIP(ip *resources.PublicIP) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+
+	_, err := s.EC2Service.ReleaseAddress(&ec2.ReleaseAddressInput{
+		AllocationId: aws.String(ip.ID),
+	})
+	return err
+}
+
+// getAddress returns the Elastic IP identified by its allocation id
+func (s *Stack) getAddress(allocationID string) (*ec2.Address, error) {
+	out, err := s.EC2Service.DescribeAddresses(&ec2.DescribeAddressesInput{
+		AllocationIds: []*string{aws.String(allocationID)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.Addresses) == 0 {
+		return nil, scerr.NotFoundError(fmt.Sprintf("failed to find Elastic IP '%s'", allocationID))
+	}
+	return out.Addresses[0], nil
+}
+
+func toPublicIP(addr *ec2.Address) *resources.PublicIP {
+	ip := &resources.PublicIP{
+		ID:      aws.StringValue(addr.AllocationId),
+		Address: aws.StringValue(addr.PublicIp),
+		HostID:  aws.StringValue(addr.InstanceId),
+	}
+	for _, tag := range addr.Tags {
+		if aws.StringValue(tag.Key) == "Name" {
+			ip.Name = aws.StringValue(tag.Value)
+		}
+	}
+	return ip
+}
@@ -28,10 +28,14 @@ import (
 )
 
 func (s *Stack) CreateVolume(request resources.VolumeRequest) (*resources.Volume, error) {
+	zone := request.AvailabilityZone
+	if zone == "" {
+		zone = s.AwsConfig.Zone
+	}
 	v, err := s.EC2Service.CreateVolume(&ec2.CreateVolumeInput{
 		Size:             aws.Int64(int64(request.Size)),
 		VolumeType:       aws.String(toVolumeType(request.Speed)),
-		AvailabilityZone: aws.String(s.AwsConfig.Zone),
+		AvailabilityZone: aws.String(zone),
 	})
 	if err != nil {
 		return nil, err
@@ -974,6 +974,23 @@ func (s *StackEbrc) RebootHost(id string) error {
 	return err
 }
 
+// GetHostConsoleOutput returns the console output of the host identified by id
+func (s *StackEbrc) GetHostConsoleOutput(id string) (string, error) {
+	if s == nil {
+		return "", scerr.InvalidInstanceError()
+	}
+	return "", scerr.NotImplementedError("GetHostConsoleOutput() not implemented yet") // FIXME Technical debt
+}
+
+// IsHostReclaimed tells if the spot host identified by id has been reclaimed; ebrc cannot create spot hosts,
+// so no host is ever reclaimed
+func (s *StackEbrc) IsHostReclaimed(id string) (bool, error) {
+	if s == nil {
+		return false, scerr.InvalidInstanceError()
+	}
+	return false, nil
+}
+
 // GetHostState returns the host identified by id
 func (s *StackEbrc) GetHostState(hostParam interface{}) (hoststate.Enum, error) {
 	logrus.Debug("ebrc.Client.RebootHost() called")
@@ -1020,3 +1037,23 @@ func (s *StackEbrc) UnbindHostFromVIP(ip *resources.VirtualIP, s2 string) error
 func (s *StackEbrc) DeleteVIP(ip *resources.VirtualIP) error {
 	return scerr.NotImplementedError("DeleteVIP() not implemented yet") // FIXME Technical debt
 }
+
+func (s *StackEbrc) AllocatePublicIP(name string) (*resources.PublicIP, error) {
+	return nil, scerr.NotImplementedError("AllocatePublicIP() not implemented yet") // FIXME Technical debt
+}
+
+func (s *StackEbrc) ListPublicIPs() ([]*resources.PublicIP, error) {
+	return nil, scerr.NotImplementedError("ListPublicIPs() not implemented yet") // FIXME Technical debt
+}
+
+func (s *StackEbrc) AttachPublicIP(ip *resources.PublicIP, hostID string) error {
+	return scerr.NotImplementedError("AttachPublicIP() not implemented yet") // FIXME Technical debt
+}
+
+func (s *StackEbrc) DetachPublicIP(ip *resources.PublicIP) error {
+	return scerr.NotImplementedError("DetachPublicIP() not implemented yet") // FIXME Technical debt
+}
+
+func (s *StackEbrc) ReleasePublicIP(ip *resources.PublicIP) error {
+	return scerr.NotImplementedError("ReleasePublicIP() not implemented yet") // FIXME Technical debt
+}
@@ -377,7 +377,7 @@ func (s *Stack) CreateHost(request resources.HostRequest) (host *resources.Host,
 	// Retry creation until success, for 10 minutes
 	retryErr := retry.WhileUnsuccessfulDelay5Seconds(
 		func() error {
-			server, err := buildGcpMachine(s.ComputeService, s.GcpConfig.ProjectID, request.ResourceName, rim.URL, s.GcpConfig.Region, s.GcpConfig.Zone, s.GcpConfig.NetworkName, defaultNetwork.Name, string(userDataPhase1), isGateway, template)
+			server, err := buildGcpMachine(s.ComputeService, s.GcpConfig.ProjectID, request.ResourceName, rim.URL, s.GcpConfig.Region, s.GcpConfig.Zone, s.GcpConfig.NetworkName, defaultNetwork.Name, defaultNetwork.IPVersion.HasIPv6(), string(userDataPhase1), isGateway, request.Spot, template)
 			if err != nil {
 				if server != nil {
 					// try deleting server
@@ -526,7 +526,7 @@ func publicAccess(isPublic bool) []*compute.AccessConfig {
 		return []*compute.AccessConfig{
 			{
 				Type: "ONE_TO_ONE_NAT",
-				Name: "External NAT",
+				Name: publicIPAccessConfigName,
 			},
 		}
 	}
@@ -535,11 +535,16 @@ func publicAccess(isPublic bool) []*compute.AccessConfig {
 }
 
 // buildGcpMachine ...
-func buildGcpMachine(service *compute.Service, projectID string, instanceName string, imageID string, region string, zone string, network string, subnetwork string, userdata string, isPublic bool, template *resources.HostTemplate) (*resources.Host, error) {
+func buildGcpMachine(service *compute.Service, projectID string, instanceName string, imageID string, region string, zone string, network string, subnetwork string, dualStack bool, userdata string, isPublic bool, preemptible bool, template *resources.HostTemplate) (*resources.Host, error) {
 	prefix := "https://www.googleapis.com/compute/v1/projects/" + projectID
 
 	imageURL := imageID
 
+	stackType := "IPV4_ONLY"
+	if dualStack {
+		stackType = "IPV4_IPV6"
+	}
+
 	tag := "nat"
 	if !isPublic {
 		tag = fmt.Sprintf("no-ip-%s", subnetwork)
@@ -570,6 +575,7 @@ func buildGcpMachine(service *compute.Service, projectID string, instanceName st
 				AccessConfigs: publicAccess(isPublic),
 				Network:       prefix + "/global/networks/" + network,
 				Subnetwork:    prefix + "/regions/" + region + "/subnetworks/" + subnetwork,
+				StackType:     stackType,
 			},
 		},
 		ServiceAccounts: []*compute.ServiceAccount{
@@ -590,6 +596,14 @@ func buildGcpMachine(service *compute.Service, projectID string, instanceName st
 			},
 		},
 	}
+	// Preemptible instances cannot be restarted automatically, nor live migrated during maintenance
+	if preemptible {
+		instance.Scheduling = &compute.Scheduling{
+			Preemptible:       true,
+			AutomaticRestart:  googleapi.Bool(false),
+			OnHostMaintenance: "TERMINATE",
+		}
+	}
 
 	op, err := service.Instances.Insert(projectID, zone, instance).Do()
 	if err != nil {
@@ -687,6 +701,7 @@ func (s *Stack) InspectHost(hostParam interface{}) (host *resources.Host, err er
 			subnets = append(subnets, IPInSubnet{
 				Subnet:   snet,
 				IP:       nit.NetworkIP,
+				IPv6:     nit.Ipv6Address,
 				PublicIP: pubIP,
 			})
 		}
@@ -708,17 +723,22 @@ func (s *Stack) InspectHost(hostParam interface{}) (host *resources.Host, err er
 			Name:     psg.Name,
 			ID:       strconv.FormatUint(psg.Id, 10),
 			IP:       sn.IP,
+			IPv6:     sn.IPv6,
 			PublicIP: sn.PublicIP,
 		})
 	}
 
 	ip4bynetid := make(map[string]string)
+	ip6bynetid := make(map[string]string)
 	netnamebyid := make(map[string]string)
 	netidbyname := make(map[string]string)
 
 	ipv4 := ""
 	for _, rn := range resouceNetworks {
 		ip4bynetid[rn.ID] = rn.IP
+		if rn.IPv6 != "" {
+			ip6bynetid[rn.ID] = rn.IPv6
+		}
 		netnamebyid[rn.ID] = rn.Name
 		netidbyname[rn.Name] = rn.ID
 		if rn.PublicIP != "" {
@@ -729,7 +749,7 @@ func (s *Stack) InspectHost(hostParam interface{}) (host *resources.Host, err er
 	err = host.Properties.LockForWrite(hostproperty.NetworkV1).ThenUse(func(clonable data.Clonable) error {
 		hostNetworkV1 := clonable.(*propsv1.HostNetwork)
 		hostNetworkV1.IPv4Addresses = ip4bynetid
-		hostNetworkV1.IPv6Addresses = make(map[string]string)
+		hostNetworkV1.IPv6Addresses = ip6bynetid
 		hostNetworkV1.NetworksByID = netnamebyid
 		hostNetworkV1.NetworksByName = netidbyname
 		if hostNetworkV1.PublicIPv4 == "" {
@@ -957,6 +977,75 @@ func (s *Stack) StartHost(id string) error {
 	return err
 }
 
+// GetHostConsoleOutput returns the output of the serial port of the host identified by id
+func (s *Stack) GetHostConsoleOutput(id string) (string, error) {
+	if s == nil {
+		return "", scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return "", scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	output, err := s.ComputeService.Instances.GetSerialPortOutput(s.GcpConfig.ProjectID, s.GcpConfig.Zone, id).Do()
+	if err != nil {
+		return "", err
+	}
+	return output.Contents, nil
+}
+
+// IsHostReclaimed tells if the preemptible instance identified by id has been preempted; a preempted instance stays
+// in status TERMINATED like a stopped one, only the preemption operation tells them apart
+func (s *Stack) IsHostReclaimed(id string) (bool, error) {
+	if s == nil {
+		return false, scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return false, scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	instance, err := s.ComputeService.Instances.Get(s.GcpConfig.ProjectID, s.GcpConfig.Zone, id).Do()
+	if err != nil {
+		if gerr, ok := err.(*googleapi.Error); ok && gerr.Code == 404 {
+			return true, nil
+		}
+		return false, err
+	}
+	if instance.Status != "TERMINATED" || instance.Scheduling == nil || !instance.Scheduling.Preemptible {
+		return false, nil
+	}
+
+	filter := fmt.Sprintf("(operationType = \"%s\") AND (targetId = \"%d\")", preemptedOperationType, instance.Id)
+	ops, err := s.ComputeService.ZoneOperations.List(s.GcpConfig.ProjectID, s.GcpConfig.Zone).Filter(filter).Do()
+	if err != nil {
+		return false, err
+	}
+	return wasPreempted(instance, ops.Items), nil
+}
+
+// preemptedOperationType is the type of the operation recorded by GCP when it preempts an instance
+const preemptedOperationType = "compute.instances.preempted"
+
+// wasPreempted tells if the instance, terminated, has been preempted since its last start
+func wasPreempted(instance *compute.Instance, ops []*compute.Operation) bool {
+	if instance.Status != "TERMINATED" {
+		return false
+	}
+	lastStart, err := time.Parse(time.RFC3339, instance.LastStartTimestamp)
+	if err != nil {
+		lastStart = time.Time{}
+	}
+	for _, op := range ops {
+		if op.OperationType != preemptedOperationType || op.TargetId != instance.Id {
+			continue
+		}
+		inserted, err := time.Parse(time.RFC3339, op.InsertTime)
+		if err != nil || !inserted.Before(lastStart) {
+			return true
+		}
+	}
+	return false
+}
+
 // RebootHost reboot the host identified by id
 func (s *Stack) RebootHost(id string) error {
 	if s == nil {
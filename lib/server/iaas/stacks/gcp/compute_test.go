@@ -0,0 +1,63 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestWasPreempted(t *testing.T) {
+	instance := &compute.Instance{
+		Id:                 42,
+		Status:             "TERMINATED",
+		LastStartTimestamp: "2020-03-01T10:00:00.000-08:00",
+		Scheduling:         &compute.Scheduling{Preemptible: true},
+	}
+	preempted := &compute.Operation{
+		OperationType: preemptedOperationType,
+		TargetId:      42,
+		InsertTime:    "2020-03-01T20:00:00.000+01:00",
+	}
+	old := &compute.Operation{
+		OperationType: preemptedOperationType,
+		TargetId:      42,
+		InsertTime:    "2020-02-28T10:00:00.000-08:00",
+	}
+	stopped := &compute.Operation{
+		OperationType: "stop",
+		TargetId:      42,
+		InsertTime:    "2020-03-01T12:00:00.000-08:00",
+	}
+
+	// Preempted after its last start
+	assert.True(t, wasPreempted(instance, []*compute.Operation{stopped, preempted}))
+	// Stopped by someone, preempted only before its last start
+	assert.False(t, wasPreempted(instance, []*compute.Operation{old, stopped}))
+	assert.False(t, wasPreempted(instance, nil))
+	// Preemption of another instance
+	other := *preempted
+	other.TargetId = 43
+	assert.False(t, wasPreempted(instance, []*compute.Operation{&other}))
+
+	// Restarted since its preemption
+	running := *instance
+	running.Status = "RUNNING"
+	assert.False(t, wasPreempted(&running, []*compute.Operation{preempted}))
+}
@@ -43,10 +43,20 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (*resources.Network,
 		return nil, scerr.InvalidInstanceError()
 	}
 
+	// GCP subnetworks always have an IPv4 range; IPv6 is available only as dual-stack, with a prefix chosen by GCP
+	if req.IPVersion == ipversion.IPv6 {
+		return nil, scerr.NotImplementedError("IPv6-only networks are not supported by GCP, use dual-stack instead")
+	}
+	if req.IPv6CIDR != "" {
+		return nil, scerr.InvalidRequestError("GCP chooses the IPv6 prefix of the network, it cannot be requested")
+	}
+	dualStack := req.IPVersion == ipversion.DualStack
+
 	// disable subnetwork auto-creation
 	ne := compute.Network{
 		Name:                  s.GcpConfig.NetworkName,
 		AutoCreateSubnetworks: false,
+		EnableUlaInternalIpv6: dualStack,
 		ForceSendFields:       []string{"AutoCreateSubnetworks"},
 	}
 
@@ -85,6 +95,27 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (*resources.Network,
 		}
 	}
 
+	// the network must provide internal IPv6 prefixes to create dual-stack subnetworks in it
+	if dualStack && !recreateSafescaleNetwork && !recnet.EnableUlaInternalIpv6 {
+		patch := compute.Network{EnableUlaInternalIpv6: true}
+		opp, err := compuService.Networks.Patch(s.GcpConfig.ProjectID, ne.Name, &patch).Context(context.Background()).Do()
+		if err != nil {
+			return nil, err
+		}
+
+		oco := OpContext{
+			Operation:    opp,
+			ProjectID:    s.GcpConfig.ProjectID,
+			Service:      compuService,
+			DesiredState: "DONE",
+		}
+
+		err = waitUntilOperationIsSuccessfulOrTimeout(oco, temporal.GetMinDelay(), 2*temporal.GetContextTimeout())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	necreated, err := compuService.Networks.Get(s.GcpConfig.ProjectID, ne.Name).Do()
 	if err != nil {
 		return nil, err
@@ -104,6 +135,10 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (*resources.Network,
 		Network:     fmt.Sprintf("projects/%s/global/networks/%s", s.GcpConfig.ProjectID, s.GcpConfig.NetworkName),
 		Region:      theRegion,
 	}
+	if dualStack {
+		subnetReq.StackType = "IPV4_IPV6"
+		subnetReq.Ipv6AccessType = "INTERNAL"
+	}
 
 	opp, err := compuService.Subnetworks.Insert(s.GcpConfig.ProjectID, theRegion, &subnetReq).Context(context.Background()).Do()
 	if err != nil {
@@ -133,6 +168,10 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (*resources.Network,
 	subnet.Name = gcpSubNet.Name
 	subnet.CIDR = gcpSubNet.IpCidrRange
 	subnet.IPVersion = ipversion.IPv4
+	if gcpSubNet.StackType == "IPV4_IPV6" {
+		subnet.IPv6CIDR = gcpSubNet.InternalIpv6Prefix
+		subnet.IPVersion = ipversion.DualStack
+	}
 
 	buildNewRule := true
 	firewallRuleName := fmt.Sprintf("%s-%s-all-in", s.GcpConfig.NetworkName, gcpSubNet.Name)
@@ -309,6 +348,11 @@ func (s *Stack) ListNetworks() ([]*resources.Network, error) {
 			newNet.Name = nett.Name
 			newNet.ID = strconv.FormatUint(nett.Id, 10)
 			newNet.CIDR = nett.IpCidrRange
+			newNet.IPVersion = ipversion.IPv4
+			if nett.StackType == "IPV4_IPV6" {
+				newNet.IPv6CIDR = nett.InternalIpv6Prefix
+				newNet.IPVersion = ipversion.DualStack
+			}
 
 			networks = append(networks, newNet)
 		}
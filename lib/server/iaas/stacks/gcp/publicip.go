@@ -0,0 +1,241 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/compute/v1"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// publicIPAccessConfigName is the name of the access config giving public access to an instance, as set by publicAccess
+const publicIPAccessConfigName = "External NAT"
+
+// AllocatePublicIP reserves a static external address in the region of the stack
+// On GCP, the name of the address is also its ID
+func (s *Stack) AllocatePublicIP(name string) (*resources.PublicIP, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	service := s.ComputeService
+
+	op, err := service.Addresses.Insert(s.GcpConfig.ProjectID, s.GcpConfig.Region, &compute.Address{
+		Name:        name,
+		AddressType: "EXTERNAL",
+	}).Do()
+	if err != nil {
+		return nil, err
+	}
+	err = s.waitForOperation(op)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := service.Addresses.Get(s.GcpConfig.ProjectID, s.GcpConfig.Region, name).Do()
+	if err != nil {
+		return nil, err
+	}
+	return s.toPublicIP(address)
+}
+
+// ListPublicIPs lists the static external addresses of the region
+// HostID of the addresses in use is the ID of the instance using them
+func (s *Stack) ListPublicIPs() ([]*resources.PublicIP, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	var list []*resources.PublicIP
+	token := ""
+	for {
+		resp, err := s.ComputeService.Addresses.List(s.GcpConfig.ProjectID, s.GcpConfig.Region).PageToken(token).Do()
+		if err != nil {
+			return nil, err
+		}
+		for _, address := range resp.Items {
+			if address.AddressType != "" && address.AddressType != "EXTERNAL" {
+				continue
+			}
+			ip, err := s.toPublicIP(address)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, ip)
+		}
+		if token = resp.NextPageToken; token == "" {
+			break
+		}
+	}
+	return list, nil
+}
+
+// AttachPublicIP replaces the access config of the first interface of the instance identified by hostID
+// with one using the static address
+func (s *Stack) AttachPublicIP(ip *resources.PublicIP, hostID string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+
+	if ip.HostID != "" && ip.HostID != hostID {
+		err := s.DetachPublicIP(ip)
+		if err != nil {
+			return err
+		}
+	}
+
+	service := s.ComputeService
+
+	instance, err := service.Instances.Get(s.GcpConfig.ProjectID, s.GcpConfig.Zone, hostID).Do()
+	if err != nil {
+		return err
+	}
+	if len(instance.NetworkInterfaces) == 0 {
+		return scerr.InconsistentError(fmt.Sprintf("instance '%s' has no network interface", hostID))
+	}
+	nic := instance.NetworkInterfaces[0]
+	for _, ac := range nic.AccessConfigs {
+		op, err := service.Instances.DeleteAccessConfig(s.GcpConfig.ProjectID, s.GcpConfig.Zone, instance.Name, ac.Name, nic.Name).Do()
+		if err != nil {
+			return err
+		}
+		err = s.waitForOperation(op)
+		if err != nil {
+			return err
+		}
+	}
+
+	op, err := service.Instances.AddAccessConfig(s.GcpConfig.ProjectID, s.GcpConfig.Zone, instance.Name, nic.Name, &compute.AccessConfig{
+		Type:  "ONE_TO_ONE_NAT",
+		Name:  publicIPAccessConfigName,
+		NatIP: ip.Address,
+	}).Do()
+	if err != nil {
+		return err
+	}
+	err = s.waitForOperation(op)
+	if err != nil {
+		return err
+	}
+	ip.HostID = hostID
+	return nil
+}
+
+// DetachPublicIP removes the access config using the static address from the instance using it
+func (s *Stack) DetachPublicIP(ip *resources.PublicIP) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+
+	service := s.ComputeService
+
+	address, err := service.Addresses.Get(s.GcpConfig.ProjectID, s.GcpConfig.Region, ip.ID).Do()
+	if err != nil {
+		return err
+	}
+	for _, user := range address.Users {
+		instanceName := getResourceNameFromSelfLink(genURL(user))
+		instance, err := service.Instances.Get(s.GcpConfig.ProjectID, s.GcpConfig.Zone, instanceName).Do()
+		if err != nil {
+			return err
+		}
+		for _, nic := range instance.NetworkInterfaces {
+			for _, ac := range nic.AccessConfigs {
+				if ac.NatIP != address.Address {
+					continue
+				}
+				op, err := service.Instances.DeleteAccessConfig(s.GcpConfig.ProjectID, s.GcpConfig.Zone, instance.Name, ac.Name, nic.Name).Do()
+				if err != nil {
+					return err
+				}
+				err = s.waitForOperation(op)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	ip.HostID = ""
+	return nil
+}
+
+// ReleasePublicIP deletes the static external address
+func (s *Stack) ReleasePublicIP(ip *resources.PublicIP) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+
+	op, err := s.ComputeService.Addresses.Delete(s.GcpConfig.ProjectID, s.GcpConfig.Region, ip.ID).Do()
+	if err != nil {
+		return err
+	}
+	return s.waitForOperation(op)
+}
+
+func (s *Stack) waitForOperation(op *compute.Operation) error {
+	oco := OpContext{
+		Operation:    op,
+		ProjectID:    s.GcpConfig.ProjectID,
+		Service:      s.ComputeService,
+		DesiredState: "DONE",
+	}
+	return waitUntilOperationIsSuccessfulOrTimeout(oco, temporal.GetMinDelay(), temporal.GetHostTimeout())
+}
+
+// toPublicIP converts a static external address to a PublicIP
+// The users of the addresses are referenced by name, HostID is the ID of the instance using the address like for hosts
+func (s *Stack) toPublicIP(address *compute.Address) (*resources.PublicIP, error) {
+	ip := &resources.PublicIP{
+		ID:      address.Name,
+		Name:    address.Name,
+		Address: address.Address,
+	}
+	for _, user := range address.Users {
+		if !strings.Contains(user, "/instances/") {
+			continue
+		}
+		instanceName := getResourceNameFromSelfLink(genURL(user))
+		instance, err := s.ComputeService.Instances.Get(s.GcpConfig.ProjectID, s.GcpConfig.Zone, instanceName).Do()
+		if err != nil {
+			return nil, err
+		}
+		ip.HostID = strconv.FormatUint(instance.Id, 10)
+		break
+	}
+	return ip, nil
+}
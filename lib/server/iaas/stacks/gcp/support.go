@@ -90,6 +90,7 @@ type IPInSubnet struct {
 	Name     string
 	ID       string
 	IP       string
+	IPv6     string
 	PublicIP string
 }
 
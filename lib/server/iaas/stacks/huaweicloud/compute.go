@@ -471,6 +471,19 @@ func (s *Stack) CreateHost(request resources.HostRequest) (host *resources.Host,
 				}
 			}()
 
+			// Volumes created later for the host must be in its availability zone
+			hostZone := srvOpts.AvailabilityZone
+			if zoneErr == nil {
+				hostZone = creationZone
+			}
+			ierr = host.Properties.LockForWrite(hostproperty.DescriptionV1).ThenUse(func(clonable data.Clonable) error {
+				clonable.(*propsv1.HostDescription).AvailabilityZone = hostZone
+				return nil
+			})
+			if ierr != nil {
+				return ierr
+			}
+
 			// Wait that Host is ready, not just that the build is started
 			var srv *servers.Server
 			srv, ierr = s.waitHostState(host, []hoststate.Enum{hoststate.STARTED}, temporal.GetHostTimeout())
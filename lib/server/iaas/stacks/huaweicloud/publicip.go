@@ -0,0 +1,126 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package huaweicloud
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/openstack"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// AllocatePublicIP creates a VPC public IP
+// Public IPs have no name here, the name is only kept in SafeScale metadata
+func (s *Stack) AllocatePublicIP(name string) (*resources.PublicIP, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	fip, err := s.CreateFloatingIP()
+	if err != nil {
+		return nil, err
+	}
+	return &resources.PublicIP{
+		ID:      fip.ID,
+		Name:    name,
+		Address: fip.PublicIPAddress,
+	}, nil
+}
+
+// ListPublicIPs lists the VPC public IPs of the project
+func (s *Stack) ListPublicIPs() ([]*resources.PublicIP, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	var list []*resources.PublicIP
+	err := s.ListFloatingIPs().EachPage(func(page pagination.Page) (bool, error) {
+		fips, err := extractFloatingIPs(page)
+		if err != nil {
+			return false, err
+		}
+		for _, fip := range fips {
+			list = append(list, &resources.PublicIP{
+				ID:      fip.ID,
+				Address: fip.PublicIPAddress,
+			})
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, scerr.Errorf(fmt.Sprintf("failed to list public IPs: %s", openstack.ProviderErrorToString(err)), err)
+	}
+	return list, nil
+}
+
+// AttachPublicIP associates the public IP to the host identified by hostID
+func (s *Stack) AttachPublicIP(ip *resources.PublicIP, hostID string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+
+	err := s.AssociateFloatingIP(&resources.Host{ID: hostID, Name: hostID}, ip.ID)
+	if err != nil {
+		return err
+	}
+	ip.HostID = hostID
+	return nil
+}
+
+// DetachPublicIP dissociates the public IP from its host
+func (s *Stack) DetachPublicIP(ip *resources.PublicIP) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+
+	if ip.HostID == "" {
+		return nil
+	}
+	err := s.DissociateFloatingIP(&resources.Host{ID: ip.HostID, Name: ip.HostID}, ip.ID)
+	if err != nil {
+		return err
+	}
+	ip.HostID = ""
+	return nil
+}
+
+// ReleasePublicIP deletes the VPC public IP
+func (s *Stack) ReleasePublicIP(ip *resources.PublicIP) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+
+	return s.DeleteFloatingIP(ip.ID)
+}
@@ -91,9 +91,12 @@ func (s *Stack) CreateVolume(request resources.VolumeRequest) (*resources.Volume
 		return nil, scerr.Errorf(fmt.Sprintf("volume '%s' already exists", request.Name), err)
 	}
 
-	az, err := s.SelectedAvailabilityZone()
-	if err != nil {
-		return nil, err
+	az := request.AvailabilityZone
+	if az == "" {
+		az, err = s.SelectedAvailabilityZone()
+		if err != nil {
+			return nil, err
+		}
 	}
 	opts := volumes.CreateOpts{
 		AvailabilityZone: az,
@@ -24,6 +24,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -938,7 +939,7 @@ func (s *Stack) CreateHost(request resources.HostRequest) (host *resources.Host,
 	commandSysprep := fmt.Sprintf("virt-sysprep -a $VM_IMAGE --hostname %s --operations defaults,-ssh-hostkeys --firstboot %s && rm %s", hostName, userdataFileName, userdataFileName)
 	commands = append(commands, commandSysprep)
 
-	commandVirtInstall := fmt.Sprintf("virt-install --connect \"%s\" --noautoconsole --name=%s --vcpus=%d --memory=%d --import --disk=$VM_IMAGE %s", s.LibvirtConfig.URI, resourceName, template.Cores, int(template.RAMSize*1024), networksCommandString)
+	commandVirtInstall := fmt.Sprintf("virt-install --connect \"%s\" --noautoconsole --name=%s --vcpus=%d --memory=%d --import --disk=$VM_IMAGE --serial file,path=%s %s", s.LibvirtConfig.URI, resourceName, template.Cores, int(template.RAMSize*1024), s.consoleLogPath(resourceName), networksCommandString)
 	commands = append(commands, commandVirtInstall)
 
 	for _, command := range commands {
@@ -1113,6 +1114,9 @@ func (s *Stack) DeleteHost(id string) error {
 	if err != nil {
 		return scerr.Errorf(fmt.Sprintf("failed to undefine the domain : %s", err.Error()), err)
 	}
+	if domainName, err := domain.GetName(); err == nil {
+		_ = os.Remove(s.consoleLogPath(domainName))
+	}
 
 	for _, volume := range volumes {
 		volumePath := volume.Key
@@ -1199,6 +1203,46 @@ func (s *Stack) StartHost(id string) error {
 	return nil
 }
 
+// consoleLogPath returns the path of the file receiving the output of the serial console of the domain
+func (s *Stack) consoleLogPath(domainName string) string {
+	return s.LibvirtConfig.LibvirtStorage + "/" + domainName + "_console.log"
+}
+
+// GetHostConsoleOutput returns the content of the file receiving the serial console of the host identified by id;
+// the file is written on the hypervisor, so it can be read only if the hypervisor is local
+func (s *Stack) GetHostConsoleOutput(id string) (string, error) {
+	if s == nil {
+		return "", scerr.InvalidInstanceError()
+	}
+	if u, err := url.Parse(s.LibvirtConfig.URI); err != nil || u.Host != "" {
+		return "", scerr.NotAvailableError(fmt.Sprintf("console output of the host is not available with remote hypervisor '%s'", s.LibvirtConfig.URI))
+	}
+
+	_, domain, err := s.getHostAndDomainFromRef(id)
+	if err != nil {
+		return "", scerr.Errorf(fmt.Sprintf(fmt.Sprintf("getHostAndDomainFromRef failed : %s", err.Error())), err)
+	}
+	domainName, err := domain.GetName()
+	if err != nil {
+		return "", scerr.Errorf(fmt.Sprintf("failed to get domain name : %s", err.Error()), err)
+	}
+
+	content, err := ioutil.ReadFile(s.consoleLogPath(domainName))
+	if err != nil {
+		return "", scerr.Errorf(fmt.Sprintf("failed to read console output of the host : %s", err.Error()), err)
+	}
+	return string(content), nil
+}
+
+// IsHostReclaimed tells if the spot host identified by id has been reclaimed; libvirt cannot create spot hosts,
+// so no host is ever reclaimed
+func (s *Stack) IsHostReclaimed(id string) (bool, error) {
+	if s == nil {
+		return false, scerr.InvalidInstanceError()
+	}
+	return false, nil
+}
+
 // RebootHost reboot the host identified by id
 func (s *Stack) RebootHost(id string) error {
 	if s == nil {
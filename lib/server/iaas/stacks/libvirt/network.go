@@ -355,3 +355,28 @@ func (s *Stack) UnbindHostFromVIP(vip *resources.VirtualIP, hostID string) error
 func (s *Stack) DeleteVIP(vip *resources.VirtualIP) error {
 	return scerr.NotImplementedError("DeleteVIP() not implemented yet") // FIXME Technical debt
 }
+
+// AllocatePublicIP is not implemented for libvirt
+func (s *Stack) AllocatePublicIP(name string) (*resources.PublicIP, error) {
+	return nil, scerr.NotImplementedError("AllocatePublicIP() not implemented yet") // FIXME Technical debt
+}
+
+// ListPublicIPs is not implemented for libvirt
+func (s *Stack) ListPublicIPs() ([]*resources.PublicIP, error) {
+	return nil, scerr.NotImplementedError("ListPublicIPs() not implemented yet") // FIXME Technical debt
+}
+
+// AttachPublicIP is not implemented for libvirt
+func (s *Stack) AttachPublicIP(ip *resources.PublicIP, hostID string) error {
+	return scerr.NotImplementedError("AttachPublicIP() not implemented yet") // FIXME Technical debt
+}
+
+// DetachPublicIP is not implemented for libvirt
+func (s *Stack) DetachPublicIP(ip *resources.PublicIP) error {
+	return scerr.NotImplementedError("DetachPublicIP() not implemented yet") // FIXME Technical debt
+}
+
+// ReleasePublicIP is not implemented for libvirt
+func (s *Stack) ReleasePublicIP(ip *resources.PublicIP) error {
+	return scerr.NotImplementedError("ReleasePublicIP() not implemented yet") // FIXME Technical debt
+}
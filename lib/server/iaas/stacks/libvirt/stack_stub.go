@@ -145,6 +145,31 @@ func (s *Stack) DeleteVIP(vip *resources.VirtualIP) error {
 	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
 }
 
+// AllocatePublicIP stub
+func (s *Stack) AllocatePublicIP(name string) (*resources.PublicIP, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// ListPublicIPs stub
+func (s *Stack) ListPublicIPs() ([]*resources.PublicIP, error) {
+	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// AttachPublicIP stub
+func (s *Stack) AttachPublicIP(ip *resources.PublicIP, hostID string) error {
+	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// DetachPublicIP stub
+func (s *Stack) DetachPublicIP(ip *resources.PublicIP) error {
+	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// ReleasePublicIP stub
+func (s *Stack) ReleasePublicIP(ip *resources.PublicIP) error {
+	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
 // CreateHost stub
 func (s *Stack) CreateHost(request resources.HostRequest) (*resources.Host, *userdata.Content, error) {
 	return nil, nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
@@ -195,6 +220,16 @@ func (s *Stack) RebootHost(id string) error {
 	return scerr.Errorf(fmt.Sprintf(errorStr), nil)
 }
 
+// GetHostConsoleOutput stub
+func (s *Stack) GetHostConsoleOutput(id string) (string, error) {
+	return "", scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
+// IsHostReclaimed stub
+func (s *Stack) IsHostReclaimed(id string) (bool, error) {
+	return false, scerr.Errorf(fmt.Sprintf(errorStr), nil)
+}
+
 // CreateVolume stub
 func (s *Stack) CreateVolume(request resources.VolumeRequest) (*resources.Volume, error) {
 	return nil, scerr.Errorf(fmt.Sprintf(errorStr), nil)
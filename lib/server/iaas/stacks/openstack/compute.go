@@ -754,6 +754,8 @@ func (s *Stack) CreateHost(request resources.HostRequest) (host *resources.Host,
 		msg := fmt.Sprintf("failed to prepare user data content: %+v", err)
 		return nil, userData, scerr.Errorf(msg, err)
 	}
+	// Without router, IPv6 subnets are created without router advertisement mode (see createSubnet)
+	userData.AdvertiseIPv6 = userData.IsGateway && userData.IPv6CIDR != ""
 
 	// Determine system disk size based on vcpus count
 	template, err := s.GetTemplate(request.TemplateID)
@@ -854,6 +856,19 @@ func (s *Stack) CreateHost(request resources.HostRequest) (host *resources.Host,
 				}
 			}()
 
+			// Volumes created later for the host must be in its availability zone
+			hostZone := srvOpts.AvailabilityZone
+			if zoneErr == nil {
+				hostZone = creationZone
+			}
+			ierr = host.Properties.LockForWrite(hostproperty.DescriptionV1).ThenUse(func(clonable data.Clonable) error {
+				clonable.(*propsv1.HostDescription).AvailabilityZone = hostZone
+				return nil
+			})
+			if ierr != nil {
+				return ierr
+			}
+
 			// Wait that Host is ready, not just that the build is started
 			var srv *servers.Server
 			srv, ierr = s.waitHostState(host, []hoststate.Enum{hoststate.STARTED}, temporal.GetHostTimeout())
@@ -1438,6 +1453,34 @@ func (s *Stack) RebootHost(id string) error {
 	return nil
 }
 
+// GetHostConsoleOutput returns the console output of the host identified by id (os-getConsoleOutput action)
+func (s *Stack) GetHostConsoleOutput(id string) (string, error) {
+	if s == nil {
+		return "", scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return "", scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s)", id), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	output, err := servers.ShowConsoleOutput(s.ComputeClient, id, servers.ShowConsoleOutputOpts{}).Extract()
+	if err != nil {
+		ftErr := fmt.Sprintf("error getting console output of host [%s]: %s", id, ProviderErrorToString(err))
+		return "", scerr.Wrap(err, ftErr)
+	}
+	return output, nil
+}
+
+// IsHostReclaimed tells if the spot host identified by id has been reclaimed; OpenStack cannot create spot hosts,
+// so no host is ever reclaimed
+func (s *Stack) IsHostReclaimed(id string) (bool, error) {
+	if s == nil {
+		return false, scerr.InvalidInstanceError()
+	}
+	return false, nil
+}
+
 // StartHost starts the host identified by id
 func (s *Stack) StartHost(id string) error {
 	if s == nil {
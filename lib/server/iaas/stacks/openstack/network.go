@@ -40,6 +40,7 @@ import (
 	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/userdata"
 	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/cidr"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/retry"
 )
@@ -84,6 +85,19 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (newNet *resources.N
 	if err != nil {
 		return nil, scerr.Errorf(fmt.Sprintf("failed to create subnet '%s (%s)': %s", req.Name, req.CIDR, err.Error()), err)
 	}
+	// ... and chooses an IPv6 prefix for dual-stack network if none is requested
+	ipv6CIDR := req.IPv6CIDR
+	if req.IPVersion == ipversion.DualStack {
+		if ipv6CIDR == "" {
+			ula, err := cidr.RandomULA()
+			if err != nil {
+				return nil, scerr.Wrap(err, "failed to choose an IPv6 prefix")
+			}
+			ipv6CIDR = ula.String()
+		} else if _, _, err = net.ParseCIDR(ipv6CIDR); err != nil {
+			return nil, scerr.Errorf(fmt.Sprintf("failed to create subnet '%s (%s)': %s", req.Name, ipv6CIDR, err.Error()), err)
+		}
+	}
 
 	// We specify a name and that it should forward packets
 	state := true
@@ -109,7 +123,11 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (newNet *resources.N
 		}
 	}()
 
-	subnet, err := s.createSubnet(req.Name, network.ID, req.CIDR, req.IPVersion, req.DNSServers)
+	subnetVersion := req.IPVersion
+	if subnetVersion == ipversion.DualStack {
+		subnetVersion = ipversion.IPv4
+	}
+	subnet, err := s.createSubnet(req.Name, network.ID, req.CIDR, subnetVersion, req.DNSServers)
 	if err != nil {
 		return nil, scerr.Errorf(fmt.Sprintf("error creating network '%s': %s", req.Name, ProviderErrorToString(err)), err)
 	}
@@ -130,9 +148,58 @@ func (s *Stack) CreateNetwork(req resources.NetworkRequest) (newNet *resources.N
 	newNet.Name = network.Name
 	newNet.CIDR = subnet.Mask
 	newNet.IPVersion = subnet.IPVersion
+	if subnet.IPVersion == ipversion.IPv6 {
+		newNet.IPv6CIDR = subnet.Mask
+	}
+
+	if req.IPVersion == ipversion.DualStack {
+		// IPv6 subnet is created without DNS servers, IPv4 ones cannot be used there
+		subnet6, err := s.createSubnet(req.Name+"-v6", network.ID, ipv6CIDR, ipversion.IPv6, nil)
+		if err != nil {
+			return nil, scerr.Errorf(fmt.Sprintf("error creating network '%s': %s", req.Name, ProviderErrorToString(err)), err)
+		}
+		newNet.IPv6CIDR = subnet6.Mask
+		newNet.IPVersion = ipversion.DualStack
+	}
 	return newNet, nil
 }
 
+// fillNetworkFromSubnets sets the CIDRs and the IP version of the network from its subnets; a network has either
+// one subnet or, when dual-stack, one IPv4 and one IPv6 subnets
+func fillNetworkFromSubnets(network *resources.Network, sns []Subnet) error {
+	var v4, v6 *Subnet
+	for i := range sns {
+		switch sns[i].IPVersion {
+		case ipversion.IPv4:
+			if v4 != nil {
+				return scerr.Errorf("bad configuration, a network cannot have several IPv4 subnets", nil)
+			}
+			v4 = &sns[i]
+		case ipversion.IPv6:
+			if v6 != nil {
+				return scerr.Errorf("bad configuration, a network cannot have several IPv6 subnets", nil)
+			}
+			v6 = &sns[i]
+		}
+	}
+	switch {
+	case v4 != nil && v6 != nil:
+		network.CIDR = v4.Mask
+		network.IPv6CIDR = v6.Mask
+		network.IPVersion = ipversion.DualStack
+	case v4 != nil:
+		network.CIDR = v4.Mask
+		network.IPVersion = ipversion.IPv4
+	case v6 != nil:
+		network.CIDR = v6.Mask
+		network.IPv6CIDR = v6.Mask
+		network.IPVersion = ipversion.IPv6
+	default:
+		return scerr.Errorf("bad configuration, a network should have at least one subnet", nil)
+	}
+	return nil
+}
+
 // GetNetworkByName ...
 func (s *Stack) GetNetworkByName(name string) (*resources.Network, error) {
 	if s == nil {
@@ -188,16 +255,13 @@ func (s *Stack) GetNetwork(id string) (*resources.Network, error) {
 		if err != nil {
 			return nil, scerr.Wrap(err, fmt.Sprintf("error getting network: %s", ProviderErrorToString(err)))
 		}
-		if len(sns) != 1 {
-			return nil, scerr.Errorf(fmt.Sprintf("bad configuration, each network should have exactly one subnet"), nil)
-		}
-		sn := sns[0]
-
 		newNet := resources.NewNetwork()
 		newNet.ID = network.ID
 		newNet.Name = network.Name
-		newNet.CIDR = sn.Mask
-		newNet.IPVersion = sn.IPVersion
+		err = fillNetworkFromSubnets(newNet, sns)
+		if err != nil {
+			return nil, err
+		}
 		// net.GatewayID = network.GatewayId
 		return newNet, nil
 	}
@@ -231,19 +295,16 @@ func (s *Stack) ListNetworks() ([]*resources.Network, error) {
 				if err != nil {
 					return false, scerr.Errorf(fmt.Sprintf("error getting network: %s", ProviderErrorToString(err)), err)
 				}
-				if len(sns) != 1 {
-					continue
-				}
 				if n.ID == s.ProviderNetworkID {
 					continue
 				}
-				sn := sns[0]
 
 				newNet := resources.NewNetwork()
 				newNet.ID = n.ID
 				newNet.Name = n.Name
-				newNet.CIDR = sn.Mask
-				newNet.IPVersion = sn.IPVersion
+				if fillNetworkFromSubnets(newNet, sns) != nil {
+					continue
+				}
 				// GatewayID: gwID,
 				netList = append(netList, newNet)
 			}
@@ -463,6 +524,14 @@ func (s *Stack) createSubnet(name string, networkID string, cidr string, ipVersi
 		opts.DNSNameservers = dnsServers
 	}
 
+	// IPv6 addresses are given by DHCPv6; router advertisements are sent by the router if any, by the gateway otherwise
+	if ipVersion == ipversion.IPv6 {
+		opts.IPv6AddressMode = "dhcpv6-stateful"
+		if s.cfgOpts.UseLayer3Networking {
+			opts.IPv6RAMode = "dhcpv6-stateful"
+		}
+	}
+
 	if !s.cfgOpts.UseLayer3Networking {
 		noGateway := ""
 		opts.GatewayIP = &noGateway
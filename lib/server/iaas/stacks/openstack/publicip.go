@@ -0,0 +1,153 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openstack
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/floatingips"
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// AllocatePublicIP reserves a floating IP in the floating IP pool of the tenant
+// Floating IPs have no name in OpenStack, the name is only kept in SafeScale metadata
+func (s *Stack) AllocatePublicIP(name string) (*resources.PublicIP, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	if !s.cfgOpts.UseFloatingIP {
+		return nil, scerr.NotAvailableError("floating IPs are not used on this tenant")
+	}
+
+	fip, err := floatingips.Create(s.ComputeClient, floatingips.CreateOpts{
+		Pool: s.authOpts.FloatingIPPool,
+	}).Extract()
+	if err != nil {
+		return nil, scerr.Wrap(err, fmt.Sprintf("failed to allocate floating IP: %s", ProviderErrorToString(err)))
+	}
+	return &resources.PublicIP{
+		ID:      fip.ID,
+		Name:    name,
+		Address: fip.IP,
+		HostID:  fip.InstanceID,
+	}, nil
+}
+
+// ListPublicIPs lists the floating IPs of the tenant
+func (s *Stack) ListPublicIPs() ([]*resources.PublicIP, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	defer concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	var list []*resources.PublicIP
+	err := floatingips.List(s.ComputeClient).EachPage(func(page pagination.Page) (bool, error) {
+		fips, err := floatingips.ExtractFloatingIPs(page)
+		if err != nil {
+			return false, err
+		}
+		for _, fip := range fips {
+			list = append(list, &resources.PublicIP{
+				ID:      fip.ID,
+				Address: fip.IP,
+				HostID:  fip.InstanceID,
+			})
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, scerr.Wrap(err, fmt.Sprintf("failed to list floating IPs: %s", ProviderErrorToString(err)))
+	}
+	return list, nil
+}
+
+// AttachPublicIP associates the floating IP to the host identified by hostID
+func (s *Stack) AttachPublicIP(ip *resources.PublicIP, hostID string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s, %s)", ip.Address, hostID), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	err := floatingips.AssociateInstance(s.ComputeClient, hostID, floatingips.AssociateOpts{
+		FloatingIP: ip.Address,
+	}).ExtractErr()
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("failed to associate floating IP '%s' to host '%s': %s", ip.Address, hostID, ProviderErrorToString(err)))
+	}
+	ip.HostID = hostID
+	return nil
+}
+
+// DetachPublicIP disassociates the floating IP from its host
+func (s *Stack) DetachPublicIP(ip *resources.PublicIP) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s)", ip.Address), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	if ip.HostID == "" {
+		return nil
+	}
+	err := floatingips.DisassociateInstance(s.ComputeClient, ip.HostID, floatingips.DisassociateOpts{
+		FloatingIP: ip.Address,
+	}).ExtractErr()
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("failed to disassociate floating IP '%s' from host '%s': %s", ip.Address, ip.HostID, ProviderErrorToString(err)))
+	}
+	ip.HostID = ""
+	return nil
+}
+
+// ReleasePublicIP deletes the floating IP
+func (s *Stack) ReleasePublicIP(ip *resources.PublicIP) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+
+	defer concurrency.NewTracer(nil, fmt.Sprintf("(%s)", ip.Address), true).WithStopwatch().GoingIn().OnExitTrace()()
+
+	err := floatingips.Delete(s.ComputeClient, ip.ID).ExtractErr()
+	if err != nil {
+		return scerr.Wrap(err, fmt.Sprintf("failed to delete floating IP '%s': %s", ip.Address, ProviderErrorToString(err)))
+	}
+	return nil
+}
@@ -107,9 +107,12 @@ func (s *Stack) CreateVolume(request resources.VolumeRequest) (volume *resources
 		return nil, resources.ResourceDuplicateError("volume", request.Name)
 	}
 
-	az, err := s.SelectedAvailabilityZone()
-	if err != nil {
-		return nil, resources.ResourceDuplicateError("volume", request.Name)
+	az := request.AvailabilityZone
+	if az == "" {
+		az, err = s.SelectedAvailabilityZone()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	var v resources.Volume
@@ -1266,6 +1266,40 @@ func (s *Stack) RebootHost(id string) error {
 	return err
 }
 
+// GetHostConsoleOutput returns the console output of the host identified by id
+func (s *Stack) GetHostConsoleOutput(id string) (string, error) {
+	if s == nil {
+		return "", scerr.InvalidInstanceError()
+	}
+	if id == "" {
+		return "", scerr.InvalidParameterError("id", "must not be empty")
+	}
+	readConsoleOutputRequest := osc.ReadConsoleOutputRequest{
+		VmId: id,
+	}
+	res, _, err := s.client.VmApi.ReadConsoleOutput(s.auth, &osc.ReadConsoleOutputOpts{
+		ReadConsoleOutputRequest: optional.NewInterface(readConsoleOutputRequest),
+	})
+	if err != nil {
+		return "", err
+	}
+	// Console output is base64 encoded
+	content, err := base64.StdEncoding.DecodeString(res.ConsoleOutput)
+	if err != nil {
+		return "", scerr.Errorf(fmt.Sprintf("failed to decode console output of host '%s'", id), err)
+	}
+	return string(content), nil
+}
+
+// IsHostReclaimed tells if the spot host identified by id has been reclaimed; Outscale cannot create spot hosts,
+// so no host is ever reclaimed
+func (s *Stack) IsHostReclaimed(id string) (bool, error) {
+	if s == nil {
+		return false, scerr.InvalidInstanceError()
+	}
+	return false, nil
+}
+
 func (s *Stack) perfFromFreq(freq float32) int {
 	var perfList sort.IntSlice
 	for k := range s.CPUPerformanceMap {
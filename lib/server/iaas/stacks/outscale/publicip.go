@@ -0,0 +1,177 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package outscale
+
+import (
+	"fmt"
+
+	"github.com/antihax/optional"
+	"github.com/outscale-dev/osc-sdk-go/osc"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// AllocatePublicIP allocates a public IP and tags it with name
+func (s *Stack) AllocatePublicIP(name string) (*resources.PublicIP, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	res, _, err := s.client.PublicIpApi.CreatePublicIp(s.auth, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = s.setResourceTags(res.PublicIp.PublicIpId, map[string]string{
+		"name": name,
+	})
+	if err != nil {
+		derr := s.deletePublicIP(res.PublicIp.PublicIpId)
+		if derr != nil {
+			err = scerr.AddConsequence(err, derr)
+		}
+		return nil, err
+	}
+	return &resources.PublicIP{
+		ID:      res.PublicIp.PublicIpId,
+		Name:    name,
+		Address: res.PublicIp.PublicIp,
+	}, nil
+}
+
+// ListPublicIPs lists the public IPs of the account
+func (s *Stack) ListPublicIPs() ([]*resources.PublicIP, error) {
+	if s == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	res, _, err := s.client.PublicIpApi.ReadPublicIps(s.auth, nil)
+	if err != nil {
+		return nil, err
+	}
+	var list []*resources.PublicIP
+	for _, ip := range res.PublicIps {
+		list = append(list, toPublicIP(ip))
+	}
+	return list, nil
+}
+
+// AttachPublicIP links the public IP to the vm identified by hostID
+// A public IP already linked to another vm is relinked to hostID
+func (s *Stack) AttachPublicIP(ip *resources.PublicIP, hostID string) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+	if hostID == "" {
+		return scerr.InvalidParameterError("hostID", "cannot be empty string")
+	}
+
+	linkPublicIpRequest := osc.LinkPublicIpRequest{
+		AllowRelink: true,
+		PublicIpId:  ip.ID,
+		VmId:        hostID,
+	}
+	_, _, err := s.client.PublicIpApi.LinkPublicIp(s.auth, &osc.LinkPublicIpOpts{
+		LinkPublicIpRequest: optional.NewInterface(linkPublicIpRequest),
+	})
+	if err != nil {
+		return err
+	}
+	ip.HostID = hostID
+	return nil
+}
+
+// DetachPublicIP unlinks the public IP from its vm
+func (s *Stack) DetachPublicIP(ip *resources.PublicIP) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+
+	oscIP, err := s.getPublicIP(ip.ID)
+	if err != nil {
+		return err
+	}
+	if oscIP.LinkPublicIpId != "" {
+		unlinkPublicIpRequest := osc.UnlinkPublicIpRequest{
+			LinkPublicIpId: oscIP.LinkPublicIpId,
+		}
+		_, _, err = s.client.PublicIpApi.UnlinkPublicIp(s.auth, &osc.UnlinkPublicIpOpts{
+			UnlinkPublicIpRequest: optional.NewInterface(unlinkPublicIpRequest),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	ip.HostID = ""
+	return nil
+}
+
+// ReleasePublicIP deletes the public IP
+func (s *Stack) ReleasePublicIP(ip *resources.PublicIP) error {
+	if s == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if ip == nil {
+		return scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+
+	return s.deletePublicIP(ip.ID)
+}
+
+func (s *Stack) getPublicIP(id string) (*osc.PublicIp, error) {
+	readPublicIpsRequest := osc.ReadPublicIpsRequest{
+		Filters: osc.FiltersPublicIp{PublicIpIds: []string{id}},
+	}
+	res, _, err := s.client.PublicIpApi.ReadPublicIps(s.auth, &osc.ReadPublicIpsOpts{
+		ReadPublicIpsRequest: optional.NewInterface(readPublicIpsRequest),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.PublicIps) == 0 {
+		return nil, scerr.NotFoundError(fmt.Sprintf("failed to find public IP '%s'", id))
+	}
+	return &res.PublicIps[0], nil
+}
+
+func (s *Stack) deletePublicIP(id string) error {
+	deletePublicIpRequest := osc.DeletePublicIpRequest{
+		PublicIpId: id,
+	}
+	_, _, err := s.client.PublicIpApi.DeletePublicIp(s.auth, &osc.DeletePublicIpOpts{
+		DeletePublicIpRequest: optional.NewInterface(deletePublicIpRequest),
+	})
+	return err
+}
+
+func toPublicIP(ip osc.PublicIp) *resources.PublicIP {
+	return &resources.PublicIP{
+		ID:      ip.PublicIpId,
+		Name:    unwrapTags(ip.Tags)["name"],
+		Address: ip.PublicIp,
+		HostID:  ip.VmId,
+	}
+}
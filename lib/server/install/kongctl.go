@@ -163,7 +163,7 @@ func (k *KongController) Apply(rule map[interface{}]interface{}, values *Variabl
 	if err != nil {
 		return rule["name"].(string), err
 	}
-	content, err := k.realizeRuleData(strings.Trim(rule["content"].(string), "\n"), *values)
+	content, err := k.realizeRuleData(strings.Trim(rule["content"].(string), "\n"), bracketIPv6Values(*values))
 	if err != nil {
 		return ruleName, err
 	}
@@ -194,6 +194,7 @@ func (k *KongController) Apply(rule map[interface{}]interface{}, values *Variabl
 		}
 		if _, ok := unjsoned["source-control"]; ok {
 			sourceControl = unjsoned["source-control"].(map[string]interface{})
+			unbracketSourceControl(sourceControl)
 			delete(unjsoned, "source-control")
 		}
 		if _, ok := unjsoned["name"]; !ok {
@@ -218,6 +219,7 @@ func (k *KongController) Apply(rule map[interface{}]interface{}, values *Variabl
 		}
 		if _, ok := unjsoned["source-control"]; ok {
 			sourceControl = unjsoned["source-control"].(map[string]interface{})
+			unbracketSourceControl(sourceControl)
 			delete(unjsoned, "source-control")
 		}
 		if _, ok := unjsoned["name"]; !ok {
@@ -277,6 +279,36 @@ func (k *KongController) Apply(rule map[interface{}]interface{}, values *Variabl
 	}
 }
 
+// bracketIPv6Values returns a copy of values where IPv6 addresses are enclosed in brackets, as Kong expects them in
+// targets and urls
+func bracketIPv6Values(values Variables) Variables {
+	bracketed := Variables{}
+	for k, v := range values {
+		if s, ok := v.(string); ok {
+			bracketed[k] = utils.BracketIPv6(s)
+		} else {
+			bracketed[k] = v
+		}
+	}
+	return bracketed
+}
+
+// unbracketSourceControl removes the brackets of the addresses of the source-control lists, where Kong expects bare
+// addresses
+func unbracketSourceControl(sourceControl map[string]interface{}) {
+	for _, list := range []string{"whitelist", "blacklist"} {
+		entries, ok := sourceControl[list].([]interface{})
+		if !ok {
+			continue
+		}
+		for i, e := range entries {
+			if s, ok := e.(string); ok {
+				entries[i] = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+			}
+		}
+	}
+}
+
 func (k *KongController) realizeRuleData(content string, v Variables) (string, error) {
 	contentTmpl, err := template.New("proxy_content").Parse(content)
 	if err != nil {
@@ -0,0 +1,290 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package listeners
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	srvmetadata "github.com/CS-SI/SafeScale/lib/server/metadata"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// AuditHandler ...
+var AuditHandler = handlers.NewAuditHandler
+
+// AuditListener audit service server grpc
+type AuditListener struct{}
+
+// readOnlyMethods contains the names of the RPCs that do not modify anything, and are not audited
+var readOnlyMethods = map[string]bool{
+	"List":    true,
+	"Inspect": true,
+	"Status":  true,
+	"Get":     true,
+	"History": true,
+	"SSH":     true,
+	"Check":   true,
+	"Cost":    true,
+	// HostService
+	"ConsoleLog": true,
+	// TemplateService
+	"Presets": true,
+	// NetworkService
+	"InspectVPN": true,
+	"InspectDNS": true,
+}
+
+// secretParameters contains the parts of parameter names whose values must not appear in the audit trail
+var secretParameters = []string{"password", "private_key", "privatekey", "secret", "token", "passphrase", "credential"}
+
+var (
+	auditFileMutex sync.Mutex
+	auditFilePath  = utils.AbsPathify("$HOME/.safescale/audit/audit.log")
+)
+
+// AuditUnaryServerInterceptor records in the audit trail every mutating RPC
+func AuditUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	service, method := splitFullMethod(info.FullMethod)
+	if readOnlyMethods[method] || service == "AuditService" {
+		return handler(ctx, req)
+	}
+
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	event := &srvmetadata.AuditEvent{
+		Time:         start,
+		Principal:    getCaller(ctx),
+		Method:       info.FullMethod,
+		ResourceKind: strings.ToLower(strings.TrimSuffix(service, "Service")),
+		ResourceRef:  getResourceReference(req),
+		Parameters:   redactParameters(req),
+		Outcome:      "success",
+		Duration:     time.Since(start).Seconds(),
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("uuid")) > 0 {
+		event.JobUUID = md.Get("uuid")[0]
+	}
+	if err != nil {
+		event.Outcome = "failure"
+		event.Error = err.Error()
+	}
+	if event.ResourceRef == "" {
+		event.ResourceRef = getResultReference(resp)
+	}
+
+	// The current tenant may have changed during the RPC (TenantService/Set), so it is read afterwards
+	recordAuditEvent(ctx, event)
+
+	return resp, err
+}
+
+// recordBackgroundAuditEvent records in the audit trail an action done by a job of safescaled itself
+func recordBackgroundAuditEvent(ctx context.Context, event *srvmetadata.AuditEvent) {
+	event.Principal = getCaller(ctx)
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("uuid")) > 0 {
+		event.JobUUID = md.Get("uuid")[0]
+	}
+	recordAuditEvent(ctx, event)
+}
+
+// recordAuditEvent stores the audit event in the metadata of the current tenant and in the local audit file
+func recordAuditEvent(ctx context.Context, event *srvmetadata.AuditEvent) {
+	if tenant := GetCurrentTenant(); tenant != nil {
+		event.Tenant = tenant.name
+		if nerr := AuditHandler(tenant.ServiceWithContext(ctx)).Record(ctx, event); nerr != nil {
+			log.Warnf("failed to record audit event in metadata of tenant '%s': %v", tenant.name, nerr)
+		}
+	}
+	if nerr := appendAuditFile(event); nerr != nil {
+		log.Warnf("failed to record audit event in '%s': %v", auditFilePath, nerr)
+	}
+}
+
+// splitFullMethod returns the service name and the method name of a grpc full method name ("/<service>/<method>")
+func splitFullMethod(fullMethod string) (string, string) {
+	parts := strings.Split(strings.Trim(fullMethod, "/"), "/")
+	if len(parts) != 2 {
+		return "", fullMethod
+	}
+	return parts[0], parts[1]
+}
+
+// getResourceReference returns the name or ID of the resource targeted by a request
+func getResourceReference(req interface{}) string {
+	ref := func(r *pb.Reference) string {
+		if r == nil {
+			return ""
+		}
+		if r.GetName() != "" {
+			return r.GetName()
+		}
+		return r.GetId()
+	}
+	switch in := req.(type) {
+	case *pb.VolumeAttachment:
+		return ref(in.GetVolume()) + "@" + ref(in.GetHost())
+	case *pb.VolumeDetachment:
+		return ref(in.GetVolume()) + "@" + ref(in.GetHost())
+	case *pb.VolumeSizeChange:
+		return ref(in.GetVolumeName())
+	case *pb.ShareMountDefinition:
+		return ref(in.GetShare()) + "@" + ref(in.GetHost())
+	case *pb.BucketMountingPoint:
+		return in.GetBucket() + "@" + ref(in.GetHost())
+	case *pb.SshCommand:
+		return ref(in.GetHost())
+	case *pb.JobDefinition:
+		return in.GetUuid()
+	case interface{ GetName() string }:
+		if in.GetName() != "" {
+			return in.GetName()
+		}
+	}
+	if in, ok := req.(interface{ GetId() string }); ok {
+		return in.GetId()
+	}
+	return ""
+}
+
+// redactParameters converts the request to a map, hiding the values of the secret parameters
+func redactParameters(req interface{}) map[string]interface{} {
+	jsoned, err := json.Marshal(req)
+	if err != nil {
+		return nil
+	}
+	params := map[string]interface{}{}
+	if err = json.Unmarshal(jsoned, &params); err != nil {
+		return nil
+	}
+	redact(params)
+	return params
+}
+
+// redact replaces recursively the values of the secret parameters by a placeholder
+func redact(params map[string]interface{}) {
+	for k, v := range params {
+		lowered := strings.ToLower(k)
+		secret := false
+		for _, s := range secretParameters {
+			if strings.Contains(lowered, s) {
+				secret = true
+				break
+			}
+		}
+		if secret {
+			params[k] = "********"
+			continue
+		}
+		switch value := v.(type) {
+		case map[string]interface{}:
+			redact(value)
+		case []interface{}:
+			for _, item := range value {
+				if m, ok := item.(map[string]interface{}); ok {
+					redact(m)
+				}
+			}
+		}
+	}
+}
+
+// appendAuditFile writes the audit event as a JSON line in the local audit file
+func appendAuditFile(event *srvmetadata.AuditEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	auditFileMutex.Lock()
+	defer auditFileMutex.Unlock()
+
+	err = os.MkdirAll(filepath.Dir(auditFilePath), 0700)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(auditFilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// List returns the audit events of the current tenant
+func (s *AuditListener) List(ctx context.Context, in *pb.AuditListRequest) (al *pb.AuditEventList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", in.GetSince(), in.GetResource()), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	var since time.Time
+	if in.GetSince() != "" {
+		since, err = time.Parse(time.RFC3339, in.GetSince())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid value '%s' for since: %s", in.GetSince(), err.Error())
+		}
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't list audit events: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list audit events: no tenant set")
+	}
+
+	handler := AuditHandler(tenant.ServiceWithContext(ctx))
+	events, err := handler.List(ctx, since, in.GetResource())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	var pbEvents []*pb.AuditEvent
+	for _, e := range events {
+		pbEvents = append(pbEvents, srvutils.ToPBAuditEvent(e))
+	}
+	return &pb.AuditEventList{Events: pbEvents}, nil
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package listeners
+
+import (
+	"context"
+
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
+
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// backgroundCaller is the caller of the jobs started by safescaled itself, in the job store and the audit trail
+const backgroundCaller = "safescaled"
+
+// RunBackgroundJob runs 'job' as a job of safescaled itself: it is recorded in the job store, and registered to be
+// waited for, then cancelled, when safescaled is draining
+// Nothing is run once safescaled is draining
+func RunBackgroundJob(command string, job func(ctx context.Context) error) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	jobUUID := id.String()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("uuid", jobUUID, "caller", backgroundCaller))
+	ctx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
+
+	err = srvutils.JobRegister(ctx, cancelFunc, command)
+	if err != nil {
+		return err
+	}
+	defer srvutils.JobDeregister(ctx)
+	// Checked once registered, for the draining to either wait for the job or see it give up
+	if IsDraining() {
+		return nil
+	}
+
+	record := &srvutils.JobRecord{
+		UUID:    jobUUID,
+		Command: command,
+		Caller:  backgroundCaller,
+	}
+	if tenant := GetCurrentTenant(); tenant != nil {
+		record.Tenant = tenant.name
+	}
+	if nerr := srvutils.JobStart(record); nerr != nil {
+		log.Warnf("failed to record job '%s' in job store: %v", jobUUID, nerr)
+	}
+	err = job(ctx)
+	if nerr := srvutils.JobEnd(jobUUID, "", err); nerr != nil {
+		log.Warnf("failed to record end of job '%s' in job store: %v", jobUUID, nerr)
+	}
+	return err
+}
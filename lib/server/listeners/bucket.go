@@ -36,7 +36,7 @@ import (
 var BucketHandler = handlers.NewBucketHandler
 
 // safescale bucket create c1
-// safescale bucket mount c1 host1 --path="/shared/data" (utilisation de s3ql, par default /buckets/c1)
+// safescale bucket mount c1 host1 --path="/shared/data" (rclone, s3fs or gcsfuse depending on Object Storage type, by default /buckets/c1)
 // safescale bucket umount c1 host1
 // safescale bucket delete c1
 // safescale bucket list
@@ -63,7 +63,7 @@ func (s *BucketListener) List(ctx context.Context, in *googleprotobuf.Empty) (bl
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list buckets: no tenant set")
 	}
 
-	handler := BucketHandler(tenant.Service)
+	handler := BucketHandler(tenant.ServiceWithContext(ctx))
 	buckets, err := handler.List(ctx)
 	if err != nil {
 		tbr := scerr.Wrap(err, "Can't list buckets"+adaptedUserMessage(err))
@@ -91,7 +91,7 @@ func (s *BucketListener) Create(ctx context.Context, in *pb.Bucket) (empty *goog
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot create bucket: no tenant set")
 	}
 
-	handler := BucketHandler(tenant.Service)
+	handler := BucketHandler(tenant.ServiceWithContext(ctx))
 	err = handler.Create(ctx, bucketName)
 	if err != nil {
 		tbr := scerr.Wrap(err, "cannot create bucket"+adaptedUserMessage(err))
@@ -120,7 +120,7 @@ func (s *BucketListener) Destroy(ctx context.Context, in *pb.Bucket) (empty *goo
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot delete bucket: no tenant set")
 	}
 
-	handler := BucketHandler(tenant.Service)
+	handler := BucketHandler(tenant.ServiceWithContext(ctx))
 	err = handler.Destroy(ctx, bucketName)
 	if err != nil {
 		tbr := scerr.Wrap(err, "cannot destroy bucket"+adaptedUserMessage(err))
@@ -149,7 +149,7 @@ func (s *BucketListener) Delete(ctx context.Context, in *pb.Bucket) (empty *goog
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot delete bucket: no tenant set")
 	}
 
-	handler := BucketHandler(tenant.Service)
+	handler := BucketHandler(tenant.ServiceWithContext(ctx))
 	err = handler.Delete(ctx, bucketName)
 	if err != nil {
 		tbr := scerr.Wrap(err, "cannot delete bucket"+adaptedUserMessage(err))
@@ -177,7 +177,7 @@ func (s *BucketListener) Inspect(ctx context.Context, in *pb.Bucket) (bmp *pb.Bu
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect bucket: no tenant set")
 	}
 
-	handler := BucketHandler(tenant.Service)
+	handler := BucketHandler(tenant.ServiceWithContext(ctx))
 	resp, err := handler.Inspect(ctx, bucketName)
 	if err != nil {
 		tbr := scerr.Wrap(err, "cannot inspect bucket"+adaptedUserMessage(err))
@@ -208,7 +208,7 @@ func (s *BucketListener) Mount(ctx context.Context, in *pb.BucketMountingPoint)
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot mount bucket: no tenant set")
 	}
 
-	handler := BucketHandler(tenant.Service)
+	handler := BucketHandler(tenant.ServiceWithContext(ctx))
 	err = handler.Mount(ctx, bucketName, hostName, in.GetPath())
 	if err != nil {
 		return &googleprotobuf.Empty{}, status.Errorf(codes.Internal, getUserMessage(err))
@@ -236,7 +236,7 @@ func (s *BucketListener) Unmount(ctx context.Context, in *pb.BucketMountingPoint
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot unmount bucket: no tenant set")
 	}
 
-	handler := BucketHandler(tenant.Service)
+	handler := BucketHandler(tenant.ServiceWithContext(ctx))
 	err = handler.Unmount(ctx, bucketName, hostName)
 	if err != nil {
 		return &googleprotobuf.Empty{}, status.Errorf(codes.Internal, getUserMessage(err))
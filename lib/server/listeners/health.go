@@ -0,0 +1,102 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package listeners
+
+import (
+	"context"
+	"sync"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// HealthListener health service server grpc
+type HealthListener struct{}
+
+// StandardHealthServer implements the standard gRPC health checking protocol (usable by probes like grpc_health_probe)
+var StandardHealthServer = health.NewServer()
+
+// drainingServices contains the services still answering while safescaled is draining
+var drainingServices = map[string]bool{
+	"HealthService":         true,
+	"JobService":            true,
+	"grpc.health.v1.Health": true,
+}
+
+var (
+	draining       bool
+	drainingSignal = make(chan struct{})
+	mutexDraining  sync.RWMutex
+)
+
+// StartDraining marks safescaled as not ready: new requests are refused, except the ones about health and jobs
+func StartDraining() {
+	mutexDraining.Lock()
+	defer mutexDraining.Unlock()
+
+	if !draining {
+		close(drainingSignal)
+	}
+	draining = true
+	StandardHealthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// DrainingStarted returns a channel closed when safescaled starts draining
+func DrainingStarted() <-chan struct{} {
+	return drainingSignal
+}
+
+// IsDraining tells if safescaled is draining its jobs before stopping
+func IsDraining() bool {
+	mutexDraining.RLock()
+	defer mutexDraining.RUnlock()
+
+	return draining
+}
+
+// DrainingUnaryServerInterceptor refuses new requests when safescaled is draining
+func DrainingUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if IsDraining() {
+		service, _ := splitFullMethod(info.FullMethod)
+		if !drainingServices[service] {
+			return nil, status.Errorf(codes.Unavailable, "safescaled is shutting down, no new request accepted")
+		}
+	}
+	return handler(ctx, req)
+}
+
+// Check returns the liveness and readiness of safescaled
+func (s *HealthListener) Check(ctx context.Context, in *googleprotobuf.Empty) (*pb.HealthStatus, error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+
+	isDraining := IsDraining()
+	return &pb.HealthStatus{
+		Live:        true,
+		Ready:       !isDraining,
+		Draining:    isDraining,
+		RunningJobs: int32(srvutils.JobCount()),
+	}, nil
+}
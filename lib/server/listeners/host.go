@@ -19,6 +19,7 @@ package listeners
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"google.golang.org/grpc/status"
 
@@ -38,6 +39,16 @@ import (
 // HostHandler ...
 var HostHandler = handlers.NewHostHandler
 
+// EnforceHostLifecycles applies the lifecycle policies of the hosts of the current tenant, in a job of safescaled
+// itself; the hosts it stops or deletes are recorded in the audit trail
+func EnforceHostLifecycles(ctx context.Context) error {
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil
+	}
+	return HostHandler(tenant.ServiceWithContext(ctx)).EnforceLifecycles(ctx, recordBackgroundAuditEvent)
+}
+
 // HostListener host service server grpc
 type HostListener struct{}
 
@@ -90,7 +101,7 @@ func (s *HostListener) Start(ctx context.Context, in *pb.Reference) (empty *goog
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot start host: no tenant set")
 	}
 
-	handler := HostHandler(tenant.Service)
+	handler := HostHandler(tenant.ServiceWithContext(ctx))
 	err = handler.Start(ctx, ref)
 	if err != nil {
 		return empty, status.Errorf(codes.Internal, getUserMessage(err))
@@ -126,7 +137,7 @@ func (s *HostListener) Stop(ctx context.Context, in *pb.Reference) (empty *googl
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot stop host: no tenant set")
 	}
 
-	handler := HostHandler(tenant.Service)
+	handler := HostHandler(tenant.ServiceWithContext(ctx))
 	err = handler.Stop(ctx, ref)
 	if err != nil {
 		return empty, status.Errorf(codes.Internal, getUserMessage(err))
@@ -163,7 +174,7 @@ func (s *HostListener) Reboot(ctx context.Context, in *pb.Reference) (empty *goo
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot reboot host: no tenant set")
 	}
 
-	handler := HostHandler(tenant.Service)
+	handler := HostHandler(tenant.ServiceWithContext(ctx))
 	err = handler.Reboot(ctx, ref)
 	if err != nil {
 		return empty, status.Errorf(codes.Internal, getUserMessage(err))
@@ -173,6 +184,40 @@ func (s *HostListener) Reboot(ctx context.Context, in *pb.Reference) (empty *goo
 	return empty, nil
 }
 
+// ConsoleLog returns the console output of a host
+func (s *HostListener) ConsoleLog(ctx context.Context, in *pb.Reference) (cl *pb.HostConsoleLog, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidParameterError("ref", "cannot be empty string").Message())
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Console log of Host "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't get console log of host: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot get console log of host: no tenant set")
+	}
+
+	handler := HostHandler(tenant.ServiceWithContext(ctx))
+	output, err := handler.ConsoleLog(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return &pb.HostConsoleLog{Name: ref, Output: output}, nil
+}
+
 // List lists hosts managed by SafeScale only, or all hosts.
 func (s *HostListener) List(ctx context.Context, in *pb.HostListRequest) (hl *pb.HostList, err error) {
 	if s == nil {
@@ -196,7 +241,7 @@ func (s *HostListener) List(ctx context.Context, in *pb.HostListRequest) (hl *pb
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list hosts: no tenant set")
 	}
 
-	handler := HostHandler(tenant.Service)
+	handler := HostHandler(tenant.ServiceWithContext(ctx))
 	hosts, err := handler.List(ctx, all)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
@@ -252,20 +297,51 @@ func (s *HostListener) Create(ctx context.Context, in *pb.HostDefinition) (h *pb
 		sizing = &s
 	}
 
-	handler := HostHandler(tenant.Service)
+	nets := in.GetNetworks()
+	if len(nets) == 0 && in.GetNetwork() != "" {
+		nets = []string{in.GetNetwork()}
+	}
+
+	lifecycle := in.GetLifecycle()
+	expiresIn := time.Duration(lifecycle.GetExpiresIn()) * time.Second
+	idleStop := time.Duration(lifecycle.GetIdleStop()) * time.Second
+	err = handlers.CheckLifecycle(expiresIn, lifecycle.GetOnExpiry(), idleStop)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, getUserMessage(err))
+	}
+
+	handler := HostHandler(tenant.ServiceWithContext(ctx))
 	host, err := handler.Create(ctx,
 		name,
-		in.GetNetwork(),
+		nets,
 		in.GetImageId(),
 		in.GetPublic(),
 		sizing,
 		in.Force,
 		in.Domain,
+		in.GetUserData(),
 		in.KeepOnFailure,
 	)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
 	}
+	if expiresIn > 0 || idleStop > 0 {
+		err = handler.SetLifecycle(ctx, host.ID, expiresIn, lifecycle.GetOnExpiry(), idleStop)
+		if err != nil {
+			// A host without its lifecycle policies would never expire nor be stopped when idle
+			if !in.KeepOnFailure {
+				derr := handler.Delete(context.Background(), host.ID)
+				if derr != nil {
+					err = scerr.AddConsequence(err, derr)
+				}
+			}
+			return nil, status.Errorf(codes.Internal, getUserMessage(err))
+		}
+		host, err = handler.Inspect(ctx, host.ID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, getUserMessage(err))
+		}
+	}
 	log.Infof("Host '%s' created", name)
 	return srvutils.ToPBHost(host), nil
 }
@@ -295,7 +371,7 @@ func (s *HostListener) Resize(ctx context.Context, in *pb.HostDefinition) (h *pb
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot resize host: no tenant set")
 	}
 
-	handler := HostHandler(tenant.Service)
+	handler := HostHandler(tenant.ServiceWithContext(ctx))
 	host, err := handler.Resize(ctx,
 		name,
 		int(in.GetCpuCount()),
@@ -339,7 +415,7 @@ func (s *HostListener) Status(ctx context.Context, in *pb.Reference) (ht *pb.Hos
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot get host status: no tenant set")
 	}
 
-	handler := HostHandler(tenant.Service)
+	handler := HostHandler(tenant.ServiceWithContext(ctx))
 	host, err := handler.ForceInspect(ctx, ref)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
@@ -375,7 +451,7 @@ func (s *HostListener) Inspect(ctx context.Context, in *pb.Reference) (h *pb.Hos
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect host: no tenant set")
 	}
 
-	handler := HostHandler(tenant.Service)
+	handler := HostHandler(tenant.ServiceWithContext(ctx))
 	host, err := handler.ForceInspect(ctx, ref)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, fmt.Sprintf("cannot inspect host: %s", getUserMessage(err)))
@@ -412,7 +488,7 @@ func (s *HostListener) Delete(ctx context.Context, in *pb.Reference) (empty *goo
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete host: no tenant set")
 	}
 
-	handler := HostHandler(tenant.Service)
+	handler := HostHandler(tenant.ServiceWithContext(ctx))
 	err = handler.Delete(ctx, ref)
 	if err != nil {
 		return empty, status.Errorf(codes.Internal, getUserMessage(err))
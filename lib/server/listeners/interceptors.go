@@ -0,0 +1,167 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
+)
+
+// jobServicePrefix is the prefix of the full method names of the JobService RPCs, which are not recorded as jobs
+const jobServicePrefix = "/JobService/"
+
+// JobUnaryServerInterceptor records each mutating RPC as a job in the job store and, when the client asked for it
+// with the grpc metadata "async", runs the RPC in background and returns immediately
+// Read-only RPCs are recorded only when run in background, for the client to be able to poll them
+func JobUnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if strings.HasPrefix(info.FullMethod, jobServicePrefix) {
+		return handler(ctx, req)
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get("uuid")) == 0 {
+		return handler(ctx, req)
+	}
+	if _, method := splitFullMethod(info.FullMethod); readOnlyMethods[method] && !isAsync(md) {
+		return handler(ctx, req)
+	}
+	uuid := md.Get("uuid")[0]
+
+	record := &srvutils.JobRecord{
+		UUID:   uuid,
+		Method: info.FullMethod,
+		Caller: getCaller(ctx),
+		Async:  isAsync(md),
+	}
+	if tenant := GetCurrentTenant(); tenant != nil {
+		record.Tenant = tenant.name
+	}
+	// Secrets (passwords, keys, ...) must not be persisted in the job store
+	if jsoned, err := json.Marshal(redactParameters(req)); err == nil {
+		record.Arguments = string(jsoned)
+	}
+	err := srvutils.JobStart(record)
+	if err != nil {
+		log.Warnf("failed to record job '%s' in job store: %v", uuid, err)
+	}
+
+	run := func(ctx context.Context) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if nerr := srvutils.JobEnd(uuid, getResultReference(resp), err); nerr != nil {
+			log.Warnf("failed to record end of job '%s' in job store: %v", uuid, nerr)
+		}
+		return resp, err
+	}
+
+	if !record.Async {
+		return run(ctx)
+	}
+
+	// Detaches the job from the connection of the client, which will poll the job store with the UUID of the job
+	go func() {
+		// Keeps tracing the job as child of the span of the request
+		jobCtx := tracing.WithSpanOf(metadata.NewIncomingContext(context.Background(), md), ctx)
+		if timeout := getTimeout(md); timeout > 0 {
+			var cancel context.CancelFunc
+			jobCtx, cancel = context.WithTimeout(jobCtx, timeout)
+			defer cancel()
+		}
+		_, _ = run(jobCtx)
+	}()
+	_ = grpc.SetHeader(ctx, metadata.Pairs("job-uuid", uuid))
+	return emptyResponse(info), nil
+}
+
+// isAsync tells if the client asked for an asynchronous execution of the RPC
+func isAsync(md metadata.MD) bool {
+	async := md.Get("async")
+	return len(async) > 0 && async[0] == "true"
+}
+
+// getTimeout returns the timeout of the job asked by the client, 0 if none
+func getTimeout(md metadata.MD) time.Duration {
+	values := md.Get("timeout")
+	if len(values) == 0 {
+		return 0
+	}
+	timeout, err := time.ParseDuration(values[0])
+	if err != nil {
+		log.Warnf("ignoring invalid job timeout '%s': %v", values[0], err)
+		return 0
+	}
+	return timeout
+}
+
+// getCaller returns an identification of the caller of the RPC
+// The identity comes from the grpc metadata "caller" sent by the client, which is not authenticated; only the
+// address of the peer can be trusted
+func getCaller(ctx context.Context) string {
+	var caller string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if callers := md.Get("caller"); len(callers) > 0 {
+			caller = callers[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if caller != "" {
+			caller += " "
+		}
+		caller += "(" + p.Addr.String() + ")"
+	}
+	return caller
+}
+
+// getResultReference returns a reference to the result of a RPC, usually the ID or the name of the resource
+func getResultReference(resp interface{}) string {
+	if resp == nil {
+		return ""
+	}
+	if v := reflect.ValueOf(resp); v.Kind() == reflect.Ptr && v.IsNil() {
+		return ""
+	}
+	if r, ok := resp.(interface{ GetId() string }); ok && r.GetId() != "" {
+		return r.GetId()
+	}
+	if r, ok := resp.(interface{ GetName() string }); ok {
+		return r.GetName()
+	}
+	return ""
+}
+
+// emptyResponse builds an empty instance of the response type of the RPC described by 'info'
+func emptyResponse(info *grpc.UnaryServerInfo) interface{} {
+	methodName := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+	method := reflect.ValueOf(info.Server).MethodByName(methodName)
+	if !method.IsValid() || method.Type().NumOut() == 0 {
+		return nil
+	}
+	out := method.Type().Out(0)
+	if out.Kind() != reflect.Ptr {
+		return reflect.Zero(out).Interface()
+	}
+	return reflect.New(out.Elem()).Interface()
+}
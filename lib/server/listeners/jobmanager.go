@@ -19,6 +19,7 @@ package listeners
 import (
 	"context"
 	"fmt"
+	"time"
 
 	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
 	log "github.com/sirupsen/logrus"
@@ -27,6 +28,7 @@ import (
 
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
@@ -69,7 +71,7 @@ func (s *JobManagerListener) Stop(ctx context.Context, in *pb.JobDefinition) (em
 		return empty, status.Errorf(codes.FailedPrecondition, "Can't stop process: no tenant set")
 	}
 
-	handler := JobManagerHandler(tenant.Service)
+	handler := JobManagerHandler(tenant.ServiceWithContext(ctx))
 	handler.Stop(ctx, in.Uuid)
 
 	return empty, nil
@@ -97,7 +99,7 @@ func (s *JobManagerListener) List(ctx context.Context, in *googleprotobuf.Empty)
 		return nil, status.Errorf(codes.FailedPrecondition, "Can't list process: no tenant set")
 	}
 
-	handler := JobManagerHandler(tenant.Service)
+	handler := JobManagerHandler(tenant.ServiceWithContext(ctx))
 	processMap, err := handler.List(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list Process %s", getUserMessage(err))
@@ -109,3 +111,75 @@ func (s *JobManagerListener) List(ctx context.Context, in *googleprotobuf.Empty)
 
 	return &pb.JobList{List: pbProcessList}, nil
 }
+
+// Inspect returns the information about a job, running or ended
+func (s *JobManagerListener) Inspect(ctx context.Context, in *pb.JobDefinition) (j *pb.Job, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	uuid := in.Uuid
+	if uuid == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect job: job id not set")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", uuid), true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	// Jobs are not bound to a tenant, so no tenant is required here
+	var svc iaas.Service
+	if tenant := GetCurrentTenant(); tenant != nil {
+		svc = tenant.Service
+	}
+
+	handler := JobManagerHandler(svc)
+	record, err := handler.Inspect(ctx, uuid)
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); ok {
+			return nil, status.Errorf(codes.NotFound, "cannot inspect job '%s': not found", uuid)
+		}
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return srvutils.ToPBJob(record), nil
+}
+
+// History returns the jobs recorded since a date
+func (s *JobManagerListener) History(ctx context.Context, in *pb.JobHistoryRequest) (jh *pb.JobHistory, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %d)", in.GetSince(), in.GetLimit()), true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	var since time.Time
+	if in.GetSince() != "" {
+		since, err = time.Parse(time.RFC3339, in.GetSince())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid value '%s' for since: %s", in.GetSince(), err.Error())
+		}
+	}
+
+	var svc iaas.Service
+	if tenant := GetCurrentTenant(); tenant != nil {
+		svc = tenant.Service
+	}
+
+	handler := JobManagerHandler(svc)
+	records, err := handler.History(ctx, since, int(in.GetLimit()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	var pbJobs []*pb.Job
+	for _, r := range records {
+		pbJobs = append(pbJobs, srvutils.ToPBJob(r))
+	}
+	return &pb.JobHistory{Jobs: pbJobs}, nil
+}
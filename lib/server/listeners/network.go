@@ -29,7 +29,7 @@ import (
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/server/handlers"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
-	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ipversion"
+	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
@@ -95,11 +95,13 @@ func (s *NetworkListener) Create(ctx context.Context, in *pb.NetworkDefinition)
 		gwName = in.GetGateway().GetName()
 	}
 
-	handler := NetworkHandler(tenant.Service)
+	handler := NetworkHandler(tenant.ServiceWithContext(ctx))
 	network, err := handler.Create(ctx,
 		networkName,
 		in.GetCidr(),
-		ipversion.IPv4,
+		int(in.GetSubnetSize()),
+		srvutils.FromPBIPVersion(in.GetIpVersion()),
+		in.GetIpv6Cidr(),
 		*sizing,
 		gwImageID,
 		gwName,
@@ -140,7 +142,7 @@ func (s *NetworkListener) List(ctx context.Context, in *pb.NetworkListRequest) (
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list networks: no tenant set")
 	}
 
-	handler := NetworkHandler(tenant.Service)
+	handler := NetworkHandler(tenant.ServiceWithContext(ctx))
 	networks, err := handler.List(ctx, in.GetAll())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
@@ -192,7 +194,15 @@ func (s *NetworkListener) Inspect(ctx context.Context, in *pb.Reference) (net *p
 		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("cannot inspect network '%s': not found", ref))
 	}
 
-	return srvutils.ToPBNetwork(network), nil
+	out := srvutils.ToPBNetwork(network)
+	health, err := handler.CheckGateways(ctx, network.ID, false)
+	if err != nil {
+		log.Warnf("failed to check gateways of network '%s': %v", network.Name, err)
+	}
+	for _, v := range health {
+		out.Gateways = append(out.Gateways, srvutils.ToPBGatewayHealth(v))
+	}
+	return out, nil
 }
 
 // Delete a network
@@ -270,3 +280,347 @@ func (s *NetworkListener) Destroy(ctx context.Context, in *pb.Reference) (buf *g
 	log.Infof("Network '%s' successfully deleted.", ref)
 	return &googleprotobuf.Empty{}, nil
 }
+
+// Peer connects two networks
+func (s *NetworkListener) Peer(ctx context.Context, in *pb.NetworkPeeringRequest) (buf *googleprotobuf.Empty, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetNetwork())
+	peerRef := srvutils.GetReference(in.GetPeer())
+	if ref == "" || peerRef == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot peer networks: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, peerRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Peer networks "+ref+" and "+peerRef); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot peer networks: no tenant set")
+	}
+
+	handler := NetworkHandler(tenant.ServiceWithContext(ctx))
+	err = handler.Peer(ctx, ref, peerRef)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Networks '%s' and '%s' successfully peered.", ref, peerRef)
+	return &googleprotobuf.Empty{}, nil
+}
+
+// Unpeer disconnects two peered networks
+func (s *NetworkListener) Unpeer(ctx context.Context, in *pb.NetworkPeeringRequest) (buf *googleprotobuf.Empty, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetNetwork())
+	peerRef := srvutils.GetReference(in.GetPeer())
+	if ref == "" || peerRef == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot unpeer networks: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, peerRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Unpeer networks "+ref+" and "+peerRef); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot unpeer networks: no tenant set")
+	}
+
+	handler := NetworkHandler(tenant.ServiceWithContext(ctx))
+	err = handler.Unpeer(ctx, ref, peerRef)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Networks '%s' and '%s' successfully unpeered.", ref, peerRef)
+	return &googleprotobuf.Empty{}, nil
+}
+
+// InspectVPN returns the site-to-site VPN configuration of a network
+func (s *NetworkListener) InspectVPN(ctx context.Context, in *pb.Reference) (vpn *pb.NetworkVPN, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect VPN: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect VPN: no tenant set")
+	}
+
+	handler := NetworkHandler(tenant.ServiceWithContext(ctx))
+	return toPBNetworkVPN(ctx, handler, ref, func() (*propsv1.NetworkVPN, error) {
+		return handler.InspectVPN(ctx, ref)
+	})
+}
+
+// AddVPNPeer connects a remote site to the VPN of a network
+func (s *NetworkListener) AddVPNPeer(ctx context.Context, in *pb.NetworkVPNPeerRequest) (vpn *pb.NetworkVPN, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil || in.GetPeer() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetNetwork())
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot add VPN peer: neither name nor id given as reference")
+	}
+	peer := in.GetPeer()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, peer.GetName()), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Add VPN peer "+peer.GetName()+" to network "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot add VPN peer: no tenant set")
+	}
+
+	handler := NetworkHandler(tenant.ServiceWithContext(ctx))
+	return toPBNetworkVPN(ctx, handler, ref, func() (*propsv1.NetworkVPN, error) {
+		return handler.AddVPNPeer(ctx, ref, propsv1.NetworkVPNPeer{
+			Name:       peer.GetName(),
+			PublicKey:  peer.GetPublicKey(),
+			Endpoint:   peer.GetEndpoint(),
+			AllowedIPs: peer.GetAllowedIps(),
+		})
+	})
+}
+
+// RemoveVPNPeer disconnects a remote site from the VPN of a network
+func (s *NetworkListener) RemoveVPNPeer(ctx context.Context, in *pb.NetworkVPNPeerRequest) (vpn *pb.NetworkVPN, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil || in.GetPeer() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetNetwork())
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot remove VPN peer: neither name nor id given as reference")
+	}
+	name := in.GetPeer().GetName()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Remove VPN peer "+name+" from network "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot remove VPN peer: no tenant set")
+	}
+
+	handler := NetworkHandler(tenant.ServiceWithContext(ctx))
+	return toPBNetworkVPN(ctx, handler, ref, func() (*propsv1.NetworkVPN, error) {
+		return handler.RemoveVPNPeer(ctx, ref, name)
+	})
+}
+
+// toPBNetworkVPN runs action, and converts the VPN configuration it returns to protocolbuffer format
+func toPBNetworkVPN(ctx context.Context, handler handlers.NetworkAPI, ref string, action func() (*propsv1.NetworkVPN, error)) (*pb.NetworkVPN, error) {
+	vpn, err := action()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	network, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return srvutils.ToPBNetworkVPN(network, vpn), nil
+}
+
+// InspectDNS returns the records served by the internal DNS of a network
+func (s *NetworkListener) InspectDNS(ctx context.Context, in *pb.Reference) (dns *pb.NetworkDNS, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect DNS: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect DNS: no tenant set")
+	}
+
+	handler := NetworkHandler(tenant.ServiceWithContext(ctx))
+	return toPBNetworkDNS(ctx, handler, ref, func() (*propsv1.NetworkDNS, error) {
+		return handler.InspectDNS(ctx, ref)
+	})
+}
+
+// AddDNSAlias adds a CNAME record to the internal DNS of a network
+func (s *NetworkListener) AddDNSAlias(ctx context.Context, in *pb.NetworkDNSAliasRequest) (dns *pb.NetworkDNS, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil || in.GetAlias() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetNetwork())
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot add DNS alias: neither name nor id given as reference")
+	}
+	alias, target := in.GetAlias().GetAlias(), in.GetAlias().GetTarget()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s', '%s')", ref, alias, target), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Add DNS alias "+alias+" to network "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot add DNS alias: no tenant set")
+	}
+
+	handler := NetworkHandler(tenant.ServiceWithContext(ctx))
+	return toPBNetworkDNS(ctx, handler, ref, func() (*propsv1.NetworkDNS, error) {
+		return handler.AddDNSAlias(ctx, ref, alias, target)
+	})
+}
+
+// RemoveDNSAlias removes a CNAME record from the internal DNS of a network
+func (s *NetworkListener) RemoveDNSAlias(ctx context.Context, in *pb.NetworkDNSAliasRequest) (dns *pb.NetworkDNS, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil || in.GetAlias() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetNetwork())
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot remove DNS alias: neither name nor id given as reference")
+	}
+	alias := in.GetAlias().GetAlias()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, alias), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Remove DNS alias "+alias+" from network "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot remove DNS alias: no tenant set")
+	}
+
+	handler := NetworkHandler(tenant.ServiceWithContext(ctx))
+	return toPBNetworkDNS(ctx, handler, ref, func() (*propsv1.NetworkDNS, error) {
+		return handler.RemoveDNSAlias(ctx, ref, alias)
+	})
+}
+
+// toPBNetworkDNS runs action, and converts the DNS configuration it returns to protocolbuffer format
+func toPBNetworkDNS(ctx context.Context, handler handlers.NetworkAPI, ref string, action func() (*propsv1.NetworkDNS, error)) (*pb.NetworkDNS, error) {
+	dns, err := action()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	network, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return srvutils.ToPBNetworkDNS(network, dns), nil
+}
+
+// Repair recreates the dead gateway(s) of a network created with failover
+func (s *NetworkListener) Repair(ctx context.Context, in *pb.NetworkRepairRequest) (net *pb.Network, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in.GetNetwork())
+	if ref == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot repair network: neither name nor id given as reference")
+	}
+	image := in.GetImageId()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ref, image), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Repair network "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot repair network: no tenant set")
+	}
+
+	handler := NetworkHandler(tenant.ServiceWithContext(ctx))
+	network, err := handler.Repair(ctx, ref, image)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	out := srvutils.ToPBNetwork(network)
+	health, err := handler.CheckGateways(ctx, network.ID, true)
+	if err != nil {
+		log.Warnf("failed to check gateways of network '%s': %v", network.Name, err)
+	}
+	for _, v := range health {
+		out.Gateways = append(out.Gateways, srvutils.ToPBGatewayHealth(v))
+	}
+	return out, nil
+}
@@ -0,0 +1,269 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// safescale public-ip allocate ip1
+// safescale public-ip attach ip1 host1
+// safescale public-ip detach ip1
+// safescale public-ip release ip1
+// safescale public-ip list [--all]
+// safescale public-ip inspect ip1
+
+// PublicIPHandler ...
+var PublicIPHandler = handlers.NewPublicIPHandler
+
+// PublicIPListener is the public IP service grpc server
+type PublicIPListener struct{}
+
+// Allocate reserves a new public IP
+func (s *PublicIPListener) Allocate(ctx context.Context, in *pb.Reference) (_ *pb.PublicIP, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot allocate public IP: name is missing")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", name), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Public IP Allocate "+name); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", getUserMessage(err)).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot allocate public IP: no tenant set")
+	}
+
+	handler := PublicIPHandler(tenant.ServiceWithContext(ctx))
+	ip, err := handler.Allocate(ctx, name)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return srvutils.ToPBPublicIP(ip), nil
+}
+
+// Attach binds a public IP to a host, moving it from the host it's attached to if any
+func (s *PublicIPListener) Attach(ctx context.Context, in *pb.PublicIPAttachment) (_ *pb.PublicIP, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ipRef := srvutils.GetReference(in.GetPublicIp())
+	if ipRef == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot attach public IP: neither name nor id given as reference of public IP")
+	}
+	hostRef := srvutils.GetReference(in.GetHost())
+	if hostRef == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot attach public IP: neither name nor id given as reference of host")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", ipRef, hostRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Public IP Attach "+ipRef+" "+hostRef); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", getUserMessage(err)).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot attach public IP: no tenant set")
+	}
+
+	handler := PublicIPHandler(tenant.ServiceWithContext(ctx))
+	ip, err := handler.Attach(ctx, ipRef, hostRef)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	log.Infof("Public IP '%s' attached to host '%s'", ipRef, hostRef)
+	return srvutils.ToPBPublicIP(ip), nil
+}
+
+// Detach unbinds a public IP from its host
+func (s *PublicIPListener) Detach(ctx context.Context, in *pb.Reference) (_ *pb.PublicIP, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot detach public IP: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Public IP Detach "+ref); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", getUserMessage(err)).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot detach public IP: no tenant set")
+	}
+
+	handler := PublicIPHandler(tenant.ServiceWithContext(ctx))
+	ip, err := handler.Detach(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return srvutils.ToPBPublicIP(ip), nil
+}
+
+// Release gives back a public IP to the provider
+func (s *PublicIPListener) Release(ctx context.Context, in *pb.Reference) (_ *googleprotobuf.Empty, err error) {
+	empty := &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, status.Errorf(codes.InvalidArgument, "cannot release public IP: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Public IP Release "+ref); err != nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", getUserMessage(err)).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot release public IP: no tenant set")
+	}
+
+	handler := PublicIPHandler(tenant.ServiceWithContext(ctx))
+	err = handler.Release(ctx, ref)
+	if err != nil {
+		return empty, status.Errorf(codes.Internal, fmt.Sprintf("cannot release public IP '%s': %s", ref, getUserMessage(err)))
+	}
+	return empty, nil
+}
+
+// List returns the public IPs managed by SafeScale, or all the public IPs of the tenant
+func (s *PublicIPListener) List(ctx context.Context, in *pb.PublicIPListRequest) (_ *pb.PublicIPList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	all := in.GetAll()
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("(%v)", all), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Public IPs List"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list public IPs: no tenant set")
+	}
+
+	handler := PublicIPHandler(tenant.ServiceWithContext(ctx))
+	ips, err := handler.List(ctx, all)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	var pbIPs []*pb.PublicIP
+	for _, ip := range ips {
+		pbIPs = append(pbIPs, srvutils.ToPBPublicIP(ip))
+	}
+	return &pb.PublicIPList{PublicIps: pbIPs}, nil
+}
+
+// Inspect returns the public IP referenced
+func (s *PublicIPListener) Inspect(ctx context.Context, in *pb.Reference) (_ *pb.PublicIP, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return nil, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	ref := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot inspect public IP: neither name nor id given as reference")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", ref), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// FIXME: handle error
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Public IP Inspect "+ref); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect public IP: no tenant set")
+	}
+
+	handler := PublicIPHandler(tenant.ServiceWithContext(ctx))
+	ip, err := handler.Inspect(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+	return srvutils.ToPBPublicIP(ip), nil
+}
@@ -42,6 +42,8 @@ var ShareHandler = handlers.NewShareHandler
 // safescale nas|share umount share1 host2
 // safescale nas|share list
 // safescale nas|share inspect share1
+// safescale nas|share acl add share1 host2:ro
+// safescale nas|share acl remove share1 host2
 
 // ShareListener Share service server grpc
 type ShareListener struct{}
@@ -75,8 +77,12 @@ func (s *ShareListener) Create(ctx context.Context, in *pb.ShareDefinition) (sd
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot create share: no tenant set")
 	}
 
-	handler := ShareHandler(tenant.Service)
-	share, err := handler.Create(ctx, shareName, hostRef, sharePath, in.GetSecurityModes(), in.GetOptions().GetReadOnly(), in.GetOptions().GetRootSquash(), in.GetOptions().GetSecure(), in.GetOptions().GetAsync(), in.GetOptions().GetNoHide(), in.GetOptions().GetCrossMount(), in.GetOptions().GetSubtreeCheck())
+	handler := ShareHandler(tenant.ServiceWithContext(ctx))
+	acls := map[string]bool{}
+	for _, v := range in.GetAcls() {
+		acls[srvutils.GetReference(v.GetHost())] = v.GetReadOnly()
+	}
+	share, err := handler.Create(ctx, shareName, hostRef, sharePath, shareType, in.GetSecurityModes(), acls, in.GetOptions().GetReadOnly(), in.GetOptions().GetRootSquash(), in.GetOptions().GetSecure(), in.GetOptions().GetAsync(), in.GetOptions().GetNoHide(), in.GetOptions().GetCrossMount(), in.GetOptions().GetSubtreeCheck())
 	if err != nil {
 		tbr := scerr.Wrap(err, fmt.Sprintf("cannot create share '%s'", shareName)+adaptedUserMessage(err))
 		return nil, status.Errorf(codes.Internal, tbr.Message())
@@ -111,7 +117,7 @@ func (s *ShareListener) Delete(ctx context.Context, in *pb.Reference) (empty *go
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete share: no tenant set")
 	}
 
-	handler := ShareHandler(tenant.Service)
+	handler := ShareHandler(tenant.ServiceWithContext(ctx))
 	_, _, _, err = handler.Inspect(ctx, shareName)
 	if err != nil {
 		switch err.(type) {
@@ -150,7 +156,7 @@ func (s *ShareListener) List(ctx context.Context, in *googleprotobuf.Empty) (sl
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list shares: no tenant set")
 	}
 
-	handler := ShareHandler(tenant.Service)
+	handler := ShareHandler(tenant.ServiceWithContext(ctx))
 	shares, err := handler.List(ctx)
 	if err != nil {
 		tbr := scerr.Wrap(err, "cannot list Shares"+adaptedUserMessage(err))
@@ -196,7 +202,7 @@ func (s *ShareListener) Mount(ctx context.Context, in *pb.ShareMountDefinition)
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot mount share: no tenant set")
 	}
 
-	handler := ShareHandler(tenant.Service)
+	handler := ShareHandler(tenant.ServiceWithContext(ctx))
 	mount, err := handler.Mount(ctx, shareRef, hostRef, hostPath, in.GetWithCache())
 	if err != nil {
 		tbr := scerr.Wrap(err, fmt.Sprintf("cannot mount share '%s'", shareRef)+adaptedUserMessage(err))
@@ -236,7 +242,7 @@ func (s *ShareListener) Unmount(ctx context.Context, in *pb.ShareMountDefinition
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot unmount share: no tenant set")
 	}
 
-	handler := ShareHandler(tenant.Service)
+	handler := ShareHandler(tenant.ServiceWithContext(ctx))
 	err = handler.Unmount(ctx, shareRef, hostRef)
 	if err != nil {
 		return empty, status.Errorf(codes.Internal, scerr.Wrap(err, fmt.Sprintf("cannot unmount share '%s'", shareRef)+adaptedUserMessage(err)).Message())
@@ -244,6 +250,82 @@ func (s *ShareListener) Unmount(ctx context.Context, in *pb.ShareMountDefinition
 	return empty, nil
 }
 
+// AddACL allows hosts to mount the share
+func (s *ShareListener) AddACL(ctx context.Context, in *pb.ShareACLDefinition) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	shareRef := srvutils.GetReference(in.GetShare())
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", shareRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Add ACL to share "+shareRef); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't add ACL to share: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot add ACL to share: no tenant set")
+	}
+
+	acls := map[string]bool{}
+	for _, v := range in.GetAcls() {
+		acls[srvutils.GetReference(v.GetHost())] = v.GetReadOnly()
+	}
+	handler := ShareHandler(tenant.ServiceWithContext(ctx))
+	err = handler.AddACL(ctx, shareRef, acls)
+	if err != nil {
+		return empty, status.Errorf(codes.Internal, scerr.Wrap(err, fmt.Sprintf("cannot add ACL to share '%s'", shareRef)+adaptedUserMessage(err)).Message())
+	}
+	return empty, nil
+}
+
+// RemoveACL forbids hosts to mount the share
+func (s *ShareListener) RemoveACL(ctx context.Context, in *pb.ShareACLDefinition) (empty *googleprotobuf.Empty, err error) {
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	shareRef := srvutils.GetReference(in.GetShare())
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s')", shareRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Remove ACL from share "+shareRef); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't remove ACL from share: no tenant set")
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot remove ACL from share: no tenant set")
+	}
+
+	var hosts []string
+	for _, v := range in.GetAcls() {
+		hosts = append(hosts, srvutils.GetReference(v.GetHost()))
+	}
+	handler := ShareHandler(tenant.ServiceWithContext(ctx))
+	err = handler.RemoveACL(ctx, shareRef, hosts)
+	if err != nil {
+		return empty, status.Errorf(codes.Internal, scerr.Wrap(err, fmt.Sprintf("cannot remove ACL from share '%s'", shareRef)+adaptedUserMessage(err)).Message())
+	}
+	return empty, nil
+}
+
 // Inspect shows the detail of a share and all connected clients
 func (s *ShareListener) Inspect(ctx context.Context, in *pb.Reference) (sml *pb.ShareMountList, err error) {
 	if s == nil {
@@ -271,7 +353,7 @@ func (s *ShareListener) Inspect(ctx context.Context, in *pb.Reference) (sml *pb.
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect share: no tenant set")
 	}
 
-	handler := ShareHandler(tenant.Service)
+	handler := ShareHandler(tenant.ServiceWithContext(ctx))
 	host, share, mounts, err := handler.Inspect(ctx, shareRef)
 	if err != nil {
 		err := scerr.Wrap(err, fmt.Sprintf("cannot inspect share '%s'", shareRef)+adaptedUserMessage(err))
@@ -73,7 +73,7 @@ func (s *SSHListener) Run(ctx context.Context, in *pb.SshCommand) (sr *pb.SshRes
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot execute ssh command: no tenant set")
 	}
 
-	handler := SSHHandler(tenant.Service)
+	handler := SSHHandler(tenant.ServiceWithContext(ctx))
 	retcode, stdout, stderr, err := handler.Run(ctx, host, command, outputs.DISPLAY)
 	if err != nil {
 		err = status.Errorf(codes.Internal, getUserMessage(err))
@@ -113,7 +113,7 @@ func (s *SSHListener) Copy(ctx context.Context, in *pb.SshCopyCommand) (sr *pb.S
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot copy by ssh: no tenant set")
 	}
 
-	handler := SSHHandler(tenant.Service)
+	handler := SSHHandler(tenant.ServiceWithContext(ctx))
 	retcode, stdout, stderr, err := handler.Copy(ctx, source, dest)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
@@ -19,12 +19,14 @@ package listeners
 import (
 	"context"
 
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
@@ -60,17 +62,67 @@ func (s *TemplateListener) List(ctx context.Context, in *pb.TemplateListRequest)
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list templates: no tenant set")
 	}
 
-	handler := TemplateHandler(tenant.Service)
+	handler := TemplateHandler(tenant.ServiceWithContext(ctx))
 	templates, err := handler.List(ctx, all)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
 	}
 
+	// With scanned, only the templates measured by the scanner are listed, with their measurements
+	var scans map[string]*resources.StoredCPUInfo
+	if in.GetScanned() {
+		scans, err = handler.Scans(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, getUserMessage(err))
+		}
+	}
+
 	// Map resources.Host to pb.Host
 	var pbTemplates []*pb.HostTemplate
 	for _, template := range templates {
-		pbTemplates = append(pbTemplates, srvutils.ToPBHostTemplate(&template))
+		pbTemplate := srvutils.ToPBHostTemplate(&template)
+		if in.GetScanned() {
+			scan, ok := scans[template.ID]
+			if !ok {
+				continue
+			}
+			pbTemplate.Scan = srvutils.ToPBTemplateScan(scan)
+		}
+		pbTemplates = append(pbTemplates, pbTemplate)
 	}
 	rv := &pb.TemplateList{Templates: pbTemplates}
 	return rv, nil
 }
+
+// Presets lists the presets of the tenant
+func (s *TemplateListener) Presets(ctx context.Context, in *googleprotobuf.Empty) (pl *pb.TemplatePresetList, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Template Presets"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't list presets: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot list presets: no tenant set")
+	}
+
+	presets, err := TemplateHandler(tenant.ServiceWithContext(ctx)).Presets(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	pl = &pb.TemplatePresetList{}
+	for _, p := range presets {
+		pl.Presets = append(pl.Presets, &pb.TemplatePreset{Name: p.Name, Sizing: p.Sizing, Image: p.Image})
+	}
+	return pl, nil
+}
@@ -26,12 +26,16 @@ import (
 	"google.golang.org/grpc/status"
 
 	pb "github.com/CS-SI/SafeScale/lib"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 )
 
+// CostHandler exists to ease integration tests
+var CostHandler = handlers.NewCostHandler
+
 // Tenant structure to handle name and clientAPI for a tenant
 type Tenant struct {
 	name    string
@@ -42,6 +46,11 @@ var (
 	currentTenant *Tenant
 )
 
+// ServiceWithContext returns the service of the tenant, tracing its provider calls in ctx
+func (t *Tenant) ServiceWithContext(ctx context.Context) iaas.Service {
+	return iaas.WithContext(ctx, t.Service)
+}
+
 // GetCurrentTenant contains the current tenant
 var GetCurrentTenant = getCurrentTenant
 
@@ -159,3 +168,44 @@ func (s *TenantListener) Set(ctx context.Context, in *pb.TenantName) (empty *goo
 	log.Infof("Current tenant is now '%s'", name)
 	return empty, nil
 }
+
+// Cost estimates the price per hour of the hosts and volumes of the current tenant
+func (s *TenantListener) Cost(ctx context.Context, in *googleprotobuf.Empty) (tc *pb.TenantCost, err error) {
+	if s == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, scerr.InvalidInstanceError().Message())
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Tenant Cost"); err == nil {
+		defer srvutils.JobDeregister(ctx)
+	}
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		log.Info("Can't estimate cost: no tenant set")
+		return nil, status.Errorf(codes.FailedPrecondition, "cannot estimate cost: no tenant set")
+	}
+
+	currency, costs, err := CostHandler(tenant.ServiceWithContext(ctx)).Estimate(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	tc = &pb.TenantCost{Currency: currency}
+	for _, c := range costs {
+		tc.Resources = append(tc.Resources, &pb.ResourceCost{
+			Kind:         c.Kind,
+			Id:           c.ID,
+			Name:         c.Name,
+			NetworkId:    c.NetworkID,
+			NetworkName:  c.NetworkName,
+			PricePerHour: c.PricePerHour,
+			Priced:       c.Priced,
+		})
+	}
+	return tc, nil
+}
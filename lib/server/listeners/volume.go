@@ -74,7 +74,7 @@ func (s *VolumeListener) List(ctx context.Context, in *pb.VolumeListRequest) (_
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot list volumes: no tenant set")
 	}
 
-	handler := VolumeHandler(tenant.Service)
+	handler := VolumeHandler(tenant.ServiceWithContext(ctx))
 	volumes, err := handler.List(ctx, in.GetAll())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
@@ -100,9 +100,11 @@ func (s *VolumeListener) Create(ctx context.Context, in *pb.VolumeDefinition) (_
 	name := in.GetName()
 	speed := in.GetSpeed()
 	size := in.GetSize()
+	raid := in.GetRaid()
+	count := in.GetCount()
 	// FIXME: validate parameters
 
-	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %s, %d)", name, speed.String(), size), true).WithStopwatch().GoingIn()
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', %s, %d, '%s', %d)", name, speed.String(), size, raid, count), true).WithStopwatch().GoingIn()
 	defer tracer.OnExitTrace()()
 	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
 
@@ -118,8 +120,8 @@ func (s *VolumeListener) Create(ctx context.Context, in *pb.VolumeDefinition) (_
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot create volume: no tenant set")
 	}
 
-	handler := VolumeHandler(tenant.Service)
-	vol, err := handler.Create(ctx, name, int(size), volumespeed.Enum(speed))
+	handler := VolumeHandler(tenant.ServiceWithContext(ctx))
+	vol, err := handler.Create(ctx, name, int(size), volumespeed.Enum(speed), raid, int(count))
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
 	}
@@ -141,7 +143,7 @@ func (s *VolumeListener) Expand(ctx context.Context, in *pb.VolumeSizeChange) (*
 	}
 
 	hostName := in.GetHostName().GetName()
-	handler := VolumeHandler(tenant.Service)
+	handler := VolumeHandler(tenant.ServiceWithContext(ctx))
 
 	err := handler.Expand(ctx, volumeName, hostName, in.ChangeSize, in.ChangeSizeType)
 	if err != nil {
@@ -165,7 +167,7 @@ func (s *VolumeListener) Shrink(ctx context.Context, in *pb.VolumeSizeChange) (*
 	}
 
 	hostName := in.GetHostName().GetName()
-	handler := VolumeHandler(tenant.Service)
+	handler := VolumeHandler(tenant.ServiceWithContext(ctx))
 
 	err := handler.Shrink(ctx, volumeName, hostName, in.ChangeSize, in.ChangeSizeType)
 	if err != nil {
@@ -222,8 +224,8 @@ func (s *VolumeListener) Attach(ctx context.Context, in *pb.VolumeAttachment) (_
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot attach volume: no tenant set")
 	}
 
-	handler := VolumeHandler(tenant.Service)
-	_, err = handler.Attach(ctx, volumeRef, hostRef, mountPath, filesystem, doNotFormat)
+	handler := VolumeHandler(tenant.ServiceWithContext(ctx))
+	_, err = handler.Attach(ctx, volumeRef, hostRef, mountPath, filesystem, doNotFormat, in.GetEncrypt())
 	if err != nil {
 		return empty, status.Errorf(codes.Internal, getUserMessage(err))
 	}
@@ -265,7 +267,7 @@ func (s *VolumeListener) Detach(ctx context.Context, in *pb.VolumeDetachment) (_
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot detach volume: no tenant set")
 	}
 
-	handler := VolumeHandler(tenant.Service)
+	handler := VolumeHandler(tenant.ServiceWithContext(ctx))
 	err = handler.Detach(ctx, volumeRef, hostRef)
 	if err != nil {
 		return empty, status.Errorf(codes.Internal, getUserMessage(err))
@@ -275,6 +277,49 @@ func (s *VolumeListener) Detach(ctx context.Context, in *pb.VolumeDetachment) (_
 	return empty, nil
 }
 
+// Migrate moves a volume to another host
+func (s *VolumeListener) Migrate(ctx context.Context, in *pb.VolumeMigration) (_ *googleprotobuf.Empty, err error) {
+	empty := &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidInstanceError().Message())
+	}
+	if in == nil {
+		return empty, status.Errorf(codes.InvalidArgument, scerr.InvalidParameterError("in", "cannot be nil").Message())
+	}
+	volumeRef := srvutils.GetReference(in.GetVolume())
+	if volumeRef == "" {
+		return empty, status.Errorf(codes.InvalidArgument, "cannot migrate volume: neither name nor id given as reference for volume")
+	}
+	hostRef := srvutils.GetReference(in.GetHost())
+	if hostRef == "" {
+		return empty, status.Errorf(codes.InvalidArgument, "cannot migrate volume: neither name nor id given as reference for host")
+	}
+
+	tracer := concurrency.NewTracer(nil, fmt.Sprintf("('%s', '%s')", volumeRef, hostRef), true).WithStopwatch().GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	if err := srvutils.JobRegister(ctx, cancelFunc, "Volume migrate "+volumeRef+" to host "+hostRef); err != nil {
+		return empty, status.Errorf(codes.FailedPrecondition, fmt.Errorf("failed to register the process : %s", getUserMessage(err)).Error())
+	}
+	defer srvutils.JobDeregister(ctx)
+
+	tenant := GetCurrentTenant()
+	if tenant == nil {
+		return empty, status.Errorf(codes.FailedPrecondition, "cannot migrate volume: no tenant set")
+	}
+
+	handler := VolumeHandler(tenant.ServiceWithContext(ctx))
+	err = handler.Migrate(ctx, volumeRef, hostRef)
+	if err != nil {
+		return empty, status.Errorf(codes.Internal, getUserMessage(err))
+	}
+
+	log.Infof("Volume '%s' migrated to '%s'", volumeRef, hostRef)
+	return empty, nil
+}
+
 // Delete a volume
 func (s *VolumeListener) Delete(ctx context.Context, in *pb.Reference) (_ *googleprotobuf.Empty, err error) {
 	empty := &googleprotobuf.Empty{}
@@ -305,7 +350,7 @@ func (s *VolumeListener) Delete(ctx context.Context, in *pb.Reference) (_ *googl
 		return empty, status.Errorf(codes.FailedPrecondition, "cannot delete volume: no tenant set")
 	}
 
-	handler := VolumeHandler(tenant.Service)
+	handler := VolumeHandler(tenant.ServiceWithContext(ctx))
 	err = handler.Delete(ctx, ref)
 	if err != nil {
 		return empty, status.Errorf(codes.Internal, fmt.Sprintf("cannot delete volume '%s': %s", ref, getUserMessage(err)))
@@ -343,7 +388,7 @@ func (s *VolumeListener) Inspect(ctx context.Context, in *pb.Reference) (_ *pb.V
 		return nil, status.Errorf(codes.FailedPrecondition, "cannot inspect volume: no tenant set")
 	}
 
-	handler := VolumeHandler(tenant.Service)
+	handler := VolumeHandler(tenant.ServiceWithContext(ctx))
 	volume, mounts, err := handler.Inspect(ctx, ref)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, getUserMessage(err))
@@ -352,5 +397,14 @@ func (s *VolumeListener) Inspect(ctx context.Context, in *pb.Reference) (_ *pb.V
 		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("cannot inspect volume '%s': volume not found", ref))
 	}
 
-	return srvutils.ToPBVolumeInfo(volume, mounts), nil
+	info := srvutils.ToPBVolumeInfo(volume, mounts)
+	if info.GetRaid() != "" {
+		var healthErr error
+		info.RaidHealth, healthErr = handler.GetRAIDHealth(ctx, volume)
+		if healthErr != nil {
+			log.Warnf("failed to get health of RAID volume '%s': %v", ref, healthErr)
+			info.RaidHealth = "unknown"
+		}
+	}
+	return info, nil
 }
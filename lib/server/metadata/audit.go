@@ -0,0 +1,124 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+const (
+	// auditFolderName is the technical name of the folder used to store audit events
+	auditFolderName = "audit"
+	// auditDayLayout is the layout of the date used to group audit events by day
+	auditDayLayout = "2006-01-02"
+)
+
+// AuditEvent describes a mutating operation done by safescaled
+// Principal is advisory only: it is built from the metadata sent by the client ('user@hostname'), which is not
+// authenticated, and from the address of the client
+type AuditEvent struct {
+	Time         time.Time              `json:"time"`
+	JobUUID      string                 `json:"job_uuid,omitempty"`
+	Principal    string                 `json:"principal,omitempty"`
+	Tenant       string                 `json:"tenant,omitempty"`
+	Method       string                 `json:"method"`
+	ResourceKind string                 `json:"resource_kind"`
+	ResourceRef  string                 `json:"resource_ref,omitempty"`
+	Parameters   map[string]interface{} `json:"parameters,omitempty"`
+	Outcome      string                 `json:"outcome"`
+	Error        string                 `json:"error,omitempty"`
+	Duration     float64                `json:"duration"`
+}
+
+// Audit gives access to the audit events stored in the metadata of a tenant
+type Audit struct {
+	folder *metadata.Folder
+}
+
+// NewAudit creates an instance of Audit
+func NewAudit(svc iaas.Service) (*Audit, error) {
+	folder, err := metadata.NewFolder(svc, auditFolderName)
+	if err != nil {
+		return nil, err
+	}
+	return &Audit{folder: folder}, nil
+}
+
+// Write stores the audit event in Object Storage, in the folder of the day of the event
+func (ma *Audit) Write(event *AuditEvent) error {
+	if ma == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if event == nil {
+		return scerr.InvalidParameterError("event", "cannot be nil")
+	}
+
+	content, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	name := event.Time.UTC().Format("150405.000000000")
+	if event.JobUUID != "" {
+		name += "-" + event.JobUUID
+	}
+	return ma.folder.Write(event.Time.UTC().Format(auditDayLayout), name, content)
+}
+
+// Browse walks through the audit events of the days since 'since' (all days if zero) and executes a callback
+// for each event occurring after 'since' and concerning the resource kind 'kind' (all kinds if empty)
+func (ma *Audit) Browse(since time.Time, kind string, callback func(*AuditEvent) error) error {
+	if ma == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if callback == nil {
+		return scerr.InvalidParameterError("callback", "cannot be nil")
+	}
+
+	filter := func(buf []byte) error {
+		event := &AuditEvent{}
+		err := json.Unmarshal(buf, event)
+		if err != nil {
+			return err
+		}
+		if !since.IsZero() && event.Time.Before(since) {
+			return nil
+		}
+		if kind != "" && !strings.EqualFold(event.ResourceKind, kind) {
+			return nil
+		}
+		return callback(event)
+	}
+
+	if since.IsZero() {
+		return ma.folder.Browse("", filter)
+	}
+	now := time.Now().UTC()
+	for day := since.UTC(); !day.After(now.Add(24 * time.Hour)); day = day.Add(24 * time.Hour) {
+		err := ma.folder.Browse(day.Format(auditDayLayout), filter)
+		if err != nil {
+			return fmt.Errorf("failed to browse audit events of %s: %s", day.Format(auditDayLayout), err.Error())
+		}
+	}
+	return nil
+}
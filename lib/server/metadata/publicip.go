@@ -0,0 +1,384 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/metadata"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+	"github.com/CS-SI/SafeScale/lib/utils/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// publicIPsFolderName is the technical name of the container used to store public IP info
+	publicIPsFolderName = "publicips"
+)
+
+// PublicIP links Object Storage folder and PublicIPs
+type PublicIP struct {
+	item *metadata.Item
+	name *string
+	id   *string
+}
+
+// NewPublicIP creates an instance of metadata.PublicIP
+func NewPublicIP(svc iaas.Service) (*PublicIP, error) {
+	if svc == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+
+	anIP, err := metadata.NewItem(svc, publicIPsFolderName)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicIP{
+		item: anIP,
+		name: nil,
+		id:   nil,
+	}, nil
+}
+
+// Carry links a PublicIP instance to the Metadata instance
+func (mip *PublicIP) Carry(ip *resources.PublicIP) (*PublicIP, error) {
+	if mip == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if mip.item == nil {
+		return nil, scerr.InvalidInstanceContentError("mip.item", "cannot be nil")
+	}
+	if ip == nil {
+		return nil, scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+	mip.item.Carry(ip)
+	mip.name = &ip.Name
+	mip.id = &ip.ID
+	return mip, nil
+}
+
+// Get returns the PublicIP instance linked to metadata
+func (mip *PublicIP) Get() (*resources.PublicIP, error) {
+	if mip == nil {
+		return nil, scerr.InvalidInstanceError()
+	}
+	if mip.item == nil {
+		return nil, scerr.InvalidInstanceContentError("mip.item", "cannot be nil")
+	}
+	if ip, ok := mip.item.Get().(*resources.PublicIP); ok {
+		return ip, nil
+	}
+	return nil, scerr.InconsistentError("invalid content in public IP metadata")
+}
+
+// Write updates the metadata corresponding to the public IP in the Object Storage
+func (mip *PublicIP) Write() error {
+	if mip == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mip.item == nil {
+		return scerr.InvalidInstanceContentError("mip.item", "cannot be nil!")
+	}
+
+	err := mip.item.WriteInto(ByIDFolderName, *mip.id)
+	if err != nil {
+		return err
+	}
+	return mip.item.WriteInto(ByNameFolderName, *mip.name)
+}
+
+// Reload reloads the content of the Object Storage, overriding what is in the metadata instance
+func (mip *PublicIP) Reload() error {
+	if mip == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mip.item == nil {
+		return scerr.InvalidInstanceContentError("mip.item", "cannot be nil")
+	}
+	err := mip.ReadByID(*mip.id)
+	if err != nil {
+		if _, ok := scerr.Cause(err).(scerr.ErrNotFound); ok {
+			return scerr.NotFoundError(fmt.Sprintf("metadata of public IP '%s' vanished", *mip.name))
+		}
+		return err
+	}
+	return nil
+}
+
+// ReadByReference tries to read with 'ref' as id, then if not found as name
+func (mip *PublicIP) ReadByReference(ref string) (err error) {
+	if mip == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mip.item == nil {
+		return scerr.InvalidInstanceContentError("mip.item", "cannot be nil")
+	}
+	if ref == "" {
+		return scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	var errors []error
+	err = mip.mayReadByID(ref) // First read by ID ...
+	if err != nil {
+		errors = append(errors, err)
+		err = mip.mayReadByName(ref) // ... then read by name only if by id failed (no need to read twice if the 2 exist)
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if err != nil {
+		return scerr.NotFoundErrorWithCause(fmt.Sprintf("reference %s not found", ref), scerr.ErrListError(errors))
+	}
+
+	return nil
+}
+
+// mayReadByID reads the metadata of a public IP identified by ID from Object Storage
+// Doesn't log error or validate parameters by design; caller does that
+func (mip *PublicIP) mayReadByID(id string) error {
+	ip := resources.NewPublicIP()
+	err := mip.item.ReadFrom(ByIDFolderName, id, func(buf []byte) (serialize.Serializable, error) {
+		err := ip.Deserialize(buf)
+		if err != nil {
+			return nil, err
+		}
+		return ip, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = mip.Carry(ip)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// mayReadByName reads the metadata of a public IP identified by name
+// Doesn't log error or validate parameters by design; caller does that
+func (mip *PublicIP) mayReadByName(name string) error {
+	ip := resources.NewPublicIP()
+	err := mip.item.ReadFrom(ByNameFolderName, name, func(buf []byte) (serialize.Serializable, error) {
+		err := ip.Deserialize(buf)
+		if err != nil {
+			return nil, err
+		}
+		return ip, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = mip.Carry(ip)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReadByID reads the metadata of a public IP identified by ID from Object Storage
+func (mip *PublicIP) ReadByID(id string) (err error) {
+	if mip == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mip.item == nil {
+		return scerr.InvalidInstanceContentError("mip.item", "cannot be nil")
+	}
+	if id == "" {
+		return scerr.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+id+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return mip.mayReadByID(id)
+}
+
+// ReadByName reads the metadata of a public IP identified by name
+func (mip *PublicIP) ReadByName(name string) (err error) {
+	if mip == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mip.item == nil {
+		return scerr.InvalidInstanceContentError("mip.item", "cannot be nil")
+	}
+	if name == "" {
+		return scerr.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "('"+name+"')", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return mip.mayReadByName(name)
+}
+
+// Delete delete the metadata corresponding to the public IP
+func (mip *PublicIP) Delete() (err error) {
+	if mip == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mip.item == nil {
+		return scerr.InvalidInstanceContentError("mip.item", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	err = mip.item.DeleteFrom(ByIDFolderName, *mip.id)
+	if err != nil {
+		return err
+	}
+	err = mip.item.DeleteFrom(ByNameFolderName, *mip.name)
+	if err != nil {
+		return err
+	}
+	mip.item.Reset()
+	mip.name = nil
+	mip.id = nil
+	return nil
+}
+
+// Browse walks through public IP folder and executes a callback for each entries
+func (mip *PublicIP) Browse(callback func(*resources.PublicIP) error) (err error) {
+	if mip == nil {
+		return scerr.InvalidInstanceError()
+	}
+	if mip.item == nil {
+		return scerr.InvalidInstanceContentError("mip.item", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	return mip.item.BrowseInto(ByIDFolderName, func(buf []byte) error {
+		ip := resources.NewPublicIP()
+		err := ip.Deserialize(buf)
+		if err != nil {
+			return err
+		}
+		return callback(ip)
+	})
+}
+
+// SavePublicIP saves the PublicIP definition in Object Storage
+func SavePublicIP(svc iaas.Service, ip *resources.PublicIP) (mip *PublicIP, err error) {
+	if svc == nil {
+		return nil, scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if ip == nil {
+		return nil, scerr.InvalidParameterError("ip", "cannot be nil")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+ip.Name+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mip, err = NewPublicIP(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	mp, err := mip.Carry(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mp.Write()
+	if err != nil {
+		return nil, err
+	}
+
+	return mip, nil
+}
+
+// RemovePublicIP removes the PublicIP definition from Object Storage
+func RemovePublicIP(svc iaas.Service, ipID string) (err error) {
+	if svc == nil {
+		return scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if ipID == "" {
+		return scerr.InvalidParameterError("ipID", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+ipID+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	m, err := LoadPublicIP(svc, ipID)
+	if err != nil {
+		return err
+	}
+	return m.Delete()
+}
+
+// LoadPublicIP gets the PublicIP definition from Object Storage
+// logic: Read by ID; if error is ErrNotFound then read by name; if error is ErrNotFound return this error.
+// In case of any other error, abort the retry to propagate the error.
+// If retry times out, return errNotFound
+func LoadPublicIP(svc iaas.Service, ref string) (mip *PublicIP, err error) {
+	if svc == nil {
+		return nil, scerr.InvalidParameterError("svc", "cannot be nil")
+	}
+	if ref == "" {
+		return nil, scerr.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	tracer := concurrency.NewTracer(nil, "("+ref+")", true).GoingIn()
+	defer tracer.OnExitTrace()()
+	defer scerr.OnExitLogError(tracer.TraceMessage(""), &err)()
+
+	mip, err = NewPublicIP(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	retryErr := retry.WhileUnsuccessfulDelay1Second(
+		func() error {
+			innerErr := mip.ReadByReference(ref)
+			if innerErr != nil {
+				if _, ok := innerErr.(scerr.ErrNotFound); ok {
+					return retry.AbortedError("no metadata found", innerErr)
+				}
+				return innerErr
+			}
+			return nil
+		},
+		2*temporal.GetDefaultDelay(),
+	)
+	if retryErr != nil {
+		switch err := retryErr.(type) {
+		case retry.ErrAborted:
+			return nil, err.Cause()
+		case scerr.ErrTimeout:
+			return nil, err
+		default:
+			return nil, scerr.Cause(err)
+		}
+	}
+
+	return mip, nil
+}
@@ -18,6 +18,8 @@ package utils
 
 import (
 	"context"
+	"os"
+	"os/user"
 	"sync"
 	"time"
 
@@ -40,10 +42,24 @@ func GetContext(storeUUID bool) (context.Context, error) {
 	if err != nil {
 		return nil, err
 	}
-	clientContext = metadata.AppendToOutgoingContext(clientContext, "UUID", aUUID)
+	clientContext = metadata.AppendToOutgoingContext(clientContext, "UUID", aUUID, "caller", getCaller())
 	return clientContext, nil
 }
 
+// GetAsyncContext returns a context asking safescaled to run the command in background, and the UUID of the job
+// If timeout > 0, safescaled aborts the job once 'timeout' is reached
+func GetAsyncContext(timeout time.Duration) (context.Context, string, error) {
+	clientContext, err := GetContext(true)
+	if err != nil {
+		return nil, "", err
+	}
+	clientContext = metadata.AppendToOutgoingContext(clientContext, "async", "true")
+	if timeout > 0 {
+		clientContext = metadata.AppendToOutgoingContext(clientContext, "timeout", timeout.String())
+	}
+	return clientContext, GetUUID(), nil
+}
+
 // GetTimeoutContext return a context for grpc commands
 func GetTimeoutContext(timeout time.Duration) (context.Context, context.CancelFunc, error) {
 	// Contact the server and print out its response.
@@ -64,6 +80,18 @@ func GetUUID() string {
 	return clientRPCUUID.String()
 }
 
+// getCaller returns an identification of the user running the client ('user@hostname')
+func getCaller() string {
+	var caller string
+	if u, err := user.Current(); err == nil {
+		caller = u.Username
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		caller += "@" + hostname
+	}
+	return caller
+}
+
 // generateUUID ...
 func generateUUID(store bool) (string, error) {
 	mutexContextManager.Lock()
@@ -17,13 +17,21 @@
 package utils
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
+	"sort"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	pb "github.com/CS-SI/SafeScale/lib"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources"
 	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/networkproperty"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/resources/enums/volumeproperty"
+	"github.com/CS-SI/SafeScale/lib/server/metadata"
 	propsv1 "github.com/CS-SI/SafeScale/lib/server/iaas/resources/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/system"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
@@ -73,6 +81,16 @@ func ToPBVolume(in *resources.Volume) *pb.Volume {
 	}
 }
 
+// ToPBPublicIP converts a resources.PublicIP to a *PublicIP
+func ToPBPublicIP(in *resources.PublicIP) *pb.PublicIP {
+	return &pb.PublicIP{
+		Id:      in.ID,
+		Name:    in.Name,
+		Address: in.Address,
+		HostId:  in.HostID,
+	}
+}
+
 // ToPBVolumeAttachment converts an api.Volume to a *Volume
 func ToPBVolumeAttachment(in *resources.VolumeAttachment) *pb.VolumeAttachment {
 	return &pb.VolumeAttachment{
@@ -101,6 +119,16 @@ func ToPBVolumeInfo(volume *resources.Volume, mounts map[string]*propsv1.HostLoc
 			break
 		}
 	}
+	_ = volume.Properties.LockForRead(volumeproperty.EncryptionV1).ThenUse(func(clonable data.Clonable) error {
+		pbvi.Encrypted = clonable.(*propsv1.VolumeEncryption).IsEncrypted()
+		return nil
+	})
+	_ = volume.Properties.LockForRead(volumeproperty.DescriptionV1).ThenUse(func(clonable data.Clonable) error {
+		volumeDescriptionV1 := clonable.(*propsv1.VolumeDescription)
+		pbvi.Raid = volumeDescriptionV1.RAID
+		pbvi.RaidMembers = int32(len(volumeDescriptionV1.Members))
+		return nil
+	})
 	return pbvi
 }
 
@@ -126,12 +154,24 @@ func ToPBBucketMountPoint(in *resources.Bucket) *pb.BucketMountingPoint {
 
 // ToPBShare convert a share from model to protocolbuffer format
 func ToPBShare(hostName string, share *propsv1.HostShare) *pb.ShareDefinition {
+	var acls []*pb.ShareACL
+	for _, v := range share.ACLs {
+		acls = append(acls, &pb.ShareACL{
+			Host:     &pb.Reference{Id: v.HostID, Name: v.HostName},
+			ReadOnly: v.ReadOnly,
+		})
+	}
+	sort.Slice(acls, func(i, j int) bool {
+		return acls[i].Host.Name < acls[j].Host.Name
+	})
 	return &pb.ShareDefinition{
-		Id:   share.ID,
-		Name: share.Name,
-		Host: &pb.Reference{Name: hostName},
-		Path: share.Path,
-		Type: "nfs",
+		Id:            share.ID,
+		Name:          share.Name,
+		Host:          &pb.Reference{Name: hostName},
+		Path:          share.Path,
+		Type:          share.Type,
+		SecurityModes: share.SecurityModes,
+		Acls:          acls,
 	}
 }
 
@@ -153,7 +193,7 @@ func ToPBShareMountList(hostName string, share *propsv1.HostShare, mounts map[st
 			Host:  &pb.Reference{Name: k},
 			Share: &pb.Reference{Name: share.Name},
 			Path:  v.Path,
-			Type:  "nfs",
+			Type:  v.FileSystem,
 		})
 	}
 	return &pb.ShareMountList{
@@ -187,6 +227,16 @@ func ToPBHost(in *resources.Host) *pb.Host {
 	if err != nil {
 		return nil
 	}
+	var expiresIn string
+	if in.Properties.Lookup(hostproperty.LifecycleV1) {
+		err = in.Properties.LockForRead(hostproperty.LifecycleV1).ThenUse(func(clonable data.Clonable) error {
+			expiresIn = formatTimeRemaining(clonable.(*propsv1.HostLifecycle).ExpiresAt, time.Now())
+			return nil
+		})
+		if err != nil {
+			return nil
+		}
+	}
 	return &pb.Host{
 		Cpu:                 int32(hostSizingV1.AllocatedSize.Cores),
 		Disk:                int32(hostSizingV1.AllocatedSize.DiskSize),
@@ -194,15 +244,30 @@ func ToPBHost(in *resources.Host) *pb.Host {
 		Id:                  in.ID,
 		PublicIp:            in.GetPublicIP(),
 		PrivateIp:           in.GetPrivateIP(),
+		PrivateIpv6:         hostNetworkV1.IPv6Addresses[hostNetworkV1.DefaultNetworkID],
 		Name:                in.Name,
 		PrivateKey:          in.PrivateKey,
 		Password:            in.Password,
 		Ram:                 hostSizingV1.AllocatedSize.RAMSize,
 		State:               pb.HostState(in.LastState),
 		AttachedVolumeNames: volumes,
+		ExpiresIn:           expiresIn,
 	}
 }
 
+// formatTimeRemaining returns the time remaining at now before expiresAt (ex: "71h58m"), "expired" if expiresAt is
+// past, or an empty string if expiresAt is the zero time
+func formatTimeRemaining(expiresAt, now time.Time) string {
+	if expiresAt.IsZero() {
+		return ""
+	}
+	remaining := expiresAt.Sub(now)
+	if remaining <= 0 {
+		return "expired"
+	}
+	return fmt.Sprintf("%dh%02dm", int(remaining.Hours()), int(remaining.Minutes())%60)
+}
+
 // ToPBHostDefinition ...
 func ToPBHostDefinition(in *resources.HostDefinition) *pb.HostDefinition {
 	return &pb.HostDefinition{
@@ -264,16 +329,31 @@ func ToHostStatus(in *resources.Host) *pb.HostStatus {
 	}
 }
 
+// ToPBTemplateScan converts the measurements done by the scanner on a template to protocolbuffer format
+func ToPBTemplateScan(in *resources.StoredCPUInfo) *pb.TemplateScan {
+	return &pb.TemplateScan{
+		CpuFrequency: in.CPUFrequency,
+		CpuModel:     in.CPUModel,
+		GpuCount:     int32(in.GPU),
+		GpuModel:     in.GPUModel,
+		DiskType:     in.MainDiskType,
+		DiskSpeed:    in.MainDiskSpeed,
+		NetSpeed:     in.SampleNetSpeed,
+		LastUpdated:  in.LastUpdated,
+	}
+}
+
 // ToPBHostTemplate convert an template from api to protocolbuffer format
 func ToPBHostTemplate(in *resources.HostTemplate) *pb.HostTemplate {
 	return &pb.HostTemplate{
-		Id:       in.ID,
-		Name:     in.Name,
-		Cores:    int32(in.Cores),
-		Ram:      int32(in.RAMSize),
-		Disk:     int32(in.DiskSize),
-		GpuCount: int32(in.GPUNumber),
-		GpuType:  in.GPUType,
+		Id:           in.ID,
+		Name:         in.Name,
+		Cores:        int32(in.Cores),
+		Ram:          int32(in.RAMSize),
+		Disk:         int32(in.DiskSize),
+		GpuCount:     int32(in.GPUNumber),
+		GpuType:      in.GPUType,
+		PricePerHour: in.PricePerHour,
 	}
 }
 
@@ -291,17 +371,121 @@ func ToPBNetwork(in *resources.Network) *pb.Network {
 	if in.VIP != nil {
 		pbVIP = ToPBVirtualIP(*in.VIP)
 	}
+	var peers []string
+	if in.Properties != nil {
+		err := in.Properties.LockForRead(networkproperty.PeeringsV1).ThenUse(func(clonable data.Clonable) error {
+			for name := range clonable.(*propsv1.NetworkPeerings).ByName {
+				peers = append(peers, name)
+			}
+			return nil
+		})
+		if err != nil {
+			logrus.Warnf("failed to read peerings of network '%s': %v", in.Name, err)
+		}
+		sort.Strings(peers)
+	}
 	return &pb.Network{
 		Id:                 in.ID,
 		Name:               in.Name,
 		Cidr:               in.CIDR,
+		IpVersion:          ToPBIPVersion(in.IPVersion),
+		Ipv6Cidr:           in.IPv6CIDR,
 		GatewayId:          in.GatewayID,
 		SecondaryGatewayId: in.SecondaryGatewayID,
 		VirtualIp:          pbVIP,
 		Failover:           in.SecondaryGatewayID != "",
+		Peers:              peers,
 	}
 }
 
+// ToPBIPVersion converts an IP version to protocolbuffer format
+func ToPBIPVersion(in ipversion.Enum) pb.IPVersion {
+	switch in {
+	case ipversion.IPv6:
+		return pb.IPVersion_IPV6
+	case ipversion.DualStack:
+		return pb.IPVersion_DUAL_STACK
+	default:
+		return pb.IPVersion_IPV4
+	}
+}
+
+// FromPBIPVersion converts an IP version from protocolbuffer format
+func FromPBIPVersion(in pb.IPVersion) ipversion.Enum {
+	switch in {
+	case pb.IPVersion_IPV6:
+		return ipversion.IPv6
+	case pb.IPVersion_DUAL_STACK:
+		return ipversion.DualStack
+	default:
+		return ipversion.IPv4
+	}
+}
+
+// ToPBGatewayHealth converts the health of a gateway to protocolbuffer format
+func ToPBGatewayHealth(in *resources.GatewayHealth) *pb.GatewayHealth {
+	return &pb.GatewayHealth{
+		Id:        in.ID,
+		Name:      in.Name,
+		Primary:   in.Primary,
+		State:     in.State.String(),
+		Reachable: in.Reachable,
+		HoldsVip:  in.HoldsVIP,
+		Healthy:   in.Healthy(),
+		Error:     in.Error,
+	}
+}
+
+// ToPBNetworkVPN converts the VPN configuration of a network to protocolbuffer format; private key is not included
+func ToPBNetworkVPN(network *resources.Network, in *propsv1.NetworkVPN) *pb.NetworkVPN {
+	out := &pb.NetworkVPN{
+		Network:   network.Name,
+		Cidr:      network.CIDR,
+		Endpoint:  in.Endpoint,
+		PublicKey: in.PublicKey,
+	}
+	names := make([]string, 0, len(in.Peers))
+	for k := range in.Peers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		peer := in.Peers[k]
+		out.Peers = append(out.Peers, &pb.NetworkVPNPeer{
+			Name:       peer.Name,
+			PublicKey:  peer.PublicKey,
+			Endpoint:   peer.Endpoint,
+			AllowedIps: peer.AllowedIPs,
+		})
+	}
+	return out
+}
+
+// ToPBNetworkDNS converts the internal DNS configuration of a network to protocolbuffer format
+func ToPBNetworkDNS(network *resources.Network, in *propsv1.NetworkDNS) *pb.NetworkDNS {
+	out := &pb.NetworkDNS{
+		Network: network.Name,
+		Domain:  in.Domain,
+	}
+	names := make([]string, 0, len(in.Records))
+	for k := range in.Records {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		out.Records = append(out.Records, &pb.NetworkDNSRecord{Name: k, Address: in.Records[k]})
+	}
+	names = make([]string, 0, len(in.Aliases))
+	for k := range in.Aliases {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		out.Aliases = append(out.Aliases, &pb.NetworkDNSAlias{Alias: k, Target: in.Aliases[k]})
+	}
+	return out
+}
+
 // ToPBFileList convert a list of file names from api to protocolbuffer FileList format
 func ToPBFileList(fileNames []string, uploadDates []string, fileSizes []int64, fileBuckets [][]string) *pb.FileList {
 	var files []*pb.File
@@ -312,29 +496,83 @@ func ToPBFileList(fileNames []string, uploadDates []string, fileSizes []int64, f
 	return &pb.FileList{Files: files}
 }
 
+// ToPBJob converts a JobRecord to protocolbuffer format
+func ToPBJob(in *JobRecord) *pb.Job {
+	out := &pb.Job{
+		Uuid:      in.UUID,
+		Command:   in.Command,
+		Method:    in.Method,
+		Caller:    in.Caller,
+		Tenant:    in.Tenant,
+		Arguments: in.Arguments,
+		Async:     in.Async,
+		Status:    string(in.Status),
+		Error:     in.Error,
+		Result:    in.Result,
+		StartTime: in.StartTime.Format(time.RFC3339),
+		Duration:  float32(in.Duration().Seconds()),
+	}
+	if !in.EndTime.IsZero() {
+		out.EndTime = in.EndTime.Format(time.RFC3339)
+	}
+	return out
+}
+
+// ToPBAuditEvent converts an audit event to protocolbuffer format
+func ToPBAuditEvent(in *metadata.AuditEvent) *pb.AuditEvent {
+	var params string
+	if len(in.Parameters) > 0 {
+		jsoned, err := json.Marshal(in.Parameters)
+		if err == nil {
+			params = string(jsoned)
+		}
+	}
+	return &pb.AuditEvent{
+		Time:         in.Time.Format(time.RFC3339),
+		JobUuid:      in.JobUUID,
+		Principal:    in.Principal,
+		Tenant:       in.Tenant,
+		Method:       in.Method,
+		ResourceKind: in.ResourceKind,
+		ResourceRef:  in.ResourceRef,
+		Parameters:   params,
+		Outcome:      in.Outcome,
+		Error:        in.Error,
+		Duration:     float32(in.Duration),
+	}
+}
+
 // ToPBHostSizing converts a protobuf HostSizing message to resources.SizingRequirements
 func ToPBHostSizing(src resources.SizingRequirements) *pb.HostSizing {
 	return &pb.HostSizing{
-		MinCpuCount: int32(src.MinCores),
-		MaxCpuCount: int32(src.MaxCores),
-		MinCpuFreq:  src.MinFreq,
-		GpuCount:    int32(src.MinGPU),
-		MinRamSize:  src.MinRAMSize,
-		MaxRamSize:  src.MaxRAMSize,
-		MinDiskSize: int32(src.MinDiskSize),
+		MinCpuCount:  int32(src.MinCores),
+		MaxCpuCount:  int32(src.MaxCores),
+		MinCpuFreq:   src.MinFreq,
+		GpuCount:     int32(src.MinGPU),
+		MinRamSize:   src.MinRAMSize,
+		MaxRamSize:   src.MaxRAMSize,
+		MinDiskSize:  int32(src.MinDiskSize),
+		MinDiskSpeed: src.MinDiskSpeed,
+		MinNetSpeed:  src.MinNetSpeed,
+		Replaceable:  src.Replaceable,
+		Cheapest:     src.Cheapest,
 	}
 }
 
 // FromPBHostSizing converts a protobuf HostSizing message to resources.SizingRequirements
 func FromPBHostSizing(src *pb.HostSizing) resources.SizingRequirements {
 	return resources.SizingRequirements{
-		MinCores:    int(src.MinCpuCount),
-		MaxCores:    int(src.MaxCpuCount),
-		MinFreq:     src.MinCpuFreq,
-		MinGPU:      int(src.GpuCount),
-		MinRAMSize:  src.MinRamSize,
-		MaxRAMSize:  src.MaxRamSize,
-		MinDiskSize: int(src.MinDiskSize),
+		MinCores:     int(src.MinCpuCount),
+		MaxCores:     int(src.MaxCpuCount),
+		MinFreq:      src.MinCpuFreq,
+		MinGPU:       int(src.GpuCount),
+		MinRAMSize:   src.MinRamSize,
+		MaxRAMSize:   src.MaxRamSize,
+		MinDiskSize:  int(src.MinDiskSize),
+		MinDiskSpeed: src.MinDiskSpeed,
+		MinNetSpeed:  src.MinNetSpeed,
+		Replaceable:  src.Replaceable,
+		Cheapest:     src.Cheapest,
 	}
 }
 
@@ -364,6 +602,10 @@ func ClonePBHostSizing(in *pb.HostSizing) *pb.HostSizing {
 		MinDiskSize: in.MinDiskSize,
 		GpuCount: in.GpuCount,
 		MinCpuFreq: in.MinCpuFreq,
+		MinDiskSpeed: in.MinDiskSpeed,
+		MinNetSpeed: in.MinNetSpeed,
+		Replaceable: in.Replaceable,
+		Cheapest: in.Cheapest,
 	}
 }
 
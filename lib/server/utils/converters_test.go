@@ -0,0 +1,32 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTimeRemaining(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, "", formatTimeRemaining(time.Time{}, now))
+	assert.Equal(t, "expired", formatTimeRemaining(now.Add(-time.Minute), now))
+	assert.Equal(t, "71h58m", formatTimeRemaining(now.Add(71*time.Hour+58*time.Minute+30*time.Second), now))
+	assert.Equal(t, "0h05m", formatTimeRemaining(now.Add(5*time.Minute), now))
+}
@@ -18,14 +18,69 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
 	"sync"
 	"time"
 
+	scribble "github.com/nanobox-io/golang-scribble"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/metadata"
+
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+// JobStatus describes the state of a job
+type JobStatus string
+
+const (
+	// JobRunning when the job is being executed
+	JobRunning JobStatus = "running"
+	// JobSucceeded when the job ended without error
+	JobSucceeded JobStatus = "succeeded"
+	// JobFailed when the job ended with an error
+	JobFailed JobStatus = "failed"
+	// JobAborted when the job has been cancelled
+	JobAborted JobStatus = "aborted"
+	// JobInterrupted when safescaled stopped while the job was running
+	JobInterrupted JobStatus = "interrupted"
+)
+
+const (
+	// jobsCollection is the name of the collection storing jobs in the job database
+	jobsCollection = "jobs"
+	// jobPollingInterval is the delay between 2 checks of the running jobs when waiting for them to end
+	jobPollingInterval = 500 * time.Millisecond
 )
 
+// JobRecord contains the persisted information about a job
+type JobRecord struct {
+	UUID      string    `json:"uuid"`
+	Command   string    `json:"command,omitempty"`
+	Method    string    `json:"method,omitempty"`
+	Caller    string    `json:"caller,omitempty"`
+	Tenant    string    `json:"tenant,omitempty"`
+	Arguments string    `json:"arguments,omitempty"`
+	Async     bool      `json:"async,omitempty"`
+	Status    JobStatus `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Result    string    `json:"result,omitempty"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time,omitempty"`
+}
+
+// Duration returns the time spent by the job (up to now if still running)
+func (jr *JobRecord) Duration() time.Duration {
+	if jr.EndTime.IsZero() {
+		return time.Since(jr.StartTime)
+	}
+	return jr.EndTime.Sub(jr.StartTime)
+}
+
 type jobInfo struct {
 	commandName string
 	launchTime  time.Time
@@ -40,24 +95,69 @@ func (ji *jobInfo) toString() string {
 var (
 	jobMap          = map[string]jobInfo{}
 	mutexJobManager sync.Mutex
+
+	jobDB      *scribble.Driver
+	mutexJobDB sync.Mutex
 )
 
-// JobRegister ...
-func JobRegister(ctx context.Context, cancelFunc func(), command string) error {
+// getJobDB returns the database used to persist jobs, creating it if needed
+func getJobDB() (*scribble.Driver, error) {
+	mutexJobDB.Lock()
+	defer mutexJobDB.Unlock()
+
+	if jobDB == nil {
+		err := os.MkdirAll(utils.AbsPathify("$HOME/.safescale/jobs"), 0700)
+		if err != nil {
+			return nil, err
+		}
+		db, err := scribble.New(utils.AbsPathify("$HOME/.safescale/jobs/db"), nil)
+		if err != nil {
+			return nil, err
+		}
+		jobDB = db
+	}
+	return jobDB, nil
+}
+
+// getJobUUID returns the uuid of the job from the grpc metadata of the context
+func getJobUUID(ctx context.Context) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return fmt.Errorf("no uuid in grpc metadata")
+		return "", fmt.Errorf("no uuid in grpc metadata")
+	}
+	uuids := md.Get("uuid")
+	if len(uuids) == 0 || uuids[0] == "" {
+		return "", fmt.Errorf("no uuid in grpc metadata")
+	}
+	return uuids[0], nil
+}
+
+// JobRegister ...
+func JobRegister(ctx context.Context, cancelFunc func(), command string) error {
+	uuid, err := getJobUUID(ctx)
+	if err != nil {
+		return err
 	}
 	mutexJobManager.Lock()
 	defer mutexJobManager.Unlock()
 
-	jobMap[md.Get("uuid")[0]] = jobInfo{
+	jobMap[uuid] = jobInfo{
 		commandName: command,
 		launchTime:  time.Now(),
 		context:     ctx,
 		cancelFunc:  cancelFunc,
 	}
+	metrics.SetActiveJobs(len(jobMap))
 
+	// Keeps the human readable command of the job in the persisted record, if any
+	err = jobUpdate(uuid, func(record *JobRecord) {
+		record.Command = command
+	})
+	if err != nil {
+		if _, ok := err.(scerr.ErrNotFound); !ok {
+			logrus.Warnf("failed to update job '%s' in job store: %v", uuid, err)
+		}
+	}
 	return nil
 }
 
@@ -76,23 +176,235 @@ func JobDeregisterUUID(uuid string) {
 	defer mutexJobManager.Unlock()
 
 	delete(jobMap, uuid)
+	metrics.SetActiveJobs(len(jobMap))
 }
 
 // JobDeregister ...
 func JobDeregister(ctx context.Context) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
+	uuid, err := getJobUUID(ctx)
+	if err != nil {
 		logrus.Errorf("Trying to deregister a job without uuid!")
 	} else {
-		JobDeregisterUUID(md.Get("uuid")[0])
+		JobDeregisterUUID(uuid)
 	}
 }
 
 // JobList ...
 func JobList() map[string]string {
+	mutexJobManager.Lock()
+	defer mutexJobManager.Unlock()
+
 	listMap := map[string]string{}
 	for uuid, info := range jobMap {
 		listMap[uuid] = info.toString()
 	}
 	return listMap
 }
+
+// JobCount returns the number of jobs currently running
+func JobCount() int {
+	mutexJobManager.Lock()
+	defer mutexJobManager.Unlock()
+
+	return len(jobMap)
+}
+
+// JobWaitAll waits until all running jobs have ended or 'timeout' is reached
+// Returns true if all jobs have ended
+func JobWaitAll(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for JobCount() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(jobPollingInterval)
+	}
+	return true
+}
+
+// JobCancelAll cancels all running jobs, and returns the number of jobs cancelled
+func JobCancelAll() int {
+	mutexJobManager.Lock()
+	defer mutexJobManager.Unlock()
+
+	for uuid, info := range jobMap {
+		logrus.Warnf("Cancelling job '%s' (%s)", uuid, info.commandName)
+		info.cancelFunc()
+	}
+	return len(jobMap)
+}
+
+// JobStart records in the job store the start of the job identified by 'uuid'
+func JobStart(record *JobRecord) error {
+	if record == nil {
+		return scerr.InvalidParameterError("record", "cannot be nil")
+	}
+	if record.UUID == "" {
+		return scerr.InvalidParameterError("record.UUID", "cannot be empty string")
+	}
+
+	db, err := getJobDB()
+	if err != nil {
+		return err
+	}
+	record.Status = JobRunning
+	if record.StartTime.IsZero() {
+		record.StartTime = time.Now()
+	}
+	return db.Write(jobsCollection, record.UUID, record)
+}
+
+// JobEnd records in the job store the end of the job identified by 'uuid'
+// 'result' contains a reference to the result of the job (usually the ID of the resource created)
+func JobEnd(uuid string, result string, jobErr error) error {
+	if uuid == "" {
+		return scerr.InvalidParameterError("uuid", "cannot be empty string")
+	}
+
+	return jobUpdate(uuid, func(record *JobRecord) {
+		record.EndTime = time.Now()
+		record.Result = result
+		switch {
+		case jobErr == nil:
+			record.Status = JobSucceeded
+		case jobErr == context.Canceled || scerr.Cause(jobErr) == context.Canceled:
+			record.Status = JobAborted
+			record.Error = jobErr.Error()
+		default:
+			record.Status = JobFailed
+			record.Error = jobErr.Error()
+		}
+	})
+}
+
+// jobUpdate reads the job record, applies 'updater' on it then writes the record back in the job store
+func jobUpdate(uuid string, updater func(*JobRecord)) error {
+	db, err := getJobDB()
+	if err != nil {
+		return err
+	}
+
+	mutexJobDB.Lock()
+	defer mutexJobDB.Unlock()
+
+	record := JobRecord{}
+	err = db.Read(jobsCollection, uuid, &record)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return scerr.NotFoundError(fmt.Sprintf("job '%s' not found", uuid))
+		}
+		return err
+	}
+	updater(&record)
+	return db.Write(jobsCollection, uuid, &record)
+}
+
+// JobInspect returns the record of the job identified by 'uuid'
+func JobInspect(uuid string) (*JobRecord, error) {
+	if uuid == "" {
+		return nil, scerr.InvalidParameterError("uuid", "cannot be empty string")
+	}
+
+	db, err := getJobDB()
+	if err != nil {
+		return nil, err
+	}
+	record := JobRecord{}
+	err = db.Read(jobsCollection, uuid, &record)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, scerr.NotFoundError(fmt.Sprintf("job '%s' not found", uuid))
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// JobHistory returns the records of the jobs started after 'since', the most recent first
+// If limit > 0, returns at most 'limit' records
+func JobHistory(since time.Time, limit int) ([]*JobRecord, error) {
+	db, err := getJobDB()
+	if err != nil {
+		return nil, err
+	}
+	list, err := db.ReadAll(jobsCollection)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*JobRecord{}, nil
+		}
+		return nil, err
+	}
+
+	var records []*JobRecord
+	for _, item := range list {
+		record := JobRecord{}
+		err = json.Unmarshal([]byte(item), &record)
+		if err != nil {
+			logrus.Warnf("ignoring invalid job record: %v", err)
+			continue
+		}
+		if !since.IsZero() && record.StartTime.Before(since) {
+			continue
+		}
+		records = append(records, &record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].StartTime.After(records[j].StartTime)
+	})
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+// JobPrune removes from the job store the records of the jobs ended before 'before', and returns the number
+// of records removed; records of running jobs are kept
+func JobPrune(before time.Time) (int, error) {
+	records, err := JobHistory(time.Time{}, 0)
+	if err != nil {
+		return 0, err
+	}
+	db, err := getJobDB()
+	if err != nil {
+		return 0, err
+	}
+
+	mutexJobDB.Lock()
+	defer mutexJobDB.Unlock()
+
+	count := 0
+	for _, r := range records {
+		if r.Status == JobRunning || r.EndTime.IsZero() || !r.EndTime.Before(before) {
+			continue
+		}
+		err = db.Delete(jobsCollection, r.UUID)
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// JobRecoverInterrupted marks as interrupted the jobs recorded as running in the job store;
+// meant to be called at safescaled startup, when no job can be running yet
+func JobRecoverInterrupted() error {
+	records, err := JobHistory(time.Time{}, 0)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		if r.Status != JobRunning {
+			continue
+		}
+		err = jobUpdate(r.UUID, func(record *JobRecord) {
+			record.Status = JobInterrupted
+			record.EndTime = time.Now()
+			record.Error = "safescaled stopped while the job was running"
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestJobStore(t *testing.T) {
+	home, err := ioutil.TempDir("", "jobstore")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(home)
+	}()
+	oldHome := os.Getenv("HOME")
+	_ = os.Setenv("HOME", home)
+	defer func() {
+		_ = os.Setenv("HOME", oldHome)
+	}()
+	jobDB = nil
+
+	err = JobStart(&JobRecord{UUID: "job-1", Method: "/HostService/Create", Tenant: "test"})
+	require.Nil(t, err)
+	err = JobStart(&JobRecord{UUID: "job-2", Method: "/HostService/Delete", Tenant: "test", StartTime: time.Now().Add(time.Minute)})
+	require.Nil(t, err)
+
+	record, err := JobInspect("job-1")
+	require.Nil(t, err)
+	assert.Equal(t, JobRunning, record.Status)
+
+	err = JobEnd("job-1", "host-id", nil)
+	require.Nil(t, err)
+	record, err = JobInspect("job-1")
+	require.Nil(t, err)
+	assert.Equal(t, JobSucceeded, record.Status)
+	assert.Equal(t, "host-id", record.Result)
+	assert.False(t, record.EndTime.IsZero())
+
+	err = JobEnd("job-2", "", fmt.Errorf("boom"))
+	require.Nil(t, err)
+	record, err = JobInspect("job-2")
+	require.Nil(t, err)
+	assert.Equal(t, JobFailed, record.Status)
+	assert.Equal(t, "boom", record.Error)
+
+	history, err := JobHistory(time.Time{}, 0)
+	require.Nil(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "job-2", history[0].UUID)
+
+	history, err = JobHistory(time.Now().Add(30*time.Second), 0)
+	require.Nil(t, err)
+	require.Len(t, history, 1)
+
+	history, err = JobHistory(time.Time{}, 1)
+	require.Nil(t, err)
+	assert.Len(t, history, 1)
+
+	_, err = JobInspect("job-3")
+	assert.NotNil(t, err)
+}
+
+func TestJobRecoverInterrupted(t *testing.T) {
+	home, err := ioutil.TempDir("", "jobstore")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(home)
+	}()
+	oldHome := os.Getenv("HOME")
+	_ = os.Setenv("HOME", home)
+	defer func() {
+		_ = os.Setenv("HOME", oldHome)
+	}()
+	jobDB = nil
+
+	err = JobStart(&JobRecord{UUID: "job-running"})
+	require.Nil(t, err)
+
+	err = JobRecoverInterrupted()
+	require.Nil(t, err)
+
+	record, err := JobInspect("job-running")
+	require.Nil(t, err)
+	assert.Equal(t, JobInterrupted, record.Status)
+}
+
+func TestJobPrune(t *testing.T) {
+	home, err := ioutil.TempDir("", "jobstore")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(home)
+	}()
+	oldHome := os.Getenv("HOME")
+	_ = os.Setenv("HOME", home)
+	defer func() {
+		_ = os.Setenv("HOME", oldHome)
+	}()
+	jobDB = nil
+
+	err = JobStart(&JobRecord{UUID: "job-old", StartTime: time.Now().Add(-48 * time.Hour)})
+	require.Nil(t, err)
+	err = jobUpdate("job-old", func(record *JobRecord) {
+		record.Status = JobSucceeded
+		record.EndTime = time.Now().Add(-47 * time.Hour)
+	})
+	require.Nil(t, err)
+	err = JobStart(&JobRecord{UUID: "job-recent"})
+	require.Nil(t, err)
+	err = JobEnd("job-recent", "", nil)
+	require.Nil(t, err)
+	err = JobStart(&JobRecord{UUID: "job-running", StartTime: time.Now().Add(-48 * time.Hour)})
+	require.Nil(t, err)
+
+	count, err := JobPrune(time.Now().Add(-24 * time.Hour))
+	require.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	_, err = JobInspect("job-old")
+	assert.NotNil(t, err)
+	_, err = JobInspect("job-recent")
+	assert.Nil(t, err)
+	_, err = JobInspect("job-running")
+	assert.Nil(t, err)
+}
+
+func TestJobCancelAll(t *testing.T) {
+	home, err := ioutil.TempDir("", "jobstore")
+	require.Nil(t, err)
+	defer func() {
+		_ = os.RemoveAll(home)
+	}()
+	oldHome := os.Getenv("HOME")
+	_ = os.Setenv("HOME", home)
+	defer func() {
+		_ = os.Setenv("HOME", oldHome)
+	}()
+	jobDB = nil
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("uuid", "job-draining"))
+	ctx, cancelFunc := context.WithCancel(ctx)
+	err = JobRegister(ctx, cancelFunc, "test draining")
+	require.Nil(t, err)
+	assert.Equal(t, 1, JobCount())
+	assert.False(t, JobWaitAll(100*time.Millisecond))
+
+	// Simulates a handler ending when its context is cancelled
+	go func() {
+		<-ctx.Done()
+		JobDeregister(ctx)
+	}()
+	assert.Equal(t, 1, JobCancelAll())
+	assert.True(t, JobWaitAll(5*time.Second))
+	assert.Equal(t, 0, JobCount())
+}
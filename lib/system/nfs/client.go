@@ -46,12 +46,28 @@ func (c *Client) Install() error {
 	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to install NFS client")
 }
 
+// EnableKerberos configures the NFS client to mount shares exported with Kerberos security
+func (c *Client) EnableKerberos() error {
+	data := map[string]interface{}{
+		"Server": false,
+	}
+	retcode, stdout, stderr, err := executeScript(*c.SSHConfig, "nfs_krb5_setup.sh", data)
+	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to enable Kerberos on NFS client")
+}
+
 // Mount defines a mount of a remote share and mount it
 func (c *Client) Mount(export string, mountPoint string, withCache bool) error {
+	return c.MountWithOptions(export, mountPoint, withCache, "")
+}
+
+// MountWithOptions defines a mount of a remote share with additional mount options (ie. "vers=4.2,sec=krb5")
+// and mount it
+func (c *Client) MountWithOptions(export string, mountPoint string, withCache bool, options string) error {
 	data := map[string]interface{}{
 		"Export":      export,
 		"MountPoint":  mountPoint,
 		"cacheOption": map[bool]string{true: "ac", false: "noac"}[withCache],
+		"Options":     options,
 	}
 	retcode, stdout, stderr, err := executeScript(*c.SSHConfig, "nfs_client_share_mount.sh", data)
 	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to mount remote NFS share")
@@ -18,9 +18,11 @@ package nfs
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	rice "github.com/GeertJohan/go.rice"
 	"github.com/sirupsen/logrus"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -56,6 +58,12 @@ func getTemplateBox() (*rice.Box, error) {
 // Returns retcode, stdout, stderr, error
 // If error == nil && retcode != 0, the script ran but failed.
 func executeScript(sshconfig system.SSHConfig, name string, data map[string]interface{}) (int, string, string, error) {
+	return executeScriptWithInput(sshconfig, name, data, "")
+}
+
+// executeScriptWithInput is like executeScript, but writes input on the standard input of the script; secrets
+// given this way appear neither in the script copied on the host nor in its trace
+func executeScriptWithInput(sshconfig system.SSHConfig, name string, data map[string]interface{}, input string) (int, string, string, error) {
 	bashLibrary, err := system.GetBashLibrary()
 	if err != nil {
 		return 255, "", "", err
@@ -163,7 +171,7 @@ func executeScript(sshconfig system.SSHConfig, name string, data map[string]inte
 			stderr = ""
 			retcode = 0
 
-			sshCmd, err := sshconfig.SudoCommand(cmd, false)
+			sshCmd, err := newScriptCommand(sshconfig, cmd, filename, input)
 			if err != nil {
 				return err
 			}
@@ -230,3 +238,31 @@ func handleExecuteScriptReturn(retcode int, stdout string, stderr string, err er
 	}
 	return nil
 }
+
+// newScriptCommand returns the command running the script copied in filename on the remote host; without input,
+// cmd is written on the standard input of ssh, else the script is given to ssh as the remote command and reads input
+func newScriptCommand(sshconfig system.SSHConfig, cmd, filename, input string) (*system.SSHCommand, error) {
+	if input == "" {
+		return sshconfig.SudoCommand(cmd, false)
+	}
+
+	sshCmd, err := sshconfig.StreamCommandContext(context.Background(), "sudo bash "+filename)
+	if err != nil {
+		return nil, err
+	}
+	stdin, err := sshCmd.StdinPipe()
+	if err != nil {
+		_ = sshCmd.Close()
+		return nil, err
+	}
+	// The pipe buffers the input until the command starts
+	_, err = io.WriteString(stdin, input)
+	if cerr := stdin.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = sshCmd.Close()
+		return nil, err
+	}
+	return sshCmd, nil
+}
@@ -0,0 +1,107 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+# block_device_encrypted_mount.sh
+#
+# Sets up LUKS on a block device (if not already done), then unlocks and mounts it through a systemd unit,
+# so the device is unlocked again at each boot; prints the LUKS UUID of the device
+
+{{.BashHeader}}
+
+function print_error {
+    read line file <<<$(caller)
+    echo "An error occurred in line $line of file $file:" "{"`sed "${line}q;d" "$file"`"}" >&2
+}
+trap print_error ERR
+
+# The key of the device is read on the standard input, before anything else may consume it, into a file readable
+# only by root; it never appears in this script nor in its trace
+KEY_INPUT=$(mktemp)
+trap 'rm -f "${KEY_INPUT}"' EXIT
+cat >"${KEY_INPUT}"
+[ -s "${KEY_INPUT}" ] || { echo "No key given on standard input" >&2; exit 192; }
+
+{{.reserved_BashLibrary}}
+
+if ! which cryptsetup &>/dev/null; then
+    case $LINUX_KIND in
+        debian|ubuntu)
+            sfApt update >/dev/null
+            sfApt install -qqy cryptsetup >/dev/null || exit 192
+            ;;
+        rhel|centos|fedora)
+            yum install -q -y cryptsetup >/dev/null || exit 192
+            ;;
+        *)
+            echo "Unsupported operating system '$LINUX_KIND'" >&2
+            exit 192
+            ;;
+    esac
+fi
+CRYPTSETUP=$(which cryptsetup)
+
+FORMAT={{ if .DoNotFormat }}0{{ else }}1{{ end }}
+
+# A device already encrypted (volume attached again) keeps its LUKS header, and so its data; a device that must not
+# be formatted but has no LUKS header holds data that luksFormat would destroy
+if ! ${CRYPTSETUP} isLuks "{{.Device}}"; then
+    if [ ${FORMAT} -eq 0 ]; then
+        echo "Device '{{.Device}}' is not encrypted with LUKS and must not be formatted" >&2
+        exit 193
+    fi
+    ${CRYPTSETUP} luksFormat --batch-mode --key-file="${KEY_INPUT}" "{{.Device}}" >/dev/null || exit 193
+    FORMAT=1
+fi
+LUKS_UUID=$(${CRYPTSETUP} luksUUID "{{.Device}}")
+MAPPER=sf-${LUKS_UUID}
+
+# The key is kept in a file readable only by root, used to unlock the device at boot
+KEY_DIR=/etc/safescale/luks
+KEY_FILE=${KEY_DIR}/${LUKS_UUID}.key
+mkdir -p ${KEY_DIR}
+chmod 0700 ${KEY_DIR}
+install -m 0400 "${KEY_INPUT}" ${KEY_FILE}
+
+${CRYPTSETUP} status ${MAPPER} &>/dev/null || ${CRYPTSETUP} open --key-file ${KEY_FILE} "{{.Device}}" ${MAPPER} || exit 194
+if [ ${FORMAT} -eq 1 ]; then
+    mkfs -F -t {{.FileSystem}} /dev/mapper/${MAPPER} >/dev/null
+fi
+mkdir -p "{{.MountPoint}}"
+
+UNIT=safescale-luks-${LUKS_UUID}
+cat >/etc/systemd/system/${UNIT}.service <<-EOF
+[Unit]
+Description=Unlocks and mounts encrypted volume ${LUKS_UUID} in {{.MountPoint}}
+After=local-fs.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/bin/sh -c '${CRYPTSETUP} status ${MAPPER} >/dev/null || ${CRYPTSETUP} open --key-file ${KEY_FILE} /dev/disk/by-uuid/${LUKS_UUID} ${MAPPER}'
+ExecStart=/bin/mount -t {{.FileSystem}} /dev/mapper/${MAPPER} {{.MountPoint}}
+ExecStop=/bin/sh -c 'umount -f {{.MountPoint}}; ${CRYPTSETUP} close ${MAPPER}'
+
+[Install]
+WantedBy=multi-user.target
+EOF
+systemctl daemon-reload
+systemctl enable ${UNIT} &>/dev/null
+systemctl start ${UNIT} || exit 195
+
+chmod a+rwx "{{.MountPoint}}" >/dev/null && \
+echo -n ${LUKS_UUID} && exit 0
+
+exit 1
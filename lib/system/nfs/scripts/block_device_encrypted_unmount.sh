@@ -0,0 +1,34 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+# block_device_encrypted_unmount.sh
+#
+# Unmounts and locks an encrypted block device, then removes its unit and its key from the host
+
+{{.BashHeader}}
+
+function print_error {
+    read line file <<<$(caller)
+    echo "An error occurred in line $line of file $file:" "{"`sed "${line}q;d" "$file"`"}" >&2
+}
+trap print_error ERR
+
+UNIT=safescale-luks-{{.UUID}}
+
+systemctl disable ${UNIT} &>/dev/null
+systemctl stop ${UNIT} || exit 192
+rm -f /etc/systemd/system/${UNIT}.service /etc/safescale/luks/{{.UUID}}.key
+systemctl daemon-reload
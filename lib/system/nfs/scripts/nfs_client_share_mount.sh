@@ -27,6 +27,6 @@ function print_error {
 trap print_error ERR
 
 mkdir -p "{{.MountPoint}}"
-echo mount.nfs -o {{ .cacheOption }} "{{.Export}}" "{{.MountPoint}}" > /opt/safescale/var/tmp/moco.txt
-mount.nfs -o {{ .cacheOption }} "{{.Export}}" "{{.MountPoint}}"
-echo "{{.Export}} {{.MountPoint}}   nfs defaults,user,auto,noatime,intr,{{ .cacheOption }} 0   0" >> /etc/fstab
+echo mount.nfs -o {{ .cacheOption }}{{ if .Options }},{{ .Options }}{{ end }} "{{.Export}}" "{{.MountPoint}}" > /opt/safescale/var/tmp/moco.txt
+mount.nfs -o {{ .cacheOption }}{{ if .Options }},{{ .Options }}{{ end }} "{{.Export}}" "{{.MountPoint}}"
+echo "{{.Export}} {{.MountPoint}}   nfs defaults,user,auto,noatime,intr,{{ .cacheOption }}{{ if .Options }},{{ .Options }}{{ end }} 0   0" >> /etc/fstab
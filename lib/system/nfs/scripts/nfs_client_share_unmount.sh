@@ -26,5 +26,7 @@ function print_error {
 }
 trap print_error ERR
 
-umount -fl {{.Export}}
-sed -i '\#^{{.Export}}#d' /etc/fstab
+umount -fl "{{.Export}}"
+# The brackets of an IPv6 server address must not be taken as a character class
+EXPORT_RE=$(echo "{{.Export}}" | sed 's/[][\.*^$]/\\&/g')
+sed -i "\#^${EXPORT_RE} #d" /etc/fstab
@@ -0,0 +1,91 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+# nfs_krb5_setup.sh
+#
+# Enables the Kerberos security of NFSv4 on a server or a client; the host must already be part of a Kerberos realm,
+# with its principals in /etc/krb5.keytab (nfs/<fqdn> for a server, host/<fqdn> or nfs/<fqdn> for a client)
+
+{{.BashHeader}}
+
+function print_error {
+    read line file <<<$(caller)
+    echo "An error occurred in line $line of file $file:" "{"`sed "${line}q;d" "$file"`"}" >&2
+}
+trap print_error ERR
+
+{{.reserved_BashLibrary}}
+
+echo "Enable Kerberos for NFS {{ if .Server }}server{{ else }}client{{ end }}"
+
+case $LINUX_KIND in
+    debian|ubuntu)
+        export DEBIAN_FRONTEND=noninteractive
+        sfApt update
+        sfApt install -qqy krb5-user
+        ;;
+
+    rhel|centos)
+        yum install -y krb5-workstation gssproxy
+        ;;
+
+    *)
+        echo "Unsupported operating system '$LINUX_KIND'"
+        exit 1
+        ;;
+esac
+
+if [ ! -f /etc/krb5.keytab ]; then
+    echo "No Kerberos keytab found in /etc/krb5.keytab, the host must be joined to a Kerberos realm first" >&2
+    exit 192
+fi
+{{- if .Server }}
+if ! klist -k /etc/krb5.keytab | grep -q " nfs/"; then
+    echo "No 'nfs/' principal found in /etc/krb5.keytab, it is needed to export NFS shares with Kerberos" >&2
+    exit 192
+fi
+{{- else }}
+if ! klist -k /etc/krb5.keytab | grep -q " \(host\|nfs\)/"; then
+    echo "No 'host/' or 'nfs/' principal found in /etc/krb5.keytab, one is needed to mount NFS shares with Kerberos" >&2
+    exit 192
+fi
+{{- end }}
+
+case $LINUX_KIND in
+    debian|ubuntu)
+{{- if .Server }}
+        sed -i '/^NEED_SVCGSSD=/d' /etc/default/nfs-kernel-server
+        echo 'NEED_SVCGSSD="yes"' >>/etc/default/nfs-kernel-server
+        systemctl restart nfs-kernel-server
+{{- else }}
+        sed -i '/^NEED_GSSD=/d' /etc/default/nfs-common
+        echo 'NEED_GSSD="yes"' >>/etc/default/nfs-common
+        systemctl restart rpc-gssd || systemctl restart nfs-client.target
+{{- end }}
+        ;;
+
+    rhel|centos)
+{{- if .Server }}
+        systemctl enable gssproxy
+        systemctl restart gssproxy
+        systemctl restart nfs-server
+{{- else }}
+        systemctl enable rpc-gssd
+        systemctl restart rpc-gssd
+{{- end }}
+        ;;
+esac
+exit 0
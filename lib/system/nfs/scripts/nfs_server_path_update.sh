@@ -0,0 +1,47 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+# nfs_server_path_update.sh
+#
+# Replaces in place the access rights of a path already exported, keeping its FSID so the clients
+# still allowed keep their mounts, then re-exports
+
+{{.BashHeader}}
+
+function print_error {
+    read line file <<<$(caller)
+    echo "An error occurred in line $line of file $file:" "{"`sed "${line}q;d" "$file"`"}" >&2
+}
+trap print_error ERR
+
+LINE=$(grep "^{{.Path}} " /etc/exports || true)
+if [ -z "$LINE" ]; then
+    echo "Path '{{.Path}}' is not exported" >&2
+    exit 192
+fi
+
+# Keeps the FSID of the export
+FSID=$(echo "$LINE" | sed -r 's/ /\n/g' | sed -r 's/,/\n/g' | grep fsid= | grep -o [0-9]* | head -n 1)
+ACCESS_RIGHTS="{{.AccessRights}}"
+if [ ! -z "$FSID" ]; then
+    ACCESS_RIGHTS=$(echo "$ACCESS_RIGHTS" | sed -r "s/fsid=[[:digit:]]*,?//g" | sed -r "s/\)/,fsid=$FSID)/g")
+fi
+
+sed -i '\#^{{.Path}} #d' /etc/exports
+echo "{{.Path}} $ACCESS_RIGHTS" >>/etc/exports
+
+# Synchronizes the exports with /etc/exports; unlike an unexport, clients still allowed are not disturbed
+exportfs -ra
@@ -0,0 +1,79 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+# raid_device_mount.sh
+#
+# Assembles block devices in a software RAID array with mdadm (creating the array if the devices are not
+# members of one yet), then mounts it; prints the UUID of the filesystem of the array
+
+{{.BashHeader}}
+
+function print_error {
+    read line file <<<$(caller)
+    echo "An error occurred in line $line of file $file:" "{"`sed "${line}q;d" "$file"`"}" >&2
+}
+trap print_error ERR
+
+{{.reserved_BashLibrary}}
+
+if ! which mdadm &>/dev/null; then
+    case $LINUX_KIND in
+        debian|ubuntu)
+            sfApt update >/dev/null
+            sfApt install -qqy mdadm >/dev/null || exit 192
+            ;;
+        rhel|centos|fedora)
+            yum install -q -y mdadm >/dev/null || exit 192
+            ;;
+        *)
+            echo "Unsupported operating system '$LINUX_KIND'" >&2
+            exit 192
+            ;;
+    esac
+fi
+MDADM_CONF=/etc/mdadm.conf
+[ -d /etc/mdadm ] && MDADM_CONF=/etc/mdadm/mdadm.conf
+
+ARRAY=/dev/md/{{.Name}}
+DEVICES="{{ range .Devices }} {{ . }}{{ end }}"
+FORMAT={{ if .DoNotFormat }}0{{ else }}1{{ end }}
+
+# Devices already assembled once (volume attached again) keep their superblock, and so their data
+if mdadm --examine {{ index .Devices 0 }} &>/dev/null; then
+    mdadm --assemble ${ARRAY} ${DEVICES} >/dev/null || exit 193
+else
+    mdadm --create ${ARRAY} --run --level={{.Level}} --raid-devices={{ len .Devices }} --metadata=1.2 --name={{.Name}} ${DEVICES} >/dev/null 2>&1 || exit 193
+    FORMAT=1
+fi
+
+# Declares the array so it is assembled with the same name at boot
+sed -i '\:name=[^ ]*{{.Name}}\b:d' ${MDADM_CONF} 2>/dev/null || true
+mdadm --detail --brief ${ARRAY} >>${MDADM_CONF}
+
+if [ ${FORMAT} -eq 1 ]; then
+    mkfs -F -t {{.FileSystem}} ${ARRAY} >/dev/null
+fi
+
+UUID=""
+eval $(blkid -o export $(readlink -f ${ARRAY}) | grep ^UUID=)
+
+echo "/dev/disk/by-uuid/$UUID {{.MountPoint}} {{.FileSystem}} defaults,nofail 0 2" >>/etc/fstab && \
+mkdir -p "{{.MountPoint}}" >/dev/null && \
+mount {{.MountPoint}} >/dev/null && \
+chmod a+rwx "{{.MountPoint}}" >/dev/null && \
+echo -n $UUID && exit 0
+
+exit 1
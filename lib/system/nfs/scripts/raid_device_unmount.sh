@@ -0,0 +1,37 @@
+#!/usr/bin/env bash
+#
+# Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+#
+# raid_device_unmount.sh
+#
+# Unmounts a software RAID array and stops it, keeping the superblocks of its devices to be able to assemble
+# it again on any host
+
+{{.BashHeader}}
+
+function print_error {
+    read line file <<<$(caller)
+    echo "An error occurred in line $line of file $file:" "{"`sed "${line}q;d" "$file"`"}" >&2
+}
+trap print_error ERR
+
+umount -l -f "/dev/disk/by-uuid/{{.UUID}}" && \
+sed -i '\:{{.UUID}}:d' /etc/fstab || exit 192
+
+mdadm --stop /dev/md/{{.Name}} || exit 193
+for conf in /etc/mdadm.conf /etc/mdadm/mdadm.conf; do
+    [ -f ${conf} ] && sed -i '\:name=[^ ]*{{.Name}}\b:d' ${conf}
+done
+exit 0
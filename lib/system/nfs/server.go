@@ -17,7 +17,9 @@
 package nfs
 
 import (
+	"context"
 	"fmt"
+	"io"
 
 	"github.com/CS-SI/SafeScale/lib/system"
 	"github.com/CS-SI/SafeScale/lib/system/nfs/enums/securityflavor"
@@ -52,15 +54,19 @@ func (s *Server) Install() error {
 	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to install nfs server")
 }
 
-// AddShare configures a local path to be exported by NFS
-func (s *Server) AddShare(path string, secutityModes []string, readOnly, rootSquash, secure, async, noHide, crossMount, subtreeCheck bool) error {
-	share, err := NewShare(s, path)
-	if err != nil {
-		return fmt.Errorf("failed to create the share : %s", err.Error())
+// EnableKerberos configures the NFS service to export shares with Kerberos security
+func (s *Server) EnableKerberos() error {
+	data := map[string]interface{}{
+		"Server": true,
 	}
+	retcode, stdout, stderr, err := executeScript(*s.SSHConfig, "nfs_krb5_setup.sh", data)
+	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to enable Kerberos on nfs server")
+}
 
+// NewExportACL creates an ExportACL giving access to host (cf. exports man page) with the security modes and options given
+func NewExportACL(host string, secutityModes []string, readOnly, rootSquash, secure, async, noHide, crossMount, subtreeCheck bool) (ExportACL, error) {
 	acl := ExportACL{
-		Host:          "*",
+		Host:          host,
 		SecurityModes: []securityflavor.Enum{},
 		Options: ExportOptions{
 			ReadOnly:       readOnly,
@@ -87,15 +93,46 @@ func (s *Server) AddShare(path string, secutityModes []string, readOnly, rootSqu
 		case "krb5p":
 			acl.SecurityModes = append(acl.SecurityModes, securityflavor.Krb5p)
 		default:
-			return fmt.Errorf("cannot add the share, %s is not a valid security mode", securityMode)
+			return acl, fmt.Errorf("%s is not a valid security mode", securityMode)
 		}
 	}
+	return acl, nil
+}
 
-	share.AddACL(acl)
+// AddShare configures a local path to be exported by NFS to every host
+func (s *Server) AddShare(path string, secutityModes []string, readOnly, rootSquash, secure, async, noHide, crossMount, subtreeCheck bool) error {
+	acl, err := NewExportACL("*", secutityModes, readOnly, rootSquash, secure, async, noHide, crossMount, subtreeCheck)
+	if err != nil {
+		return fmt.Errorf("cannot add the share, %s", err.Error())
+	}
+	return s.AddShareWithACLs(path, []ExportACL{acl})
+}
 
+// AddShareWithACLs configures a local path to be exported by NFS to the hosts of the ACLs
+func (s *Server) AddShareWithACLs(path string, acls []ExportACL) error {
+	share, err := NewShare(s, path)
+	if err != nil {
+		return fmt.Errorf("failed to create the share : %s", err.Error())
+	}
+	for _, acl := range acls {
+		share.AddACL(acl)
+	}
 	return share.Add()
 }
 
+// UpdateShare replaces the ACLs of a local path already exported by NFS, and re-exports it
+// without disturbing the clients keeping their access
+func (s *Server) UpdateShare(path string, acls []ExportACL) error {
+	share, err := NewShare(s, path)
+	if err != nil {
+		return fmt.Errorf("failed to update the share : %s", err.Error())
+	}
+	for _, acl := range acls {
+		share.AddACL(acl)
+	}
+	return share.Update()
+}
+
 // RemoveShare stops export of a local mount point by NFS on the remote server
 func (s *Server) RemoveShare(path string) error {
 	data := map[string]interface{}{
@@ -127,6 +164,146 @@ func (s *Server) UnmountBlockDevice(volumeUUID string) error {
 	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to umount block device")
 }
 
+// MountEncryptedBlockDevice sets up LUKS on a local block device with the key given (if not already done), then
+// unlocks and mounts it through a systemd unit unlocking it at each boot; returns the LUKS UUID of the device
+func (s *Server) MountEncryptedBlockDevice(deviceName, mountPoint, format string, doNotFormat bool, key string) (string, error) {
+	data := map[string]interface{}{
+		"Device":      deviceName,
+		"MountPoint":  mountPoint,
+		"FileSystem":  format,
+		"DoNotFormat": doNotFormat,
+	}
+	// The key is given on the standard input of the script, to never be written in it
+	retcode, stdout, stderr, err := executeScriptWithInput(*s.SSHConfig, "block_device_encrypted_mount.sh", data, key)
+	err = handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to mount encrypted block device")
+	return stdout, err
+}
+
+// UnmountEncryptedBlockDevice unmounts and locks a LUKS device on the remote system, removing its key from the host
+func (s *Server) UnmountEncryptedBlockDevice(luksUUID string) error {
+	data := map[string]interface{}{
+		"UUID": luksUUID,
+	}
+	retcode, stdout, stderr, err := executeScript(*s.SSHConfig, "block_device_encrypted_unmount.sh", data)
+	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to umount encrypted block device")
+}
+
+// MountRAIDDevice assembles block devices in a software RAID array named name (creating it if the devices are not
+// members of an array yet), then mounts it; returns the UUID of the filesystem of the array
+func (s *Server) MountRAIDDevice(name, level string, devices []string, mountPoint, format string, doNotFormat bool) (string, error) {
+	if len(devices) == 0 {
+		return "", scerr.InvalidParameterError("devices", "cannot be empty")
+	}
+	data := map[string]interface{}{
+		"Name":        name,
+		"Level":       level,
+		"Devices":     devices,
+		"MountPoint":  mountPoint,
+		"FileSystem":  format,
+		"DoNotFormat": doNotFormat,
+	}
+	retcode, stdout, stderr, err := executeScript(*s.SSHConfig, "raid_device_mount.sh", data)
+	err = handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to mount RAID device")
+	return stdout, err
+}
+
+// UnmountRAIDDevice unmounts and stops a software RAID array on the remote system
+func (s *Server) UnmountRAIDDevice(name, uuid string) error {
+	data := map[string]interface{}{
+		"Name": name,
+		"UUID": uuid,
+	}
+	retcode, stdout, stderr, err := executeScript(*s.SSHConfig, "raid_device_unmount.sh", data)
+	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to umount RAID device")
+}
+
+// GetRAIDDeviceDetail returns the details of a software RAID array, as displayed by 'mdadm --detail'
+func (s *Server) GetRAIDDeviceDetail(name string) (string, error) {
+	data := map[string]interface{}{
+		"Name": name,
+	}
+	retcode, stdout, stderr, err := executeScript(*s.SSHConfig, "raid_device_detail.sh", data)
+	err = handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to get details of RAID device")
+	return stdout, err
+}
+
+// RemountBlockDevice remounts read-only, or read-write again, the file system mounted on mountPoint
+func (s *Server) RemountBlockDevice(mountPoint string, readOnly bool) error {
+	data := map[string]interface{}{
+		"MountPoint": mountPoint,
+		"ReadOnly":   readOnly,
+	}
+	retcode, stdout, stderr, err := executeScript(*s.SSHConfig, "block_device_remount.sh", data)
+	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to remount block device")
+}
+
+// CopyFromHost copies the content of sourcePath on the host reached with source into targetPath on the remote
+// host; the data are streamed with tar through the SSH connections of the caller, so the hosts neither reach each
+// other nor get the keys of each other
+func (s *Server) CopyFromHost(ctx context.Context, source *system.SSHConfig, sourcePath, targetPath string) (err error) {
+	if source == nil {
+		return scerr.InvalidParameterError("source", "cannot be nil")
+	}
+	reader, err := source.StreamCommandContext(ctx, fmt.Sprintf("sudo tar -C \"%s\" --numeric-owner -cpf - .", sourcePath))
+	if err != nil {
+		return err
+	}
+	// Wait releases the tunnels and the key file of a started command, the ones not started are closed here
+	readerStarted, writerStarted := false, false
+	defer func() {
+		if !readerStarted {
+			_ = reader.Close()
+		}
+	}()
+	writer, err := s.SSHConfig.StreamCommandContext(ctx, fmt.Sprintf("sudo mkdir -p \"%s\" && sudo tar -C \"%s\" --numeric-owner -xpf -", targetPath, targetPath))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if !writerStarted {
+			_ = writer.Close()
+		}
+	}()
+	in, err := reader.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	out, err := writer.StdinPipe()
+	if err != nil {
+		return err
+	}
+	err = writer.Start()
+	if err != nil {
+		return err
+	}
+	writerStarted = true
+	err = reader.Start()
+	if err != nil {
+		_ = out.Close()
+		_ = writer.Wait()
+		return err
+	}
+	readerStarted = true
+	_, err = io.Copy(out, in)
+	if err != nil {
+		// The reader blocks on its output if it is not consumed anymore
+		_ = reader.Kill()
+	}
+	_ = out.Close()
+	rerr := reader.Wait()
+	werr := writer.Wait()
+	if err != nil {
+		return scerr.Wrap(err, "failed to copy data from host")
+	}
+	if rerr != nil {
+		return scerr.Wrap(rerr, fmt.Sprintf("failed to read '%s' on source host", sourcePath))
+	}
+	if werr != nil {
+		return scerr.Wrap(werr, fmt.Sprintf("failed to write '%s' on target host", targetPath))
+	}
+	return nil
+}
+
 // MountVGDevice mounts a LVM Virtual Group in the remote system
 func (s *Server) MountVGDevice(device, name, format string, doNotFormat bool, drives []string) (string, error) {
 	data := map[string]interface{}{
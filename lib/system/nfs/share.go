@@ -80,16 +80,39 @@ func (s *Share) AddACL(acl ExportACL) {
 
 // Add configures and exports the share
 func (s *Share) Add() error {
+	data := map[string]interface{}{
+		"Path":         s.Path,
+		"AccessRights": s.accessRights(),
+	}
+
+	retcode, stdout, stderr, err := executeScript(*s.Server.SSHConfig, "nfs_server_path_export.sh", data)
+	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to export a shared directory")
+}
+
+// Update replaces the ACLs of the share already exported, and re-exports it
+func (s *Share) Update() error {
+	data := map[string]interface{}{
+		"Path":         s.Path,
+		"AccessRights": s.accessRights(),
+	}
+
+	retcode, stdout, stderr, err := executeScript(*s.Server.SSHConfig, "nfs_server_path_update.sh", data)
+	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to update the export of a shared directory")
+}
+
+// accessRights returns the ACLs of the share in the format of /etc/exports
+func (s *Share) accessRights() string {
 	var acls string
 	for _, a := range s.ACLs {
 		acl := a.Host + "("
 		if len(a.SecurityModes) > 0 {
+			// security modes are separated by colons (cf. exports man page)
 			acl += "sec="
 			for i, item := range a.SecurityModes {
-				acl += strings.ToLower(item.String())
 				if i != 0 {
-					acl += ","
+					acl += ":"
 				}
+				acl += strings.ToLower(item.String())
 			}
 		} else {
 			acl += "sec=sys"
@@ -136,11 +159,5 @@ func (s *Share) Add() error {
 
 		acls += acl + " "
 	}
-	data := map[string]interface{}{
-		"Path":         s.Path,
-		"AccessRights": strings.TrimSpace(acls),
-	}
-
-	retcode, stdout, stderr, err := executeScript(*s.Server.SSHConfig, "nfs_server_path_export.sh", data)
-	return handleExecuteScriptReturn(retcode, stdout, stderr, err, "Error executing script to export a shared directory")
+	return strings.TrimSpace(acls)
 }
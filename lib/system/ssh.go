@@ -40,9 +40,11 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
 	"github.com/CS-SI/SafeScale/lib/utils/retry"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
 )
 
 // VPL: SSH ControlMaster options: -oControlMaster=auto -oControlPath=/tmp/safescale-%C -oControlPersist=5m
@@ -343,6 +345,12 @@ func (sc *SSHCommand) Wait() error {
 	return nerr
 }
 
+// Close releases the resources (tunnels, key file) of a command that is not waited for, because it has not been
+// started or failed to start
+func (sc *SSHCommand) Close() error {
+	return sc.cleanup()
+}
+
 // Kill kills SSHCommand process and releases any resources associated with the SSHCommand.
 func (sc *SSHCommand) Kill() error {
 	return sc.cmd.Process.Kill()
@@ -429,11 +437,18 @@ func (sc *SSHCommand) Run(t concurrency.Task, outs outputs.Enum) (int, string, s
 }
 
 // RunWithTimeout ...
-func (sc *SSHCommand) RunWithTimeout(task concurrency.Task, outs outputs.Enum, timeout time.Duration) (int, string, string, error) {
+func (sc *SSHCommand) RunWithTimeout(task concurrency.Task, outs outputs.Enum, timeout time.Duration) (_ int, _ string, _ string, err error) {
 	tracer := concurrency.NewTracer(task, fmt.Sprintf("(%s, %v)", outs.String(), timeout), true).WithStopwatch().GoingIn()
 	tracer.Trace("command=\n%s\n", sc.Display())
 	defer tracer.OnExitTrace()()
 
+	var ctx context.Context
+	if task != nil {
+		ctx = task.GetContext()
+	}
+	_, span := tracing.StartSpan(ctx, "SSHCommand:RunWithTimeout")
+	defer tracing.EndSpan(span, &err)
+
 	// if strings.Contains(sc.Display(), "ENDSSH") {
 	// 	defer utils.NewStopwatch().OnExitLogWithLevel(
 	// 		fmt.Sprintf("Running command with timeout of %s:\n%s", timeout, sc.Display()),
@@ -511,6 +526,7 @@ func (sc *SSHCommand) RunWithTimeout(task concurrency.Task, outs outputs.Enum, t
 	if err != nil {
 		return -1, "", "", err
 	}
+	start := time.Now()
 	_, err = subtask.StartWithTimeout(sc.taskExecute, data.Map{
 		"stdout":          stdoutPipe,
 		"stderr":          stderrPipe,
@@ -521,6 +537,7 @@ func (sc *SSHCommand) RunWithTimeout(task concurrency.Task, outs outputs.Enum, t
 	}
 
 	r, err := subtask.Wait()
+	metrics.ObserveSSHCommand(time.Since(start), err)
 	if err != nil {
 		return -1, "", "", err
 	}
@@ -1077,3 +1094,27 @@ func (ssh *SSHConfig) CommandContext(ctx context.Context, cmdString string) (*SS
 	}
 	return &sshCommand, nil
 }
+
+// StreamCommandContext is like CommandContext, but cmdString is given to ssh as the remote command instead of being
+// written on its standard input, so the standard input of the returned command is the one of cmdString
+func (ssh *SSHConfig) StreamCommandContext(ctx context.Context, cmdString string) (*SSHCommand, error) {
+	tunnels, sshConfig, err := ssh.CreateTunneling()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create command : %s", err.Error())
+	}
+	sshCmdString, keyFile, err := createSSHCmd(sshConfig, "", "", "", false, false)
+	if err != nil {
+		sshCommand := SSHCommand{tunnels: tunnels}
+		_ = sshCommand.closeTunneling()
+		return nil, fmt.Errorf("unable to create command : %s", err.Error())
+	}
+	sshCmdString += " '" + strings.Replace(cmdString, "'", `'\''`, -1) + "'"
+
+	cmd := exec.CommandContext(ctx, "bash", "-c", sshCmdString)
+	sshCommand := SSHCommand{
+		cmd:     cmd,
+		tunnels: tunnels,
+		keyFile: keyFile,
+	}
+	return &sshCommand, nil
+}
@@ -16,6 +16,7 @@
 package cidr
 
 import (
+	"crypto/rand"
 	"fmt"
 	"math/big"
 	"net"
@@ -148,6 +149,54 @@ func VerifyNoOverlap(subnets []*net.IPNet, CIDRBlock *net.IPNet) error {
 	return nil
 }
 
+// Overlaps tells if the 2 networks have at least one address in common
+func Overlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// FirstFreeSubnet returns the first subnet of the desired mask size inside
+// pool not overlapping any of the used subnets
+func FirstFreeSubnet(pool *net.IPNet, prefixLen int, used []*net.IPNet) (*net.IPNet, error) {
+	poolLen, bits := pool.Mask.Size()
+	if prefixLen < poolLen || prefixLen > bits {
+		return nil, fmt.Errorf("cannot allocate a subnet with a prefix of %d inside %s", prefixLen, pool.String())
+	}
+
+	mask := net.CIDRMask(prefixLen, bits)
+	candidate := &net.IPNet{IP: checkIPv4(pool.IP).Mask(mask), Mask: mask}
+	for pool.Contains(candidate.IP) {
+		var conflict *net.IPNet
+		for _, u := range used {
+			if Overlaps(u, candidate) {
+				conflict = u
+				break
+			}
+		}
+		if conflict == nil {
+			return candidate, nil
+		}
+
+		// Continues after the end of the conflicting subnet
+		next, rollover := NextSubnet(conflict, prefixLen)
+		if rollover {
+			break
+		}
+		candidate = next
+	}
+	return nil, fmt.Errorf("no free subnet with a prefix of %d left in %s", prefixLen, pool.String())
+}
+
+// RandomULA returns a random IPv6 unique local /64 prefix, as defined in RFC 4193
+// (fd00::/8 followed by a random 40-bit global ID and the subnet 0)
+func RandomULA() (*net.IPNet, error) {
+	ip := make(net.IP, net.IPv6len)
+	ip[0] = 0xfd
+	if _, err := rand.Read(ip[1:6]); err != nil {
+		return nil, err
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(64, 128)}, nil
+}
+
 // PreviousSubnet returns the subnet of the desired mask in the IP space
 // just lower than the start of IPNet provided. If the IP space rolls over
 // then the second return value is true
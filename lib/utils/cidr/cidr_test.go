@@ -0,0 +1,69 @@
+package cidr
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	require.Nil(t, err)
+	return n
+}
+
+func TestOverlaps(t *testing.T) {
+	assert.True(t, Overlaps(parse(t, "192.168.0.0/16"), parse(t, "192.168.4.0/24")))
+	assert.True(t, Overlaps(parse(t, "192.168.4.0/24"), parse(t, "192.168.0.0/16")))
+	assert.False(t, Overlaps(parse(t, "192.168.4.0/24"), parse(t, "192.168.5.0/24")))
+}
+
+func TestFirstFreeSubnet(t *testing.T) {
+	pool := parse(t, "10.0.0.0/16")
+
+	subnet, err := FirstFreeSubnet(pool, 24, nil)
+	require.Nil(t, err)
+	assert.Equal(t, "10.0.0.0/24", subnet.String())
+
+	used := []*net.IPNet{parse(t, "10.0.0.0/24"), parse(t, "10.0.1.128/25"), parse(t, "10.0.4.0/22")}
+	subnet, err = FirstFreeSubnet(pool, 24, used)
+	require.Nil(t, err)
+	assert.Equal(t, "10.0.2.0/24", subnet.String())
+
+	subnet, err = FirstFreeSubnet(pool, 22, used)
+	require.Nil(t, err)
+	assert.Equal(t, "10.0.8.0/22", subnet.String())
+
+	_, err = FirstFreeSubnet(pool, 16, used)
+	assert.NotNil(t, err)
+	_, err = FirstFreeSubnet(pool, 8, nil)
+	assert.NotNil(t, err)
+}
+
+func TestFirstFreeSubnetIPv6(t *testing.T) {
+	pool := parse(t, "fd00:1::/48")
+
+	subnet, err := FirstFreeSubnet(pool, 64, nil)
+	require.Nil(t, err)
+	assert.Equal(t, "fd00:1::/64", subnet.String())
+
+	used := []*net.IPNet{parse(t, "fd00:1::/64"), parse(t, "fd00:1:0:2::/63")}
+	subnet, err = FirstFreeSubnet(pool, 64, used)
+	require.Nil(t, err)
+	assert.Equal(t, "fd00:1:0:1::/64", subnet.String())
+
+	subnet, err = FirstFreeSubnet(pool, 63, used)
+	require.Nil(t, err)
+	assert.Equal(t, "fd00:1:0:4::/63", subnet.String())
+}
+
+func TestRandomULA(t *testing.T) {
+	ula, err := RandomULA()
+	require.Nil(t, err)
+	ones, bits := ula.Mask.Size()
+	assert.Equal(t, 64, ones)
+	assert.Equal(t, 128, bits)
+	assert.True(t, parse(t, "fd00::/8").Contains(ula.IP))
+}
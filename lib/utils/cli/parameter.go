@@ -22,11 +22,13 @@ import (
 	"strconv"
 	"strings"
 	"text/scanner"
+	"unicode"
 )
 
-// Token describes a token (<keyword> <operator> <value>)
+// Token describes a token (<keyword> <operator> <value>[<unit>])
 type Token struct {
 	members []string
+	unit    string
 
 	pos uint8
 }
@@ -79,9 +81,14 @@ func (t *Token) GetValue() (string, error) {
 	return "", scerr.InvalidRequestError("value is not set in token")
 }
 
+// GetUnit returns the unit following the value of the token (ex: 'mbps' in 'disk-speed >= 200MBps'), in lower case
+func (t *Token) GetUnit() string {
+	return t.unit
+}
+
 // String returns a string representing the token
 func (t *Token) String() string {
-	return strings.Join(t.members, " ")
+	return strings.Join(t.members, " ") + t.unit
 }
 
 // Validate validates value in relation with operator, and returns min and max values if validated
@@ -228,6 +235,16 @@ func ParseParameter(request string) (map[string]*Token, error) {
 			return nil, fmt.Errorf("invalid content '%s' at line %d, column %d", request, p.Line, p.Column)
 		}
 
+		// handles keywords containing '-' (ex: disk-speed)
+		for mytoken.pos == 1 && s.Peek() == '-' {
+			s.Scan()
+			if tok = s.Scan(); tok != scanner.Ident {
+				p := s.Pos()
+				return nil, fmt.Errorf("invalid keyword in '%s' at line %d, column %d", request, p.Line, p.Column)
+			}
+			mytoken.members[0] += "-" + strings.ToLower(s.TokenText())
+		}
+
 		// handles the cases >= or <=
 		if val, err := mytoken.GetOperator(); err == nil && (val == ">" || val == "<") {
 			if tok = s.Scan(); tok != scanner.EOF {
@@ -243,6 +260,11 @@ func ParseParameter(request string) (map[string]*Token, error) {
 			}
 		}
 		if mytoken.IsFull() {
+			// handles the unit stuck to the value (ex: 200MBps)
+			if unicode.IsLetter(s.Peek()) {
+				s.Scan()
+				mytoken.unit = strings.ToLower(s.TokenText())
+			}
 			name, _ := mytoken.GetKeyword()
 			tokens[name] = mytoken
 			mytoken = nil
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseParameter(t *testing.T) {
+	tokens, err := ParseParameter("cpu ~ 4, ram = [14-32], disk-speed>=200MBps, cpufreq>=2.5")
+	require.Nil(t, err)
+	require.Len(t, tokens, 4)
+
+	min, max, err := tokens["cpu"].Validate()
+	assert.Nil(t, err)
+	assert.Equal(t, "4", min)
+	assert.Equal(t, "8", max)
+
+	min, max, err = tokens["ram"].Validate()
+	assert.Nil(t, err)
+	assert.Equal(t, "14", min)
+	assert.Equal(t, "32", max)
+
+	require.Contains(t, tokens, "disk-speed")
+	min, _, err = tokens["disk-speed"].Validate()
+	assert.Nil(t, err)
+	assert.Equal(t, "200", min)
+	assert.Equal(t, "mbps", tokens["disk-speed"].GetUnit())
+
+	min, _, err = tokens["cpufreq"].Validate()
+	assert.Nil(t, err)
+	assert.Equal(t, "2.5", min)
+	assert.Equal(t, "", tokens["cpufreq"].GetUnit())
+}
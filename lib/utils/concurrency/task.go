@@ -24,10 +24,13 @@ import (
 	"time"
 
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+	"github.com/CS-SI/SafeScale/lib/utils/tracing"
 
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 
 	uuid "github.com/satori/go.uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TaskStatus ...
@@ -98,6 +101,7 @@ type task struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 	status TaskStatus
+	span   trace.Span // span of the execution of the action, if the context of the task carries a span
 
 	finishCh chan struct{} // Used to signal the routine that Wait() the go routine is done
 	doneCh   chan bool     // Used by routine to signal it has done its processing
@@ -159,7 +163,7 @@ func newTask(ctx context.Context, parentTask Task) (*task, error) {
 		}
 	} else {
 		pTask := parentTask.(*task)
-		childContext, cancel = context.WithCancel(parentTask.(*task).ctx)
+		childContext, cancel = context.WithCancel(parentTask.GetContext())
 		generation = pTask.generation + 1
 	}
 	t := task{
@@ -207,6 +211,8 @@ func (t *task) GetStatus() TaskStatus {
 
 // GetContext returns the context associated to the task
 func (t *task) GetContext() context.Context {
+	t.lock.Lock()
+	defer t.lock.Unlock()
 	return t.ctx
 }
 
@@ -248,6 +254,8 @@ func (t *task) StartWithTimeout(action TaskAction, params TaskParameters, timeou
 		t.status = DONE
 	} else {
 		t.status = RUNNING
+		// The action and its subtasks are traced as children of the span of the task
+		t.ctx, t.span = tracing.StartChildSpan(t.ctx, "Task", attribute.String("task.id", tid))
 		t.doneCh = make(chan bool, 1)
 		t.abortCh = make(chan struct{}, 1)
 		t.finishCh = make(chan struct{}, 1)
@@ -349,6 +357,7 @@ func (t *task) run(action TaskAction, params TaskParameters) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	tracing.EndSpan(t.span, &err)
 	t.err = err
 	t.result = result
 	t.doneCh <- true
@@ -89,15 +89,30 @@ func LongToIPv4(value uint32) string {
 	return fmt.Sprintf("%d.%d.%d.%d", value>>24, (value&0x00FFFFFF)>>16, (value&0x0000FFFF)>>8, value&0x000000FF)
 }
 
-// IsCIDRRoutable tells if the network is routable
+// BracketIPv6 encloses an IPv6 address in brackets, as expected in front of a port or a path; other values are
+// returned unchanged
+func BracketIPv6(address string) string {
+	ip := net.ParseIP(address)
+	if ip == nil || ip.To4() != nil {
+		return address
+	}
+	return "[" + address + "]"
+}
+
+// IsCIDRRoutable tells if the network is routable (IPv4 or IPv6)
 func IsCIDRRoutable(cidr string) (bool, error) {
-	first, last, err := CIDRToIPv4Range(cidr)
+	if cidr == "" {
+		return false, scerr.InvalidParameterError("cidr", "cannot be empty string")
+	}
+	_, ipnet, err := net.ParseCIDR(cidr)
 	if err != nil {
-		return false, err
+		return false, scerr.InvalidParameterError("cidr", "Not a valid CIDR")
+	}
+	firstIP := ipnet.IP
+	lastIP := make(net.IP, len(firstIP))
+	for i := range firstIP {
+		lastIP[i] = firstIP[i] | ^ipnet.Mask[i]
 	}
-	splitted := strings.Split(cidr, "/")
-	firstIP, _, _ := net.ParseCIDR(first + "/" + splitted[1])
-	lastIP, _, _ := net.ParseCIDR(last + "/" + splitted[1])
 	for _, nr := range networks {
 		if nr.Contains(firstIP) && nr.Contains(lastIP) {
 			return false, nil
@@ -107,7 +122,7 @@ func IsCIDRRoutable(cidr string) (bool, error) {
 }
 
 func init() {
-	notRoutables := []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}
+	notRoutables := []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7"}
 
 	for _, n := range notRoutables {
 		_, ipnet, _ := net.ParseCIDR(n)
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package utils
+
+import (
+	"testing"
+)
+
+func TestIsCIDRRoutable(t *testing.T) {
+	tests := []struct {
+		name    string
+		cidr    string
+		want    bool
+		wantErr bool
+	}{
+		{"private ipv4", "192.168.1.0/24", false, false},
+		{"private ipv4 overlapping", "172.0.0.0/8", true, false},
+		{"public ipv4", "8.8.8.0/24", true, false},
+		{"unique local ipv6", "fd00:1234::/64", false, false},
+		{"global ipv6", "2001:db8::/32", true, false},
+		{"invalid", "not-a-cidr", false, true},
+		{"empty", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsCIDRRoutable(tt.cidr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsCIDRRoutable() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsCIDRRoutable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBracketIPv6(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    string
+	}{
+		{"ipv4", "192.168.1.10", "192.168.1.10"},
+		{"ipv6", "fd00:1234::10", "[fd00:1234::10]"},
+		{"hostname", "gw-net1", "gw-net1"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BracketIPv6(tt.address); got != tt.want {
+				t.Errorf("BracketIPv6() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
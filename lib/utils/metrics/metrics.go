@@ -0,0 +1,148 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics exposes safescaled operational metrics in Prometheus format
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+const namespace = "safescale"
+
+var (
+	grpcRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "Number of gRPC requests handled, by service, method and status code",
+		},
+		[]string{"service", "method", "code"},
+	)
+	grpcDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Duration of gRPC requests, by service and method",
+			Buckets:   []float64{0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600, 1200},
+		},
+		[]string{"service", "method"},
+	)
+	providerCalls = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "provider_calls_total",
+			Help:      "Number of calls to the cloud provider, by provider and method",
+		},
+		[]string{"provider", "method"},
+	)
+	providerErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "provider_errors_total",
+			Help:      "Number of failed calls to the cloud provider, by provider and method",
+		},
+		[]string{"provider", "method"},
+	)
+	providerDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "provider_call_duration_seconds",
+			Help:      "Duration of calls to the cloud provider, by provider and method",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"provider", "method"},
+	)
+	retries = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Number of retries done by the retry engine",
+		},
+	)
+	sshDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "ssh_command_duration_seconds",
+			Help:      "Duration of commands run through SSH, by outcome",
+			Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600},
+		},
+		[]string{"outcome"},
+	)
+	activeJobs = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "active_jobs",
+			Help:      "Number of jobs currently running",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(grpcRequests, grpcDuration, providerCalls, providerErrors, providerDuration, retries, sshDuration, activeJobs)
+}
+
+// ObserveGRPCRequest records a gRPC request that has been handled
+func ObserveGRPCRequest(service, method, code string, duration time.Duration) {
+	grpcRequests.WithLabelValues(service, method, code).Inc()
+	grpcDuration.WithLabelValues(service, method).Observe(duration.Seconds())
+}
+
+// ObserveProviderCall records a call to the provider started at 'start'; meant to be deferred
+func ObserveProviderCall(provider, method string, start time.Time, err *error) {
+	providerCalls.WithLabelValues(provider, method).Inc()
+	providerDuration.WithLabelValues(provider, method).Observe(time.Since(start).Seconds())
+	if err != nil && *err != nil {
+		providerErrors.WithLabelValues(provider, method).Inc()
+	}
+}
+
+// IncRetries counts a retry
+func IncRetries() {
+	retries.Inc()
+}
+
+// ObserveSSHCommand records the duration of a command run through SSH
+func ObserveSSHCommand(duration time.Duration, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	sshDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+}
+
+// SetActiveJobs updates the number of jobs currently running
+func SetActiveJobs(count int) {
+	activeJobs.Set(float64(count))
+}
+
+// Serve starts an HTTP server exposing the metrics on '<address>/metrics'
+// Returns immediately, the server runs in a goroutine
+func Serve(address string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		logrus.Infof("Metrics available on http://%s/metrics", address)
+		if err := http.ListenAndServe(address, mux); err != nil {
+			logrus.Errorf("metrics endpoint stopped: %v", err)
+		}
+	}()
+}
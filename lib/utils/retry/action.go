@@ -24,6 +24,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/CS-SI/SafeScale/lib/utils/metrics"
 	"github.com/CS-SI/SafeScale/lib/utils/retry/enums/verdict"
 	"github.com/CS-SI/SafeScale/lib/utils/scerr"
 )
@@ -369,6 +370,7 @@ func (a action) loop() error {
 			}
 			return retryErr
 		default:
+			metrics.IncRetries()
 			// Retry is wanted, so blocks the loop the amount of time needed
 			if a.Officer != nil {
 				a.Officer.Block(try)
@@ -449,6 +451,7 @@ func (a action) loopWithTimeout(timeout time.Duration) error {
 			}
 			return retryErr
 		default:
+			metrics.IncRetries()
 			// Retry is wanted, so blocks the loop the amount of time needed
 			if a.Officer != nil {
 				go func() {
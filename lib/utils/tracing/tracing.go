@@ -0,0 +1,140 @@
+/*
+ * Copyright 2018-2020, CS Systemes d'Information, http://www.c-s.fr
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package tracing exports OpenTelemetry spans of safescaled
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/CS-SI/SafeScale/lib/utils/scerr"
+)
+
+const tracerName = "github.com/CS-SI/SafeScale"
+
+// Init configures the export of the spans following 'spec', which is either:
+//   - "otlp[:<endpoint>]" to send spans to an OTLP collector using gRPC (default endpoint is localhost:4317)
+//   - "file:<path>" to append spans in JSON format to a local file
+//
+// Returns a function to call on exit to flush the pending spans
+func Init(spec string, serviceName string) (func(), error) {
+	kind, target := spec, ""
+	if idx := strings.Index(spec, ":"); idx >= 0 {
+		kind, target = spec[:idx], spec[idx+1:]
+	}
+
+	var (
+		exporter sdktrace.SpanExporter
+		closer   = func() {}
+		err      error
+	)
+	switch strings.ToLower(kind) {
+	case "otlp":
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithInsecure()}
+		if target != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(target))
+		}
+		exporter, err = otlptracegrpc.New(context.Background(), opts...)
+		if err != nil {
+			return nil, err
+		}
+	case "file":
+		if target == "" {
+			return nil, scerr.InvalidParameterError("spec", "missing path of the file exporter")
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, err
+		}
+		closer = func() { _ = file.Close() }
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			closer()
+			return nil, err
+		}
+	default:
+		return nil, scerr.InvalidParameterError("spec", fmt.Sprintf("unknown exporter '%s', can be 'otlp' or 'file'", kind))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func() {
+		_ = provider.Shutdown(context.Background())
+		closer()
+	}, nil
+}
+
+// StartSpan starts a span named 'name', child of the span carried by 'ctx' if there is one
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartChildSpan starts a span named 'name' only if 'ctx' carries a span, to avoid creating root spans for
+// background work; returns 'ctx' unchanged and a nil span otherwise
+func StartChildSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, nil
+	}
+	return StartSpan(ctx, name, attrs...)
+}
+
+// WithSpanOf returns a copy of 'ctx' carrying the span carried by 'from', to trace work detached from the
+// context of a request (like jobs run in background) as children of the span of the request
+func WithSpanOf(ctx context.Context, from context.Context) context.Context {
+	if from == nil {
+		return ctx
+	}
+	spanContext := trace.SpanContextFromContext(from)
+	if !spanContext.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithSpanContext(ctx, spanContext)
+}
+
+// EndSpan ends 'span', recording the error pointed by 'err' if any; meant to be deferred
+// Does nothing if 'span' is nil
+func EndSpan(span trace.Span, err *error) {
+	if span == nil {
+		return
+	}
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}